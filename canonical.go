@@ -0,0 +1,176 @@
+package iabconsent
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Canonicalize parses consent string s, a TCF v1.1, TCF v2, GPP or US Privacy string, and returns
+// it re-encoded in its canonical form: the smallest valid encoding of the same consent. Strings which
+// hold equal consents (see the Equal methods) have the same canonical form, so it can be used to
+// deduplicate strings that only differ in their encoding.
+//
+// GPP strings with sections that cannot be parsed are not canonicalized, as re-encoding them would
+// drop those sections.
+func Canonicalize(s string) (string, error) {
+	if len(s) == UsPrivacyStringLength && s[0] == '1' {
+		var p, err = ParseUsPrivacy(s)
+		if err != nil {
+			return "", errors.Wrap(err, "parse us_privacy")
+		}
+		return p.Encode()
+	}
+
+	var version = -1
+	if s != "" {
+		version = strings.IndexByte(base64URLAlphabet, s[0])
+	}
+	switch version {
+	case int(V1):
+		var p, err = ParseV1(s)
+		if err != nil {
+			return "", errors.Wrap(err, "parse tcfv1")
+		}
+		return EncodeV1(CanonicalV1(p))
+	case int(V2):
+		var p, err = ParseV2(s)
+		if err != nil {
+			return "", errors.Wrap(err, "parse tcfv2")
+		}
+		return EncodeV2(CanonicalV2(p))
+	case 3:
+		var g, err = ParseGppConsent(s)
+		if err != nil {
+			return "", errors.Wrap(err, "parse gpp")
+		}
+		var sections = make(map[int]GppParsedConsent, len(g.Sections))
+		for _, sid := range g.Header.Sections {
+			switch p := g.Sections[sid].(type) {
+			case *V2ParsedConsent:
+				sections[sid] = CanonicalV2(p)
+			case nil:
+				return "", errors.Errorf("gpp section %d could not be parsed", sid)
+			default:
+				sections[sid] = p
+			}
+		}
+		return EncodeGppConsent(&GppConsent{Sections: sections})
+	default:
+		return "", errors.New("unrecognized consent string")
+	}
+}
+
+// CanonicalV1 returns a copy of p with its vendors in whichever of the bit field, range and default
+// consent range encodings is smallest. MaxVendorID is kept, as the vendors of a default consent range
+// encoding depend on it.
+func CanonicalV1(p *ParsedConsent) *ParsedConsent {
+	var c = *p
+	c.PurposesAllowed = copySet(p.PurposesAllowed)
+
+	var consentedRanges = v1VendorRanges(p)
+	var notConsentedRanges = subtractRanges(v1AllVendors(p), consentedRanges)
+	// A range section holds a 1 bit default consent and a 12 bit count followed by its entries.
+	var consentedBits, notConsentedBits = 13 + rangeEntriesBits(consentedRanges), 13 + rangeEntriesBits(notConsentedRanges)
+
+	c.IsRangeEncoding, c.DefaultConsent, c.ConsentedVendors, c.RangeEntries = false, false, nil, nil
+	switch {
+	case p.MaxVendorID <= consentedBits && p.MaxVendorID <= notConsentedBits:
+		// The bit field is no larger than the range sections, so it can be built from them.
+		c.ConsentedVendors = bitFieldOf(consentedRanges)
+	case consentedBits <= notConsentedBits:
+		c.IsRangeEncoding = true
+		c.RangeEntries = consentedRanges
+	default:
+		c.IsRangeEncoding = true
+		c.DefaultConsent = true
+		c.RangeEntries = notConsentedRanges
+	}
+	c.NumEntries = len(c.RangeEntries)
+	return &c
+}
+
+// CanonicalV2 returns a copy of p in its smallest encoding: the consented and legitimate interest
+// vendors, and the DisclosedVendors and AllowedVendors segments, are written with whichever of the bit
+// field and range encodings is smaller, with their MaxVendorID set to their highest vendor, and
+// publisher restrictions are grouped into one entry per purpose and restriction type, ordered by
+// purpose then type, with the fewest ranges.
+func CanonicalV2(p *V2ParsedConsent) *V2ParsedConsent {
+	var c = *p
+	c.SpecialFeaturesOptIn = copySet(p.SpecialFeaturesOptIn)
+	c.PurposesConsent = copySet(p.PurposesConsent)
+	c.PurposesLITransparency = copySet(p.PurposesLITransparency)
+
+	var ranges = vendorRanges(p.IsConsentRangeEncoding, p.ConsentedVendors, p.ConsentedVendorsRange)
+	c.MaxConsentVendorID, c.IsConsentRangeEncoding = vendorEncoding(ranges)
+	c.ConsentedVendors, c.NumConsentEntries, c.ConsentedVendorsRange = nil, len(ranges), ranges
+	if !c.IsConsentRangeEncoding {
+		c.ConsentedVendors, c.NumConsentEntries, c.ConsentedVendorsRange = bitFieldOf(ranges), 0, nil
+	}
+
+	ranges = vendorRanges(p.IsInterestsRangeEncoding, p.InterestsVendors, p.InterestsVendorsRange)
+	c.MaxInterestsVendorID, c.IsInterestsRangeEncoding = vendorEncoding(ranges)
+	c.InterestsVendors, c.NumInterestsEntries, c.InterestsVendorsRange = nil, len(ranges), ranges
+	if !c.IsInterestsRangeEncoding {
+		c.InterestsVendors, c.NumInterestsEntries, c.InterestsVendorsRange = bitFieldOf(ranges), 0, nil
+	}
+
+	c.PubRestrictionEntries = canonicalRestrictions(p.PubRestrictionEntries)
+	c.NumPubRestrictions = len(c.PubRestrictionEntries)
+
+	if p.OOBDisclosedVendors != nil {
+		c.OOBDisclosedVendors = newOOBVendorList(DisclosedVendors, oobVendorRanges(p.OOBDisclosedVendors))
+	}
+	if p.OOBAllowedVendors != nil {
+		c.OOBAllowedVendors = newOOBVendorList(AllowedVendors, oobVendorRanges(p.OOBAllowedVendors))
+	}
+	if p.PublisherTCEntry != nil {
+		var ptc = *p.PublisherTCEntry
+		ptc.PubPurposesConsent = copySet(ptc.PubPurposesConsent)
+		ptc.PubPurposesLITransparency = copySet(ptc.PubPurposesLITransparency)
+		ptc.CustomPurposesConsent = copySet(ptc.CustomPurposesConsent)
+		ptc.CustomPurposesLITransparency = copySet(ptc.CustomPurposesLITransparency)
+		c.PublisherTCEntry = &ptc
+	}
+	return &c
+}
+
+// canonicalRestrictions regroups entries into one entry per purpose and restriction type, ordered by
+// purpose then type, with the fewest ranges.
+func canonicalRestrictions(entries []*PubRestrictionEntry) []*PubRestrictionEntry {
+	var ranges = restrictionRanges(entries)
+	var groups = make([]restrictionGroup, 0, len(ranges))
+	for g := range ranges {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].purpose != groups[j].purpose {
+			return groups[i].purpose < groups[j].purpose
+		}
+		return groups[i].t < groups[j].t
+	})
+
+	var canonical = make([]*PubRestrictionEntry, 0, len(groups))
+	for _, g := range groups {
+		canonical = append(canonical, &PubRestrictionEntry{
+			PurposeID:         g.purpose,
+			RestrictionType:   g.t,
+			NumEntries:        len(ranges[g]),
+			RestrictionsRange: ranges[g],
+		})
+	}
+	return canonical
+}
+
+// copySet returns a copy of the IDs set in ids, such as purposes or special features, without those
+// mapped to false.
+func copySet(ids map[int]bool) map[int]bool {
+	var c = make(map[int]bool, len(ids))
+	for id, ok := range ids {
+		if ok {
+			c[id] = true
+		}
+	}
+	return c
+}
@@ -0,0 +1,207 @@
+package iabconsent_test
+
+import (
+	"time"
+
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type CanonicalSuite struct{}
+
+var _ = check.Suite(&CanonicalSuite{})
+
+func (s *CanonicalSuite) TestCanonicalize(c *check.C) {
+	var tcs = []struct {
+		desc     string
+		consent  string
+		expected string
+	}{
+		{
+			desc:     "TCF v2 range encoded vendors, smaller as a bit field.",
+			consent:  "COvzTO5OvzTO5BRAAAENAPCoALIAADgAAAAAAewAwABAAlAB6ABBFAAA",
+			expected: "COvzTO5OvzTO5BRAAAENAPCoALIAADgAAAAAAekAAAAAIAAAIAEEUAA",
+		},
+		{
+			desc:     "TCF v2 already canonical.",
+			consent:  "COvzTO5OvzTO5B7ABCENAPCYAKdAADkAAIqIFhwBAAGAAXAFGAsMAhYAgAMAAegBYAEKAAA",
+			expected: "COvzTO5OvzTO5B7ABCENAPCYAKdAADkAAIqIFhwBAAGAAXAFGAsMAhYAgAMAAegBYAEKAAA",
+		},
+		{
+			desc:     "TCF v1.1.",
+			consent:  "BONJ5bvONJ5bvAMAPyFRAL7AAAAMhuqKklS-gAAAAAAAAAAAAAAAAAAAAAAAAAA",
+			expected: "BONJ5bvONJ5bvAMAPyFRAL7AAAAMhuqKklS-gAAAAAAAAAAAAAAAAAAAAAAAAAA",
+		},
+		{
+			desc:     "GPP with a false GPC subsection.",
+			consent:  "DBABLA~BVVqAAEABCA.QA",
+			expected: "DBABLA~BVVqAAEABCA",
+		},
+		{
+			desc:     "Lower case US Privacy.",
+			consent:  "1ynn",
+			expected: "1YNN",
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		var canonical, err = iabconsent.Canonicalize(tc.consent)
+		c.Check(err, check.IsNil)
+		c.Check(canonical, check.Equals, tc.expected)
+
+		// Canonicalizing is idempotent.
+		var again string
+		again, err = iabconsent.Canonicalize(canonical)
+		c.Check(err, check.IsNil)
+		c.Check(again, check.Equals, canonical)
+	}
+}
+
+func (s *CanonicalSuite) TestCanonicalizeErrors(c *check.C) {
+	var tcs = []struct {
+		desc    string
+		consent string
+		err     string
+	}{
+		{
+			desc:    "Empty.",
+			consent: "",
+			err:     "unrecognized consent string",
+		},
+		{
+			desc:    "Invalid US Privacy.",
+			consent: "1XNN",
+			err:     "parse us_privacy: .*",
+		},
+		{
+			desc:    "Invalid TCF v2.",
+			consent: "C",
+			err:     "parse tcfv2: .*",
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		var _, err = iabconsent.Canonicalize(tc.consent)
+		c.Check(err, check.ErrorMatches, tc.err)
+	}
+}
+
+func (s *CanonicalSuite) TestCanonicalV2(c *check.C) {
+	var t1 = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var p = mergeConsent(t1, map[int]bool{1: true, 2: true}, nil)
+	p.IsInterestsRangeEncoding = true
+	p.MaxInterestsVendorID = 800
+	p.InterestsVendorsRange = []*iabconsent.RangeEntry{
+		{StartVendorID: 700, EndVendorID: 800},
+		{StartVendorID: 600, EndVendorID: 650},
+	}
+	p.PubRestrictionEntries = []*iabconsent.PubRestrictionEntry{
+		{
+			PurposeID:         3,
+			RestrictionType:   iabconsent.RequireConsent,
+			RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 5, EndVendorID: 5}},
+		},
+		{
+			PurposeID:         2,
+			RestrictionType:   iabconsent.PurposeFlatlyNotAllowed,
+			RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 2, EndVendorID: 2}},
+		},
+		{
+			PurposeID:         3,
+			RestrictionType:   iabconsent.RequireConsent,
+			RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 4, EndVendorID: 4}},
+		},
+	}
+
+	var canonical = iabconsent.CanonicalV2(p)
+	c.Check(canonical.Equal(p), check.Equals, true)
+	c.Check(canonical.MaxConsentVendorID, check.Equals, 2)
+	c.Check(canonical.IsConsentRangeEncoding, check.Equals, false)
+	c.Check(canonical.InterestsVendorsRange, check.DeepEquals, []*iabconsent.RangeEntry{
+		{StartVendorID: 600, EndVendorID: 650},
+		{StartVendorID: 700, EndVendorID: 800},
+	})
+	c.Check(canonical.PubRestrictionEntries, check.DeepEquals, []*iabconsent.PubRestrictionEntry{
+		{
+			PurposeID:         2,
+			RestrictionType:   iabconsent.PurposeFlatlyNotAllowed,
+			NumEntries:        1,
+			RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 2, EndVendorID: 2}},
+		},
+		{
+			PurposeID:         3,
+			RestrictionType:   iabconsent.RequireConsent,
+			NumEntries:        1,
+			RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 4, EndVendorID: 5}},
+		},
+	})
+	// p is not modified.
+	c.Check(p.PubRestrictionEntries, check.HasLen, 3)
+}
+
+func (s *CanonicalSuite) TestCanonicalV2LargeRanges(c *check.C) {
+	var t1 = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var p = mergeConsent(t1, nil, nil)
+	p.IsConsentRangeEncoding = true
+	p.MaxConsentVendorID = 65535
+	p.ConsentedVendorsRange = []*iabconsent.RangeEntry{
+		{StartVendorID: 10, EndVendorID: 65535},
+		nil,
+		{StartVendorID: 1, EndVendorID: 65535},
+	}
+	p.PubRestrictionEntries = []*iabconsent.PubRestrictionEntry{
+		{
+			PurposeID:       2,
+			RestrictionType: iabconsent.RequireConsent,
+			RestrictionsRange: []*iabconsent.RangeEntry{
+				{StartVendorID: 1, EndVendorID: 65535},
+				{StartVendorID: 1, EndVendorID: 65535},
+			},
+		},
+		nil,
+	}
+
+	var canonical = iabconsent.CanonicalV2(p)
+	c.Check(canonical.Equal(p), check.Equals, true)
+	c.Check(canonical.IsConsentRangeEncoding, check.Equals, true)
+	c.Check(canonical.ConsentedVendorsRange, check.DeepEquals, []*iabconsent.RangeEntry{{StartVendorID: 1, EndVendorID: 65535}})
+	c.Check(canonical.PubRestrictionEntries, check.DeepEquals, []*iabconsent.PubRestrictionEntry{
+		{
+			PurposeID:         2,
+			RestrictionType:   iabconsent.RequireConsent,
+			NumEntries:        1,
+			RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 1, EndVendorID: 65535}},
+		},
+	})
+}
+
+func (s *CanonicalSuite) TestCanonicalV1(c *check.C) {
+	// Every vendor but one is consented, which is smallest as a default consent range.
+	var ids = make(map[int]bool)
+	for v := 1; v <= 500; v++ {
+		ids[v] = v != 250
+	}
+	var t1 = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var p = &iabconsent.ParsedConsent{
+		Version:          1,
+		Created:          t1,
+		LastUpdated:      t1,
+		ConsentLanguage:  "EN",
+		MaxVendorID:      500,
+		PurposesAllowed:  map[int]bool{1: true},
+		ConsentedVendors: ids,
+	}
+	var canonical = iabconsent.CanonicalV1(p)
+	c.Check(canonical.Equal(p), check.Equals, true)
+	c.Check(canonical.IsRangeEncoding, check.Equals, true)
+	c.Check(canonical.DefaultConsent, check.Equals, true)
+	c.Check(canonical.RangeEntries, check.DeepEquals, []*iabconsent.RangeEntry{{StartVendorID: 250, EndVendorID: 250}})
+
+	var encoded, err = iabconsent.EncodeV1(canonical)
+	c.Assert(err, check.IsNil)
+	var parsed *iabconsent.ParsedConsent
+	parsed, err = iabconsent.ParseV1(encoded)
+	c.Assert(err, check.IsNil)
+	c.Check(parsed.Equal(p), check.Equals, true)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// maxLineLength bounds the length of a consent string read from a file or stdin.
+const maxLineLength = 1 << 20
+
+func decodeCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var fs = flag.NewFlagSet("decode", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var output = fs.String("output", "text", "output format, text or json")
+	var file = fs.String("file", "", "read newline-delimited consent strings from `path`, or stdin if -")
+	var bits = fs.Bool("bits", false, "list every field with its bit offset, width and raw bits, even if the string fails to parse")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: iabconsent decode [-output text|json] [-bits] [-file path] [consent string ...]")
+		fmt.Fprintln(stderr, "Consent strings are read from stdin if none are given.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(stderr, "iabconsent: unknown output format %q\n", *output)
+		return 2
+	}
+
+	var code = 0
+	var decodeOne = func(s string) {
+		var err error
+		if *bits {
+			var d *dumped
+			if d, err = dump(s); d != nil {
+				if werr := writeDumped(stdout, d, *output); werr != nil {
+					err = werr
+				}
+			}
+		} else {
+			var d *decoded
+			if d, err = decode(s); err == nil {
+				err = writeDecoded(stdout, d, *output)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "iabconsent: %s: %v\n", s, err)
+			code = 1
+		}
+	}
+
+	for _, s := range fs.Args() {
+		decodeOne(strings.TrimSpace(s))
+	}
+
+	var r io.Reader
+	switch {
+	case *file == "-":
+		r = stdin
+	case *file != "":
+		var f, err = os.Open(*file)
+		if err != nil {
+			fmt.Fprintf(stderr, "iabconsent: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		r = f
+	case fs.NArg() == 0:
+		r = stdin
+	}
+	if r != nil {
+		var sc = bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 0, 64*1024), maxLineLength)
+		for sc.Scan() {
+			if s := strings.TrimSpace(sc.Text()); s != "" {
+				decodeOne(s)
+			}
+		}
+		if err := sc.Err(); err != nil {
+			fmt.Fprintf(stderr, "iabconsent: read input: %v\n", err)
+			code = 1
+		}
+	}
+	return code
+}
+
+// writeDecoded writes d to w as a single line of JSON, or as indented text followed by a blank line.
+func writeDecoded(w io.Writer, d *decoded, output string) error {
+	var b, err = json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	if output == "json" {
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	}
+	return writeText(w, b)
+}
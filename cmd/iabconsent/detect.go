@@ -0,0 +1,68 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+// The formats of consent string that can be decoded.
+const (
+	formatUsPrivacy = "us_privacy"
+	formatGpp       = "gpp"
+	formatTcfV1     = "tcfv1"
+	formatTcfV2     = "tcfv2"
+)
+
+var usPrivacyPattern = regexp.MustCompile(`^1[YNyn-]{3}$`)
+
+// decoded is a consent string together with its detected format and parsed consent.
+type decoded struct {
+	Format  string      `json:"format"`
+	String  string      `json:"string"`
+	Consent interface{} `json:"consent"`
+}
+
+// detectFormat returns the format of consent string s, checking in turn for a US Privacy
+// string, a GPP header, and a TCF version.
+func detectFormat(s string) (string, error) {
+	if usPrivacyPattern.MatchString(s) {
+		return formatUsPrivacy, nil
+	}
+	if _, err := iabconsent.ParseGppHeader(strings.SplitN(s, "~", 2)[0]); err == nil {
+		return formatGpp, nil
+	}
+	switch iabconsent.TCFVersionFromTCString(s) {
+	case iabconsent.V1:
+		return formatTcfV1, nil
+	case iabconsent.V2:
+		return formatTcfV2, nil
+	}
+	return "", errors.New("unrecognized consent string")
+}
+
+// decode detects the format of consent string s and parses it.
+func decode(s string) (*decoded, error) {
+	var format, err = detectFormat(s)
+	if err != nil {
+		return nil, err
+	}
+	var d = &decoded{Format: format, String: s}
+	switch format {
+	case formatUsPrivacy:
+		d.Consent, err = iabconsent.ParseUsPrivacy(s)
+	case formatGpp:
+		d.Consent, err = iabconsent.ParseGppConsent(s)
+	case formatTcfV1:
+		d.Consent, err = iabconsent.ParseV1(s)
+	case formatTcfV2:
+		d.Consent, err = iabconsent.ParseV2(s)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "parse "+format)
+	}
+	return d, nil
+}
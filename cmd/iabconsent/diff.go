@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+// diffed is the list of changes between two consent strings of the same format.
+type diffed struct {
+	Format  string              `json:"format"`
+	Old     string              `json:"old"`
+	New     string              `json:"new"`
+	Changes []iabconsent.Change `json:"changes"`
+}
+
+func diffCommand(args []string, stdout, stderr io.Writer) int {
+	var fs = flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var output = fs.String("output", "text", "output format, text or json")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: iabconsent diff [-output text|json] old new")
+		fmt.Fprintln(stderr, "Lists the changes from the old consent string to the new one, which must be of the same format.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *output != "text" && *output != "json" {
+		fmt.Fprintf(stderr, "iabconsent: unknown output format %q\n", *output)
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	var d, err = diff(fs.Arg(0), fs.Arg(1))
+	if err == nil {
+		err = writeDiffed(stdout, d, *output)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "iabconsent: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// diff decodes consent strings old and new, and returns the changes between them.
+func diff(old, new string) (*diffed, error) {
+	var a, err = decode(old)
+	if err != nil {
+		return nil, errors.Wrap(err, "old")
+	}
+	var b *decoded
+	if b, err = decode(new); err != nil {
+		return nil, errors.Wrap(err, "new")
+	}
+	if a.Format != b.Format {
+		return nil, errors.Errorf("cannot diff %s against %s", a.Format, b.Format)
+	}
+
+	var d = &diffed{Format: a.Format, Old: old, New: new}
+	switch a.Format {
+	case formatTcfV2:
+		d.Changes = iabconsent.DiffV2(a.Consent.(*iabconsent.V2ParsedConsent), b.Consent.(*iabconsent.V2ParsedConsent))
+	case formatGpp:
+		d.Changes = diffGpp(a.Consent.(*iabconsent.GppConsent), b.Consent.(*iabconsent.GppConsent))
+	case formatUsPrivacy:
+		// US Privacy strings are compared as the US National sections they map to.
+		var ma, mb *iabconsent.MspaParsedConsent
+		if ma, err = iabconsent.UsPrivacyToUsNational(old); err != nil {
+			return nil, errors.Wrap(err, "old")
+		}
+		if mb, err = iabconsent.UsPrivacyToUsNational(new); err != nil {
+			return nil, errors.Wrap(err, "new")
+		}
+		d.Changes = iabconsent.DiffMspa(ma, mb)
+	default:
+		return nil, errors.Errorf("diffing %s is not supported", a.Format)
+	}
+	if d.Changes == nil {
+		d.Changes = []iabconsent.Change{}
+	}
+	return d, nil
+}
+
+// diffGpp returns the changes between the sections of a and b, in Section ID order. The fields of
+// each section are prefixed with its name, e.g. "usnat.sale_opt_out", and sections present in only
+// one of a and b are added or removed.
+func diffGpp(a, b *iabconsent.GppConsent) []iabconsent.Change {
+	var sids []int
+	for sid := range a.Sections {
+		sids = append(sids, sid)
+	}
+	for sid := range b.Sections {
+		if a.Section(sid) == nil {
+			sids = append(sids, sid)
+		}
+	}
+	sort.Ints(sids)
+
+	var changes []iabconsent.Change
+	for _, sid := range sids {
+		var name = iabconsent.GppSectionName(sid)
+		var sa, sb = a.Section(sid), b.Section(sid)
+		var sectionChanges []iabconsent.Change
+		switch {
+		case sa == nil:
+			changes = append(changes, iabconsent.Change{Kind: iabconsent.ChangeAdded, Field: name})
+			continue
+		case sb == nil:
+			changes = append(changes, iabconsent.Change{Kind: iabconsent.ChangeRemoved, Field: name})
+			continue
+		case sid == iabconsent.TcfEuV2SID:
+			sectionChanges = iabconsent.DiffV2(a.TcfEuV2(), b.TcfEuV2())
+		default:
+			sectionChanges = iabconsent.DiffMspa(a.Mspa(sid), b.Mspa(sid))
+		}
+		for _, ch := range sectionChanges {
+			ch.Field = name + "." + ch.Field
+			changes = append(changes, ch)
+		}
+	}
+	return changes
+}
+
+// writeDiffed writes d to w as a single line of JSON, or as one change per line.
+func writeDiffed(w io.Writer, d *diffed, output string) error {
+	if output == "json" {
+		var b, err = json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	}
+	for _, ch := range d.Changes {
+		if _, err := fmt.Fprintln(w, ch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"github.com/go-check/check"
+)
+
+func (s *CommandSuite) TestDiff(c *check.C) {
+	var tcs = []struct {
+		desc     string
+		args     []string
+		code     int
+		expected string
+		stderr   string
+	}{
+		{
+			desc:     "US Privacy opt out.",
+			args:     []string{"diff", "1YNN", "1YYN"},
+			expected: "sale_opt_out: not_opted_out -> opted_out\n",
+		},
+		{
+			desc:     "GPP section field.",
+			args:     []string{"diff", "DBABLA~BVVqAAEABCA.YA", "DBABLA~BVVqAAEABCA.QA"},
+			expected: "usnat.gpc: true -> false\n",
+		},
+		{
+			desc:     "No changes as JSON.",
+			args:     []string{"diff", "-output", "json", "1YNN", "1YNN"},
+			expected: `{"format":"us_privacy","old":"1YNN","new":"1YNN","changes":[]}` + "\n",
+		},
+		{
+			desc:   "Mismatched formats.",
+			args:   []string{"diff", "1YNN", "DBABLA~BVVqAAEABCA"},
+			code:   1,
+			stderr: "iabconsent: cannot diff us_privacy against gpp\n",
+		},
+		{
+			desc:   "Invalid string.",
+			args:   []string{"diff", "1YNN", "1XNN"},
+			code:   1,
+			stderr: "iabconsent: new: .*\n",
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		var code, stdout, stderr = runCommand("", tc.args...)
+		c.Check(code, check.Equals, tc.code)
+		c.Check(stdout, check.Equals, tc.expected)
+		c.Check(stderr, check.Matches, tc.stderr)
+	}
+}
+
+func (s *CommandSuite) TestDiffUsage(c *check.C) {
+	var code, _, stderr = runCommand("", "diff", "1YNN")
+	c.Check(code, check.Equals, 2)
+	c.Check(stderr, check.Matches, "Usage: iabconsent diff (.|\n)*")
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+// dumped is a consent string together with its bit-level dump.
+type dumped struct {
+	String string                  `json:"string"`
+	Dump   *iabconsent.ConsentDump `json:"dump"`
+	Error  string                  `json:"error,omitempty"`
+}
+
+// dump returns the bit-level dump of consent string s. The dump is returned along with any error,
+// so strings which fail to parse can be inspected up to the point of failure.
+func dump(s string) (*dumped, error) {
+	if usPrivacyPattern.MatchString(s) {
+		return nil, errors.New("us_privacy strings are not bit encoded")
+	}
+	var cd, err = iabconsent.Dump(s)
+	var d = &dumped{String: s, Dump: cd}
+	if err != nil {
+		d.Error = err.Error()
+	}
+	return d, err
+}
+
+// writeDumped writes d to w as a single line of JSON, or as a table of fields per segment
+// followed by a blank line.
+func writeDumped(w io.Writer, d *dumped, output string) error {
+	if output == "json" {
+		var b, err = json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", b)
+		return err
+	}
+
+	var tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "string: %s\nformat: %s\n", d.String, d.Dump.Format)
+	for _, seg := range d.Dump.Segments {
+		fmt.Fprintf(tw, "\nsegment %s at %d: %s (%d bits)\n", seg.Name, seg.Offset, seg.Encoded, seg.Size)
+		if seg.Error != "" {
+			fmt.Fprintf(tw, "error: %s\n", seg.Error)
+		}
+		// tabwriter right-aligns every cell, so the name, value and bits are written as a single
+		// trailing cell with their own padding.
+		fmt.Fprintf(tw, "offset\twidth\t  %s\n", fieldText(seg, "name", "value", "bits"))
+		for _, f := range seg.Fields {
+			fmt.Fprintf(tw, "%d\t%d\t  %s\n", f.Offset, f.Width, fieldText(seg, f.Name, f.Value, f.Bits))
+		}
+	}
+	fmt.Fprintln(tw)
+	return tw.Flush()
+}
+
+// fieldText formats the name, value and bits of a field, padding the name and value to the widest in seg.
+func fieldText(seg *iabconsent.DumpSegment, name, value, bits string) string {
+	var nameWidth, valueWidth = len("name"), len("value")
+	for _, g := range seg.Fields {
+		if len(g.Name) > nameWidth {
+			nameWidth = len(g.Name)
+		}
+		if len(g.Value) > valueWidth {
+			valueWidth = len(g.Value)
+		}
+	}
+	return strings.TrimRight(fmt.Sprintf("%-*s  %-*s  %s", nameWidth, name, valueWidth, value, bits), " ")
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/go-check/check"
+)
+
+func (s *CommandSuite) TestDecodeBits(c *check.C) {
+	var code, stdout, stderr = runCommand("", "decode", "-bits", "DBABLA~BVVqAAEABCA.YA")
+	c.Check(code, check.Equals, 0)
+	c.Check(stderr, check.Equals, "")
+	c.Check(strings.HasPrefix(stdout, `string: DBABLA~BVVqAAEABCA.YA
+format: gpp
+
+segment header at 0: DBABLA (36 bits)
+  offset  width  name                  value     bits
+       0      6  type                  3         000011
+       6      6  version               1         000001
+      12     12  num_sections          1         000000000001
+      24      1  sections[0].is_range  false     0
+      25      5  sections[0].offset    7 (id 7)  01011
+      30      6  padding                         000000
+`), check.Equals, true, check.Commentf(stdout))
+	c.Check(strings.HasSuffix(stdout, `
+segment usnat.gpc at 19: YA (12 bits)
+  offset  width  name             value  bits
+       0      2  subsection_type  gpc    01
+       2      1  gpc              true   1
+       3      9  padding                 000000000
+
+`), check.Equals, true, check.Commentf(stdout))
+}
+
+func (s *CommandSuite) TestDecodeBitsInvalid(c *check.C) {
+	// Strings which fail to parse are dumped up to the point of failure.
+	var code, stdout, stderr = runCommand("", "decode", "-bits", "COvzTO5OvzTO5BRAAAENAPCoAL")
+	c.Check(code, check.Equals, 1)
+	c.Check(stderr, check.Matches, "iabconsent: COvzTO5OvzTO5BRAAAENAPCoAL: core: purposes_consent: .*index out of range\n")
+	c.Check(stdout, check.Matches, `(?s).*
+error: purposes_consent: .*
+     140     12  special_features_opt_in  \[1\]                     100000000000
+     152      0  purposes_consent
+     152      4  unread                                           1011
+`+"\n")
+
+	code, stdout, stderr = runCommand("", "decode", "-bits", "DBABrJFKCiEApIGA~BVVqAAEABCA")
+	c.Check(code, check.Equals, 1)
+	c.Check(stderr, check.Equals, "iabconsent: DBABrJFKCiEApIGA~BVVqAAEABCA: header: sections[0].length: gpp sections 1000000000001 exceeds limit 64\n")
+
+	code, stdout, stderr = runCommand("", "decode", "-bits", "-output", "json", "DBABLA~BVVqAAEABC")
+	c.Check(code, check.Equals, 1)
+	c.Check(stderr, check.Equals, "iabconsent: DBABLA~BVVqAAEABC: usnat: invalid consent string length for v1\n")
+	var d dumped
+	c.Assert(json.Unmarshal([]byte(stdout), &d), check.IsNil)
+	c.Check(d.Error, check.Equals, "usnat: invalid consent string length for v1")
+	c.Check(d.Dump.Segments[1].Error, check.Equals, "invalid consent string length for v1")
+
+	code, stdout, stderr = runCommand("", "decode", "-bits", "1YNN")
+	c.Check(code, check.Equals, 1)
+	c.Check(stdout, check.Equals, "")
+	c.Check(stderr, check.Equals, "iabconsent: 1YNN: us_privacy strings are not bit encoded\n")
+}
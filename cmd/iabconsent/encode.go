@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+// description is a consent to encode, in the same shape as the JSON output of decode.
+// Any "string" field from decode's output is ignored.
+type description struct {
+	Format  string          `json:"format"`
+	Consent json.RawMessage `json:"consent"`
+}
+
+func encodeCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var fs = flag.NewFlagSet("encode", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var file = fs.String("file", "-", "read JSON consent descriptions from `path`, or stdin if -")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: iabconsent encode [-file path]")
+		fmt.Fprintln(stderr, "Reads a stream of JSON objects, each with a format and a consent,")
+		fmt.Fprintln(stderr, "as written by \"iabconsent decode -output json\", and writes one consent string per line.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return 2
+	}
+
+	var r = stdin
+	if *file != "-" {
+		var f, err = os.Open(*file)
+		if err != nil {
+			fmt.Fprintf(stderr, "iabconsent: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var code = 0
+	var err = readDescriptions(r, func(n int, d *description) {
+		var s, err = encode(d)
+		if err != nil {
+			fmt.Fprintf(stderr, "iabconsent: document %d: %v\n", n, err)
+			code = 1
+			return
+		}
+		fmt.Fprintln(stdout, s)
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "iabconsent: read input: %v\n", err)
+		code = 1
+	}
+	return code
+}
+
+// readDescriptions calls fn with each description read from r, a stream of JSON objects, numbered
+// from 1.
+func readDescriptions(r io.Reader, fn func(int, *description)) error {
+	var dec = json.NewDecoder(r)
+	for n := 1; ; n++ {
+		var d description
+		if err := dec.Decode(&d); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.Wrapf(err, "document %d", n)
+		}
+		fn(n, &d)
+	}
+}
+
+// encode returns the consent string described by d.
+func encode(d *description) (string, error) {
+	if len(d.Consent) == 0 {
+		return "", errors.New("missing consent")
+	}
+	var s string
+	var err error
+	switch d.Format {
+	case formatUsPrivacy:
+		var p iabconsent.UsPrivacyParsedConsent
+		if err = json.Unmarshal(d.Consent, &p); err == nil {
+			s, err = p.Encode()
+		}
+	case formatGpp:
+		var g iabconsent.GppConsent
+		if err = json.Unmarshal(d.Consent, &g); err == nil {
+			s, err = iabconsent.EncodeGppConsent(&g)
+		}
+	case formatTcfV2:
+		var p iabconsent.V2ParsedConsent
+		if err = json.Unmarshal(d.Consent, &p); err == nil {
+			s, err = iabconsent.EncodeV2(&p)
+		}
+	case formatTcfV1:
+		return "", errors.New("encoding tcfv1 is not supported")
+	case "":
+		return "", errors.New("missing format")
+	default:
+		return "", errors.Errorf("unknown format %q", d.Format)
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "encode "+d.Format)
+	}
+	return s, nil
+}
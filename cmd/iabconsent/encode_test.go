@@ -0,0 +1,77 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/go-check/check"
+)
+
+func (s *CommandSuite) TestEncodeRoundTrip(c *check.C) {
+	var strs = []string{
+		"1YNN",
+		"1---",
+		"COvzTO5OvzTO5BRAAAENAPCoALIAADgAAAAAAewAwABAAlAB6ABBFAAA",
+		"DBABLA~BVVqAAEABCA",
+		"DBACLMA~BVVqAAEABCA.YA~BVoYYYI",
+		"DBABrGA~BVVqAAEABCA~BVoYYZoI~BVoYYYI~BVoYYQg~BVaGGGCA~BVoYYYQg",
+	}
+	var code, decoded, stderr = runCommand("", append([]string{"decode", "-output", "json"}, strs...)...)
+	c.Assert(code, check.Equals, 0)
+	c.Assert(stderr, check.Equals, "")
+
+	code, encoded, stderr := runCommand(decoded, "encode")
+	c.Check(code, check.Equals, 0)
+	c.Check(stderr, check.Equals, "")
+	c.Check(encoded, check.Equals, strings.Join(strs, "\n")+"\n")
+}
+
+func (s *CommandSuite) TestEncodeJSON(c *check.C) {
+	var code, stdout, stderr = runCommand(`{
+  "format": "gpp",
+  "consent": {
+    "sections": {
+      "usnat": {
+        "version": 1,
+        "sharing_notice": "provided",
+        "sale_opt_out_notice": "provided",
+        "sale_opt_out": "not_opted_out",
+        "sensitive_data_processing_consents": {"1": "no_consent"},
+        "known_child_sensitive_data_consents": {"0": "consent"},
+        "covered_transaction": "yes",
+        "opt_out_option_mode": "yes",
+        "gpc": true
+      }
+    }
+  }
+}
+{"format": "us_privacy", "consent": {"version": 1, "notice": "yes", "opt_out_sale": "yes", "lspa_covered": 2}}
+`, "encode")
+	c.Check(code, check.Equals, 0)
+	c.Check(stderr, check.Equals, "")
+	c.Check(stdout, check.Equals, "DBABLA~BUAgEAAAgUA.YA\n1YYN\n")
+}
+
+func (s *CommandSuite) TestEncodeErrors(c *check.C) {
+	var code, stdout, stderr = runCommand(`{"format": "tcfv1", "consent": {}}
+{"format": "tcfv3", "consent": {}}
+{"format": "us_privacy", "consent": {"version": 1, "notice": "maybe"}}
+{"format": "us_privacy", "consent": {"version": 1, "notice": "yes", "opt_out_sale": "no", "lspa_covered": "no"}}
+{"format": "gpp", "consent": {"sections": {"usxx": {}}}}
+`, "encode")
+	c.Check(code, check.Equals, 1)
+	c.Check(stdout, check.Equals, "1YNN\n")
+	c.Check(stderr, check.Equals, `iabconsent: document 1: encoding tcfv1 is not supported
+iabconsent: document 2: unknown format "tcfv3"
+iabconsent: document 3: encode us_privacy: unknown mspa value "maybe"
+iabconsent: document 5: encode gpp: unsupported gpp section "usxx"
+`)
+
+	code, _, stderr = runCommand("format: gpp", "encode")
+	c.Check(code, check.Equals, 1)
+	c.Check(stderr, check.Matches, "iabconsent: read input: document 1: invalid character .*\n")
+
+	code, _, stderr = runCommand("", "encode", "-file", filepath.Join(c.MkDir(), "missing.json"))
+	c.Check(code, check.Equals, 1)
+	c.Check(stderr, check.Matches, "iabconsent: open .*missing.json: no such file or directory\n")
+}
@@ -0,0 +1,64 @@
+// Command iabconsent decodes IAB consent strings locally, so they do not need to be pasted into third-party websites.
+//
+// Usage:
+//
+//	iabconsent decode [-output text|json] [-bits] [-file path] [consent string ...]
+//	iabconsent encode [-file path]
+//	iabconsent diff [-output text|json] old new
+//
+// Supported strings are TCF v1.1, TCF v2, GPP and US Privacy (us_privacy). The format of each string is detected
+// automatically. Strings are read from the arguments, from a newline-delimited file, or from stdin.
+// With -bits, decode lists every field of a TCF or GPP string with its bit offset, width and raw bits
+// instead, reading as far as it can into strings which fail to parse.
+//
+// encode is the inverse of decode: it reads JSON or YAML descriptions of TCF v2, GPP and US Privacy consents, in the
+// shape written by "decode -output json", and writes the encoded strings.
+//
+// diff lists the changes from one TCF v2, GPP or US Privacy string to another of the same format, such as purposes and
+// vendors added or removed, publisher restriction changes and MSPA opt-out flips.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command named by args[0], and returns the process exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+	switch args[0] {
+	case "decode":
+		return decodeCommand(args[1:], stdin, stdout, stderr)
+	case "encode":
+		return encodeCommand(args[1:], stdin, stdout, stderr)
+	case "diff":
+		return diffCommand(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "iabconsent: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `Usage: iabconsent <command> [flags] [argument ...]
+
+Commands:
+  decode    decode TCF v1.1, TCF v2, GPP and US Privacy strings
+  encode    encode TCF v2, GPP and US Privacy strings from JSON or YAML
+  diff      list the changes between two TCF v2, GPP or US Privacy strings
+
+Run "iabconsent <command> -h" for the flags of a command.
+`)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-check/check"
+)
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+type CommandSuite struct{}
+
+var _ = check.Suite(&CommandSuite{})
+
+// runCommand runs the command with args and stdin, returning the exit code, stdout and stderr.
+func runCommand(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	var code = run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func (s *CommandSuite) TestUsage(c *check.C) {
+	var code, _, stderr = runCommand("")
+	c.Check(code, check.Equals, 2)
+	c.Check(stderr, check.Matches, "Usage: iabconsent(.|\n)*")
+
+	code, _, stderr = runCommand("", "explode")
+	c.Check(code, check.Equals, 2)
+	c.Check(stderr, check.Matches, `iabconsent: unknown command "explode"(.|\n)*`)
+
+	code, _, stderr = runCommand("", "decode", "-output", "yaml", "1YNN")
+	c.Check(code, check.Equals, 2)
+	c.Check(stderr, check.Equals, "iabconsent: unknown output format \"yaml\"\n")
+}
+
+func (s *CommandSuite) TestDetectFormat(c *check.C) {
+	var tcs = []struct {
+		s      string
+		format string
+	}{
+		{s: "1YNN", format: formatUsPrivacy},
+		{s: "1---", format: formatUsPrivacy},
+		{s: "DBABLA~BVVqAAEABCA", format: formatGpp},
+		{s: "DBACNY~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1YNN", format: formatGpp},
+		{s: "BONMj34ONMj34ABACDENALqAAAAAplY", format: formatTcfV1},
+		{s: "COvzTO5OvzTO5BRAAAENAPCoALIAADgAAAAAAewAwABAAlAB6ABBFAAA", format: formatTcfV2},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.s)
+		var f, err = detectFormat(tc.s)
+		c.Check(err, check.IsNil)
+		c.Check(f, check.Equals, tc.format)
+	}
+
+	var _, err = detectFormat("1YNX")
+	c.Check(err, check.ErrorMatches, "unrecognized consent string")
+}
+
+func (s *CommandSuite) TestDecodeJSON(c *check.C) {
+	var code, stdout, stderr = runCommand("", "decode", "-output", "json", "1YYN", "DBABLA~BVVqAAEABCA")
+	c.Check(code, check.Equals, 0)
+	c.Check(stderr, check.Equals, "")
+
+	var lines = strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	c.Assert(lines, check.HasLen, 2)
+	c.Check(lines[0], check.Equals,
+		`{"format":"us_privacy","string":"1YYN","consent":{"version":1,"notice":"yes","opt_out_sale":"yes","lspa_covered":"no"}}`)
+
+	var d struct {
+		Format  string
+		Consent struct {
+			Sections map[string]map[string]interface{}
+		}
+	}
+	c.Assert(json.Unmarshal([]byte(lines[1]), &d), check.IsNil)
+	c.Check(d.Format, check.Equals, formatGpp)
+	c.Check(d.Consent.Sections["usnat"]["sale_opt_out"], check.Equals, "not_opted_out")
+}
+
+func (s *CommandSuite) TestDecodeText(c *check.C) {
+	var code, stdout, _ = runCommand("1YNN\n", "decode")
+	c.Check(code, check.Equals, 0)
+	c.Check(stdout, check.Equals, `format: us_privacy
+string: 1YNN
+consent:
+  version: 1
+  notice: yes
+  opt_out_sale: no
+  lspa_covered: no
+
+`)
+
+	code, stdout, _ = runCommand("", "decode", "COvzTO5OvzTO5BRAAAENAPCoALIAADgAAAAAAewAwABAAlAB6ABBFAAA")
+	c.Check(code, check.Equals, 0)
+	c.Check(stdout, check.Matches, `(?s).*
+  purposes_consent: \[1, 3, 4, 7\]
+.*
+  is_consent_range_encoding: true
+  consented_vendors: \[2, 37, 61\]
+.*`)
+
+	code, stdout, _ = runCommand("", "decode", "DBABLA~BVVqAAEABCA")
+	c.Check(code, check.Equals, 0)
+	// Bitfields are listed in numeric order.
+	c.Check(stdout, check.Matches, `(?s).*
+        9: not_applicable
+        10: not_applicable
+.*`)
+}
+
+func (s *CommandSuite) TestDecodeFile(c *check.C) {
+	var dir = c.MkDir()
+	var path = filepath.Join(dir, "strings.txt")
+	c.Assert(ioutil.WriteFile(path, []byte("1YNN\n\n  1NYN  \nnot a consent string\nDBABLA~BVVqAAEABCA\n"), 0600), check.IsNil)
+
+	var code, stdout, stderr = runCommand("", "decode", "-output", "json", "-file", path)
+	// Every line is decoded, but a bad line sets a non-zero exit code.
+	c.Check(code, check.Equals, 1)
+	c.Check(strings.Count(stdout, "\n"), check.Equals, 3)
+	c.Check(stderr, check.Equals, "iabconsent: not a consent string: unrecognized consent string\n")
+
+	code, stdout, _ = runCommand("1YNN\n1NYN\n", "decode", "-output", "json", "-file", "-")
+	c.Check(code, check.Equals, 0)
+	c.Check(strings.Count(stdout, "\n"), check.Equals, 2)
+
+	code, _, stderr = runCommand("", "decode", "-file", filepath.Join(dir, "missing.txt"))
+	c.Check(code, check.Equals, 1)
+	c.Check(stderr, check.Matches, "iabconsent: open .*missing.txt: no such file or directory\n")
+}
+
+func (s *CommandSuite) TestDecodeParseError(c *check.C) {
+	var code, stdout, stderr = runCommand("", "decode", "DBABL")
+	c.Check(code, check.Equals, 1)
+	c.Check(stdout, check.Equals, "")
+	c.Check(stderr, check.Equals, "iabconsent: DBABL: parse gpp: not enough gpp segments\n")
+}
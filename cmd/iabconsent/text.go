@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The text output is rendered from the JSON encoding of a decoded string, so both outputs share
+// the same field names and values. JSON objects are decoded into node trees rather than maps so
+// that fields are printed in the order they are encoded.
+
+// node is a decoded JSON value. Exactly one of fields, items or scalar is meaningful, per kind.
+type node struct {
+	kind   byte // '{', '[' or 's' for scalars.
+	keys   []string
+	fields []*node
+	items  []*node
+	scalar string
+}
+
+func writeText(w io.Writer, b []byte) error {
+	var dec = json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var n, err = readNode(dec)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	writeNode(&buf, n, 0)
+	buf.WriteByte('\n')
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func readNode(dec *json.Decoder) (*node, error) {
+	var t, err = dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := t.(type) {
+	case json.Delim:
+		var n = &node{kind: byte(t.String()[0])}
+		for dec.More() {
+			if n.kind == '{' {
+				var k, err = dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				n.keys = append(n.keys, k.(string))
+			}
+			var c, err = readNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			if n.kind == '{' {
+				n.fields = append(n.fields, c)
+			} else {
+				n.items = append(n.items, c)
+			}
+		}
+		// Consume the closing delimiter.
+		if _, err = dec.Token(); err != nil {
+			return nil, err
+		}
+		n.sortNumericKeys()
+		return n, nil
+	case nil:
+		return &node{kind: 's', scalar: "null"}, nil
+	default:
+		return &node{kind: 's', scalar: fmt.Sprint(t)}, nil
+	}
+}
+
+// sortNumericKeys orders the fields of an object keyed by integers, such as an MSPA bitfield,
+// numerically rather than in the lexical order they are encoded.
+func (n *node) sortNumericKeys() {
+	var ids = make([]int, len(n.keys))
+	for i, k := range n.keys {
+		var id, err = strconv.Atoi(k)
+		if err != nil {
+			return
+		}
+		ids[i] = id
+	}
+	sort.Sort(byID{ids: ids, n: n})
+}
+
+type byID struct {
+	ids []int
+	n   *node
+}
+
+func (b byID) Len() int           { return len(b.ids) }
+func (b byID) Less(i, j int) bool { return b.ids[i] < b.ids[j] }
+func (b byID) Swap(i, j int) {
+	b.ids[i], b.ids[j] = b.ids[j], b.ids[i]
+	b.n.keys[i], b.n.keys[j] = b.n.keys[j], b.n.keys[i]
+	b.n.fields[i], b.n.fields[j] = b.n.fields[j], b.n.fields[i]
+}
+
+// inline returns the single line form of n, and whether it has one: scalars, empty objects and
+// arrays of scalars are written inline.
+func (n *node) inline() (string, bool) {
+	switch n.kind {
+	case 's':
+		return n.scalar, true
+	case '{':
+		return "{}", len(n.fields) == 0
+	}
+	var items = make([]string, 0, len(n.items))
+	for _, i := range n.items {
+		if i.kind != 's' {
+			return "", false
+		}
+		items = append(items, i.scalar)
+	}
+	return "[" + strings.Join(items, ", ") + "]", true
+}
+
+func writeNode(buf *bytes.Buffer, n *node, depth int) {
+	var indent = strings.Repeat("  ", depth)
+	switch n.kind {
+	case '{':
+		for i, k := range n.keys {
+			if s, ok := n.fields[i].inline(); ok {
+				fmt.Fprintf(buf, "%s%s: %s\n", indent, k, s)
+			} else {
+				fmt.Fprintf(buf, "%s%s:\n", indent, k)
+				writeNode(buf, n.fields[i], depth+1)
+			}
+		}
+	case '[':
+		for _, item := range n.items {
+			if s, ok := item.inline(); ok {
+				fmt.Fprintf(buf, "%s- %s\n", indent, s)
+				continue
+			}
+			// Write the item one level deeper, then mark its first line as the start of the item.
+			var start = buf.Len()
+			writeNode(buf, item, depth+1)
+			var b = buf.Bytes()[start:]
+			copy(b[len(indent):], "- ")
+		}
+	default:
+		fmt.Fprintf(buf, "%s%s\n", indent, n.scalar)
+	}
+}
@@ -0,0 +1,211 @@
+package iabconsent
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CMPList is the list of Consent Management Platforms registered with IAB Europe, as published at
+// https://cmp-list.consensu.org/v2/cmp-list.json. See LoadCMPList.
+type CMPList struct {
+	LastUpdated time.Time `json:"lastUpdated"`
+	// CMPs are the registered CMPs by ID, including deleted CMPs.
+	CMPs map[int]*CMP `json:"cmps"`
+}
+
+// CMP is a single entry of the CMPList.
+type CMP struct {
+	ID           int      `json:"id"`
+	Name         string   `json:"name"`
+	IsCommercial bool     `json:"isCommercial"`
+	Environments []string `json:"environments,omitempty"`
+	// DeletedDate is set if the CMP was removed from the list, and should no longer be used.
+	DeletedDate *time.Time `json:"deletedDate,omitempty"`
+}
+
+// LoadCMPList decodes the CMP list JSON read from r.
+func LoadCMPList(r io.Reader) (*CMPList, error) {
+	var l CMPList
+	if err := json.NewDecoder(r).Decode(&l); err != nil {
+		return nil, errors.Wrap(err, "decode cmp list")
+	}
+	if l.CMPs == nil {
+		return nil, errors.New("cmp list has no cmps")
+	}
+	for id, cmp := range l.CMPs {
+		if cmp == nil || cmp.ID != id {
+			return nil, errors.Errorf("cmp list entry %d has a mismatched id", id)
+		}
+	}
+	return &l, nil
+}
+
+// LoadCMPListFile decodes the CMP list JSON file at path.
+func LoadCMPListFile(path string) (*CMPList, error) {
+	var f, err = os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "open cmp list")
+	}
+	defer f.Close()
+	return LoadCMPList(f)
+}
+
+// CMP returns the CMP with ID id, or nil if it is not in the list. Deleted CMPs are returned.
+func (l *CMPList) CMP(id int) *CMP {
+	return l.CMPs[id]
+}
+
+// CheckV2 returns a finding if the CMPID of p is not in the list, or is of a CMP which was deleted.
+// A string last updated after its CMP was deleted is an error, as a deleted CMP must not create or
+// update strings; one last updated before is a warning. The result is empty if the CMP is active,
+// or, as with LintV2, if p is nil.
+func (l *CMPList) CheckV2(p *V2ParsedConsent) []LintFinding {
+	if p == nil {
+		return nil
+	}
+	var cmp = l.CMP(p.CMPID)
+	switch {
+	case cmp == nil:
+		return []LintFinding{{
+			Rule:     LintUnknownCMP,
+			Severity: SeverityError,
+			Message:  fmt.Sprintf("cmp_id %d is not in the cmp list", p.CMPID),
+		}}
+	case cmp.DeletedDate == nil:
+		return nil
+	case p.LastUpdated.After(*cmp.DeletedDate):
+		return []LintFinding{{
+			Rule:     LintDeletedCMP,
+			Severity: SeverityError,
+			Message: fmt.Sprintf("cmp_id %d (%s) was deleted on %s, before the string was last updated",
+				p.CMPID, cmp.Name, cmp.DeletedDate.UTC().Format("2006-01-02")),
+		}}
+	default:
+		return []LintFinding{{
+			Rule:     LintDeletedCMP,
+			Severity: SeverityWarning,
+			Message: fmt.Sprintf("cmp_id %d (%s) was deleted on %s",
+				p.CMPID, cmp.Name, cmp.DeletedDate.UTC().Format("2006-01-02")),
+		}}
+	}
+}
+
+// CMPStats holds the counts of the strings from a single CMP seen by a CMPStatsCollector.
+type CMPStats struct {
+	// CMPID is the CMP the strings are attributed to, or 0 for strings too short to hold a CMP ID.
+	CMPID int
+	// Strings is the number of strings seen.
+	Strings int
+	// ParseFailures is the number of strings which ParseV2 returned an error for.
+	ParseFailures int
+	// LintFailures is the number of parsed strings with at least one finding of SeverityError.
+	LintFailures int
+	// Rules counts the parsed strings with at least one finding of each rule, of any severity.
+	Rules map[LintRule]int
+}
+
+// ParseFailureRate returns the fraction of strings which failed to parse.
+func (s *CMPStats) ParseFailureRate() float64 {
+	if s.Strings == 0 {
+		return 0
+	}
+	return float64(s.ParseFailures) / float64(s.Strings)
+}
+
+// LintFailureRate returns the fraction of parsed strings with at least one finding of SeverityError.
+func (s *CMPStats) LintFailureRate() float64 {
+	var parsed = s.Strings - s.ParseFailures
+	if parsed == 0 {
+		return 0
+	}
+	return float64(s.LintFailures) / float64(parsed)
+}
+
+// CMPStatsCollector aggregates the parse and lint failures of TCF v2 strings by the CMP which last
+// updated them, to find CMPs which write invalid strings. It is not safe for concurrent use.
+type CMPStatsCollector struct {
+	// cmps, if set, is also used to check each parsed string with CMPList.CheckV2.
+	cmps  *CMPList
+	stats map[int]*CMPStats
+}
+
+// NewCMPStatsCollector returns a CMPStatsCollector. If cmps is not nil, strings from unknown or
+// deleted CMPs are also counted as lint findings.
+func NewCMPStatsCollector(cmps *CMPList) *CMPStatsCollector {
+	return &CMPStatsCollector{cmps: cmps, stats: make(map[int]*CMPStats)}
+}
+
+// Add parses and lints s, and counts the result against its CMP. The CMP of a string which fails to
+// parse is read directly from its core segment, so failures can still be attributed.
+func (c *CMPStatsCollector) Add(s string) {
+	var p, err = ParseV2(s)
+	var id int
+	if err == nil {
+		id = p.CMPID
+	} else {
+		id = cmpIDOf(s)
+	}
+
+	var st = c.stats[id]
+	if st == nil {
+		st = &CMPStats{CMPID: id, Rules: make(map[LintRule]int)}
+		c.stats[id] = st
+	}
+	st.Strings++
+	if err != nil {
+		st.ParseFailures++
+		return
+	}
+
+	var findings = LintV2(p)
+	if c.cmps != nil {
+		findings = append(findings, c.cmps.CheckV2(p)...)
+	}
+	var failed bool
+	var rules = make(map[LintRule]bool)
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			failed = true
+		}
+		rules[f.Rule] = true
+	}
+	if failed {
+		st.LintFailures++
+	}
+	for r := range rules {
+		st.Rules[r]++
+	}
+}
+
+// Stats returns the counts of every CMP seen, ordered by CMPID.
+func (c *CMPStatsCollector) Stats() []*CMPStats {
+	var out = make([]*CMPStats, 0, len(c.stats))
+	for _, st := range c.stats {
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CMPID < out[j].CMPID })
+	return out
+}
+
+// cmpIDOf returns the CMP ID of the core segment of TCF v2 string s, which follows the version and
+// the created and last updated times, or 0 if s is too short to hold it.
+func cmpIDOf(s string) int {
+	var b, err = base64.RawURLEncoding.DecodeString(strings.SplitN(s, ".", 2)[0])
+	if err != nil {
+		return 0
+	}
+	var r = NewConsentReader(b)
+	_, _ = r.ReadInt(6)
+	_, _ = r.ReadTime()
+	_, _ = r.ReadTime()
+	var id, _ = r.ReadInt(12)
+	return id
+}
@@ -0,0 +1,172 @@
+package iabconsent_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-check/check"
+	"github.com/pkg/errors"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type CMPListSuite struct{}
+
+var _ = check.Suite(&CMPListSuite{})
+
+var cmpListFixture = `{
+  "lastUpdated": "2020-06-01T16:00:00Z",
+  "cmps": {
+    "81": {"id": 81, "name": "Active CMP", "isCommercial": true, "environments": ["Web"]},
+    "123": {"id": 123, "name": "Deleted CMP", "isCommercial": false, "deletedDate": "2020-03-01T00:00:00Z"},
+    "124": {"id": 124, "name": "Later Deleted CMP", "isCommercial": true, "deletedDate": "2021-01-01T00:00:00Z"}
+  }
+}`
+
+// Strings from CMPs 81 and 123, last updated 2020-03-05.
+var (
+	cmp81String  = "COvzTO5OvzTO5BRAAAENAPCoALIAADgAAAAAAewAwABAAlAB6ABBFADBQAQA9hAAAcAA"
+	cmp123String = "COvzTO5OvzTO5B7ABCENAPCYAKdAADkAAIqIFhwBAAGAAXAFGAsMAhYAgAMAAegBYAEKAAA"
+)
+
+func (s *CMPListSuite) TestLoadCMPList(c *check.C) {
+	var l, err = iabconsent.LoadCMPList(strings.NewReader(cmpListFixture))
+	c.Assert(err, check.IsNil)
+	c.Check(l.CMPs, check.HasLen, 3)
+	c.Check(l.CMP(81).Name, check.Equals, "Active CMP")
+	c.Check(l.CMP(81).DeletedDate, check.IsNil)
+	c.Check(l.CMP(123).DeletedDate.Format("2006-01-02"), check.Equals, "2020-03-01")
+	c.Check(l.CMP(1), check.IsNil)
+
+	var dir = c.MkDir()
+	var path = filepath.Join(dir, "cmp-list.json")
+	c.Assert(ioutil.WriteFile(path, []byte(cmpListFixture), 0644), check.IsNil)
+	l, err = iabconsent.LoadCMPListFile(path)
+	c.Assert(err, check.IsNil)
+	c.Check(l.CMPs, check.HasLen, 3)
+
+	_, err = iabconsent.LoadCMPListFile(filepath.Join(dir, "missing.json"))
+	c.Check(os.IsNotExist(errors.Cause(err)), check.Equals, true)
+}
+
+func (s *CMPListSuite) TestLoadCMPListErrors(c *check.C) {
+	var tcs = []struct {
+		desc  string
+		json  string
+		error string
+	}{
+		{
+			desc:  "Invalid JSON.",
+			json:  `{"cmps": `,
+			error: "decode cmp list: unexpected EOF",
+		},
+		{
+			desc:  "No cmps.",
+			json:  `{"lastUpdated": "2020-06-01T16:00:00Z"}`,
+			error: "cmp list has no cmps",
+		},
+		{
+			desc:  "Mismatched id.",
+			json:  `{"cmps": {"2": {"id": 3}}}`,
+			error: "cmp list entry 2 has a mismatched id",
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		var _, err = iabconsent.LoadCMPList(strings.NewReader(tc.json))
+		c.Check(err, check.ErrorMatches, tc.error)
+	}
+}
+
+func (s *CMPListSuite) TestCheckV2(c *check.C) {
+	var l, err = iabconsent.LoadCMPList(strings.NewReader(cmpListFixture))
+	c.Assert(err, check.IsNil)
+
+	var tcs = []struct {
+		desc     string
+		cmpID    int
+		expected []iabconsent.LintFinding
+	}{
+		{
+			desc:  "Active CMP.",
+			cmpID: 81,
+		},
+		{
+			desc:  "Unknown CMP.",
+			cmpID: 2,
+			expected: []iabconsent.LintFinding{{
+				Rule:     iabconsent.LintUnknownCMP,
+				Severity: iabconsent.SeverityError,
+				Message:  "cmp_id 2 is not in the cmp list",
+			}},
+		},
+		{
+			desc:  "Updated after the CMP was deleted.",
+			cmpID: 123,
+			expected: []iabconsent.LintFinding{{
+				Rule:     iabconsent.LintDeletedCMP,
+				Severity: iabconsent.SeverityError,
+				Message:  "cmp_id 123 (Deleted CMP) was deleted on 2020-03-01, before the string was last updated",
+			}},
+		},
+		{
+			desc:  "Updated before the CMP was deleted.",
+			cmpID: 124,
+			expected: []iabconsent.LintFinding{{
+				Rule:     iabconsent.LintDeletedCMP,
+				Severity: iabconsent.SeverityWarning,
+				Message:  "cmp_id 124 (Later Deleted CMP) was deleted on 2021-01-01",
+			}},
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		var p, err = iabconsent.ParseV2(cmp81String)
+		c.Assert(err, check.IsNil)
+		p.CMPID = tc.cmpID
+		c.Check(l.CheckV2(p), check.DeepEquals, tc.expected)
+	}
+
+	c.Check(l.CheckV2(nil), check.IsNil)
+}
+
+func (s *CMPListSuite) TestCMPStatsCollector(c *check.C) {
+	var l, err = iabconsent.LoadCMPList(strings.NewReader(cmpListFixture))
+	c.Assert(err, check.IsNil)
+
+	var sc = iabconsent.NewCMPStatsCollector(l)
+	sc.Add(cmp81String)
+	sc.Add(cmp81String)
+	// Truncated after the CMP ID, so the failure is attributed to CMP 81.
+	sc.Add(cmp81String[:30])
+	sc.Add(cmp123String)
+	// Too short to hold a CMP ID.
+	sc.Add("CO")
+
+	var stats = sc.Stats()
+	c.Assert(stats, check.HasLen, 3)
+
+	c.Check(stats[0].CMPID, check.Equals, 0)
+	c.Check(stats[0].Strings, check.Equals, 1)
+	c.Check(stats[0].ParseFailureRate(), check.Equals, 1.0)
+	c.Check(stats[0].LintFailureRate(), check.Equals, 0.0)
+
+	c.Check(stats[1].CMPID, check.Equals, 81)
+	c.Check(stats[1].Strings, check.Equals, 3)
+	c.Check(stats[1].ParseFailures, check.Equals, 1)
+	// The publisher_cc of the string is "AA", which is only a warning.
+	c.Check(stats[1].LintFailures, check.Equals, 0)
+	c.Check(stats[1].Rules, check.DeepEquals, map[iabconsent.LintRule]int{iabconsent.LintInvalidPublisherCC: 2})
+	c.Check(stats[1].LintFailureRate(), check.Equals, 0.0)
+
+	c.Check(stats[2].CMPID, check.Equals, 123)
+	c.Check(stats[2].Strings, check.Equals, 1)
+	c.Check(stats[2].LintFailures, check.Equals, 1)
+	c.Check(stats[2].Rules, check.DeepEquals, map[iabconsent.LintRule]int{
+		iabconsent.LintPurposeOneTreatmentGlobal: 1,
+		iabconsent.LintDeletedCMP:                1,
+	})
+	c.Check(stats[2].LintFailureRate(), check.Equals, 1.0)
+}
@@ -0,0 +1,216 @@
+package iabconsent
+
+import (
+	"time"
+)
+
+// Framework is an enum type identifying the consent framework a parsed consent belongs to.
+type Framework int
+
+const (
+	UnknownFramework Framework = iota
+	// IAB Europe Transparency & Consent Framework, v1.1.
+	FrameworkTcfV1
+	// IAB Europe Transparency & Consent Framework, v2.
+	FrameworkTcfV2
+	// IAB Multi-State Privacy Agreement sections of a GPP string.
+	FrameworkMspa
+	// IAB US Privacy (CCPA) string.
+	FrameworkUsPrivacy
+)
+
+// Decision is an enum type of the outcome of evaluating a ProcessingRequest.
+type Decision int
+
+const (
+	// Processing is not permitted by the consent.
+	DecisionDeny Decision = iota
+	// Processing is permitted by the consent.
+	DecisionAllow
+	// The consent says nothing about any of the activities in the request.
+	DecisionNotApplicable
+)
+
+// ProcessingRequest describes the processing a caller wants to perform. Not every field is meaningful for every
+// framework: TCF only looks at Purposes and VendorID, while the US frameworks only look at the activity flags and
+// SensitiveDataCategories.
+type ProcessingRequest struct {
+	// TCF purposes that must be allowed on the basis of consent.
+	Purposes []int
+	// TCF vendor that wants to process.
+	VendorID int
+	// The request involves the Sale of Personal Data.
+	Sale bool
+	// The request involves the Sharing of Personal Data.
+	Sharing bool
+	// The request involves processing Personal Data for Targeted Advertising.
+	TargetedAdvertising bool
+	// Sensitive data categories to be processed, numbered as in the section being evaluated.
+	SensitiveDataCategories []int
+	// When the processing happened, e.g. when reprocessing historic traffic. MSPA state sections whose law was not
+	// yet in force at At are not applicable, see SectionInEffect. The zero time skips this check.
+	At time.Time
+}
+
+// ConsentEvaluator is implemented by every parsed consent type, so callers can evaluate a consent without knowing
+// which framework it came from.
+type ConsentEvaluator interface {
+	// Framework the consent was parsed from.
+	Framework() Framework
+	// FrameworkVersion is the version of the framework (or GPP section) specification used to encode the consent.
+	FrameworkVersion() int
+	// Decide evaluates whether the consent permits the processing described by the request.
+	Decide(req ProcessingRequest) Decision
+}
+
+var (
+	_ ConsentEvaluator = &ParsedConsent{}
+	_ ConsentEvaluator = &V2ParsedConsent{}
+	_ ConsentEvaluator = &MspaParsedConsent{}
+	_ ConsentEvaluator = &UsPrivacyParsedConsent{}
+)
+
+// Framework returns FrameworkTcfV1.
+func (p *ParsedConsent) Framework() Framework {
+	return FrameworkTcfV1
+}
+
+// FrameworkVersion returns the encoding version of the consent string.
+func (p *ParsedConsent) FrameworkVersion() int {
+	if p == nil {
+		return 0
+	}
+	return p.Version
+}
+
+// Decide allows the request iff SuitableToProcess(req.Purposes, req.VendorID).
+func (p *ParsedConsent) Decide(req ProcessingRequest) Decision {
+	return decisionOf(p.SuitableToProcess(req.Purposes, req.VendorID))
+}
+
+// Framework returns FrameworkTcfV2.
+func (p *V2ParsedConsent) Framework() Framework {
+	return FrameworkTcfV2
+}
+
+// FrameworkVersion returns the encoding version of the TC String.
+func (p *V2ParsedConsent) FrameworkVersion() int {
+	return p.SectionVersion()
+}
+
+// Decide allows the request iff SuitableToProcess(req.Purposes, req.VendorID).
+func (p *V2ParsedConsent) Decide(req ProcessingRequest) Decision {
+	return decisionOf(p.SuitableToProcess(req.Purposes, req.VendorID))
+}
+
+// Framework returns FrameworkMspa.
+func (p *MspaParsedConsent) Framework() Framework {
+	return FrameworkMspa
+}
+
+// FrameworkVersion returns the version of the section specification used to encode the string.
+func (p *MspaParsedConsent) FrameworkVersion() int {
+	return p.SectionVersion()
+}
+
+// Decide evaluates the Sale, Sharing, Targeted Advertising and Sensitive Data activities of the request. An activity
+// is denied if the user opted out of it, if notice of the opportunity to opt out was not provided, or, for Sale,
+// Sharing and Targeted Advertising, if Global Privacy Control is set or the business is in Service Provider Mode.
+// As in NormalizeMspa, Targeted Advertising in the California section is governed by its Sharing fields, as the
+// section encodes no Targeted Advertising fields of its own.
+// Sensitive data categories are denied if the user did not consent to, or opted out of, their processing, or if the
+// section does not encode the category at all, as nothing is then known of the user's choice. A request with none of
+// these activities is DecisionNotApplicable, as is a request whose At is before the law of a state section took effect.
+// A nil consent denies every other request.
+//
+// MspaOptOutOptionMode is not consulted: in Opt-Out Option Mode the business must honor the user's opt-outs, which
+// Decide does in every mode, so it can only ever allow what the opt-outs and notices already allow.
+func (p *MspaParsedConsent) Decide(req ProcessingRequest) Decision {
+	if !req.Sale && !req.Sharing && !req.TargetedAdvertising && len(req.SensitiveDataCategories) == 0 {
+		return DecisionNotApplicable
+	}
+	if p == nil {
+		return DecisionDeny
+	}
+	if !req.At.IsZero() && !SectionInEffect(p.SID, req.At) {
+		return DecisionNotApplicable
+	}
+	if (req.Sale || req.Sharing || req.TargetedAdvertising) && (p.Gpc || p.MspaServiceProviderMode == MspaYes) {
+		return DecisionDeny
+	}
+	if req.Sale && !mspaOptOutAllows(p.SaleOptOutNotice, p.SaleOptOut) {
+		return DecisionDeny
+	}
+	if req.Sharing && !mspaOptOutAllows(p.SharingOptOutNotice, p.SharingOptOut) {
+		return DecisionDeny
+	}
+	var taNotice, taOptOut = p.TargetedAdvertisingOptOutNotice, p.TargetedAdvertisingOptOut
+	if p.SID == UsCaliforniaSID {
+		taNotice, taOptOut = p.SharingOptOutNotice, p.SharingOptOut
+	}
+	if req.TargetedAdvertising && !mspaOptOutAllows(taNotice, taOptOut) {
+		return DecisionDeny
+	}
+	for _, c := range req.SensitiveDataCategories {
+		var consent, hasConsent = p.SensitiveDataProcessingConsents[c]
+		var optOut, hasOptOut = p.SensitiveDataProcessingOptOuts[c]
+		if !hasConsent && !hasOptOut {
+			return DecisionDeny
+		}
+		switch consent {
+		case NoConsent, InvalidConsentValue:
+			return DecisionDeny
+		}
+		switch optOut {
+		case OptedOut, InvalidOptOutValue:
+			return DecisionDeny
+		}
+	}
+	return DecisionAllow
+}
+
+// mspaOptOutAllows returns false if the user opted out of an activity, notice of the opportunity to opt out of it was
+// not provided, or either value is invalid.
+func mspaOptOutAllows(n MspaNotice, o MspaOptout) bool {
+	switch {
+	case n == NoticeNotProvided, n == InvalidNoticeValue:
+		return false
+	case o == OptedOut, o == InvalidOptOutValue:
+		return false
+	default:
+		return true
+	}
+}
+
+// Framework returns FrameworkUsPrivacy.
+func (p *UsPrivacyParsedConsent) Framework() Framework {
+	return FrameworkUsPrivacy
+}
+
+// FrameworkVersion returns the version of the US Privacy string.
+func (p *UsPrivacyParsedConsent) FrameworkVersion() int {
+	if p == nil {
+		return 0
+	}
+	return p.Version
+}
+
+// Decide denies a Sale or Sharing request if the user opted out of the Sale of their Personal Information. US
+// Privacy does not carry signals for any other activity, so other requests are DecisionNotApplicable. A nil consent
+// denies every Sale or Sharing request.
+func (p *UsPrivacyParsedConsent) Decide(req ProcessingRequest) Decision {
+	if !req.Sale && !req.Sharing {
+		return DecisionNotApplicable
+	}
+	if p == nil {
+		return DecisionDeny
+	}
+	return decisionOf(p.OptOutSale != MspaYes && p.OptOutSale != InvalidMspaValue)
+}
+
+func decisionOf(allowed bool) Decision {
+	if allowed {
+		return DecisionAllow
+	}
+	return DecisionDeny
+}
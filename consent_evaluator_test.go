@@ -0,0 +1,220 @@
+package iabconsent_test
+
+import (
+	"time"
+
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type ConsentEvaluatorSuite struct{}
+
+var _ = check.Suite(&ConsentEvaluatorSuite{})
+
+func (s *ConsentEvaluatorSuite) TestFramework(c *check.C) {
+	var tcs = []struct {
+		e         iabconsent.ConsentEvaluator
+		framework iabconsent.Framework
+		version   int
+	}{
+		{e: &iabconsent.ParsedConsent{Version: 1}, framework: iabconsent.FrameworkTcfV1, version: 1},
+		{e: &iabconsent.V2ParsedConsent{Version: 2}, framework: iabconsent.FrameworkTcfV2, version: 2},
+		{e: mspaConsentFixtures[iabconsent.UsNationalSID]["CVVVVVVVVVVW.YA"], framework: iabconsent.FrameworkMspa, version: 2},
+		{e: &iabconsent.UsPrivacyParsedConsent{Version: 1}, framework: iabconsent.FrameworkUsPrivacy, version: 1},
+	}
+	for _, tc := range tcs {
+		c.Check(tc.e.Framework(), check.Equals, tc.framework)
+		c.Check(tc.e.FrameworkVersion(), check.Equals, tc.version)
+	}
+}
+
+func (s *ConsentEvaluatorSuite) TestDecideTcf(c *check.C) {
+	var v2 = &iabconsent.V2ParsedConsent{
+		PurposesConsent:  map[int]bool{1: true, 2: true},
+		ConsentedVendors: map[int]bool{123: true},
+	}
+	c.Check(v2.Decide(iabconsent.ProcessingRequest{Purposes: []int{1, 2}, VendorID: 123}), check.Equals, iabconsent.DecisionAllow)
+	c.Check(v2.Decide(iabconsent.ProcessingRequest{Purposes: []int{1, 3}, VendorID: 123}), check.Equals, iabconsent.DecisionDeny)
+	c.Check(v2.Decide(iabconsent.ProcessingRequest{Purposes: []int{1}, VendorID: 124}), check.Equals, iabconsent.DecisionDeny)
+
+	var v1 = &iabconsent.ParsedConsent{
+		PurposesAllowed:  map[int]bool{1: true},
+		ConsentedVendors: map[int]bool{10: true},
+	}
+	c.Check(v1.Decide(iabconsent.ProcessingRequest{Purposes: []int{1}, VendorID: 10}), check.Equals, iabconsent.DecisionAllow)
+	c.Check(v1.Decide(iabconsent.ProcessingRequest{Purposes: []int{2}, VendorID: 10}), check.Equals, iabconsent.DecisionDeny)
+}
+
+func (s *ConsentEvaluatorSuite) TestDecideMspa(c *check.C) {
+	var tcs = []struct {
+		desc     string
+		consent  *iabconsent.MspaParsedConsent
+		req      iabconsent.ProcessingRequest
+		expected iabconsent.Decision
+	}{
+		{
+			desc:     "No US activities requested.",
+			consent:  mspaConsentFixtures[iabconsent.UsNationalSID]["BVVqAAEABCA.QA"],
+			req:      iabconsent.ProcessingRequest{Purposes: []int{1}, VendorID: 1},
+			expected: iabconsent.DecisionNotApplicable,
+		},
+		{
+			desc:     "Not opted out of Sale or Targeted Advertising.",
+			consent:  mspaConsentFixtures[iabconsent.UsNationalSID]["BVVqAAEABCA.QA"],
+			req:      iabconsent.ProcessingRequest{Sale: true, TargetedAdvertising: true},
+			expected: iabconsent.DecisionAllow,
+		},
+		{
+			desc:     "GPC set.",
+			consent:  mspaConsentFixtures[iabconsent.UsNationalSID]["BVVqAAEABCA.YA"],
+			req:      iabconsent.ProcessingRequest{Sale: true},
+			expected: iabconsent.DecisionDeny,
+		},
+		{
+			desc: "Opted out of Sharing.",
+			consent: &iabconsent.MspaParsedConsent{
+				SharingOptOutNotice: iabconsent.NoticeProvided,
+				SharingOptOut:       iabconsent.OptedOut,
+			},
+			req:      iabconsent.ProcessingRequest{Sharing: true},
+			expected: iabconsent.DecisionDeny,
+		},
+		{
+			desc: "Notice of Targeted Advertising opt out not provided.",
+			consent: &iabconsent.MspaParsedConsent{
+				TargetedAdvertisingOptOutNotice: iabconsent.NoticeNotProvided,
+				TargetedAdvertisingOptOut:       iabconsent.OptOutNotApplicable,
+			},
+			req:      iabconsent.ProcessingRequest{TargetedAdvertising: true},
+			expected: iabconsent.DecisionDeny,
+		},
+		{
+			desc: "Service Provider Mode.",
+			consent: &iabconsent.MspaParsedConsent{
+				SaleOptOutNotice:        iabconsent.NoticeProvided,
+				SaleOptOut:              iabconsent.NotOptedOut,
+				MspaServiceProviderMode: iabconsent.MspaYes,
+			},
+			req:      iabconsent.ProcessingRequest{Sale: true},
+			expected: iabconsent.DecisionDeny,
+		},
+		{
+			desc:     "Virginia sensitive data consented.",
+			consent:  mspaConsentFixtures[iabconsent.UsVirginiaSID]["BVoYYYI"],
+			req:      iabconsent.ProcessingRequest{SensitiveDataCategories: []int{0, 2, 5}},
+			expected: iabconsent.DecisionAllow,
+		},
+		{
+			desc:     "Virginia sensitive data not consented.",
+			consent:  mspaConsentFixtures[iabconsent.UsVirginiaSID]["BVoYYYI"],
+			req:      iabconsent.ProcessingRequest{SensitiveDataCategories: []int{2, 4}},
+			expected: iabconsent.DecisionDeny,
+		},
+		{
+			desc:    "Tennessee section before TIPA took effect.",
+			consent: mspaConsentFixtures[iabconsent.UsTennesseeSID]["Bqqqqqo"],
+			req: iabconsent.ProcessingRequest{Sale: true,
+				At: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+			expected: iabconsent.DecisionNotApplicable,
+		},
+		{
+			desc: "Section in effect.",
+			consent: &iabconsent.MspaParsedConsent{
+				SID:              iabconsent.UsCaliforniaSID,
+				SaleOptOutNotice: iabconsent.NoticeProvided,
+				SaleOptOut:       iabconsent.OptedOut,
+			},
+			req: iabconsent.ProcessingRequest{Sale: true,
+				At: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)},
+			expected: iabconsent.DecisionDeny,
+		},
+		{
+			desc:     "Virginia sensitive data category not encoded by the section.",
+			consent:  mspaConsentFixtures[iabconsent.UsVirginiaSID]["BVoYYYI"],
+			req:      iabconsent.ProcessingRequest{SensitiveDataCategories: []int{0, 8}},
+			expected: iabconsent.DecisionDeny,
+		},
+		{
+			desc:     "Sensitive data of a section without categories.",
+			consent:  &iabconsent.MspaParsedConsent{},
+			req:      iabconsent.ProcessingRequest{SensitiveDataCategories: []int{1}},
+			expected: iabconsent.DecisionDeny,
+		},
+		{
+			desc: "Opt-Out Option Mode with no opt out.",
+			consent: &iabconsent.MspaParsedConsent{
+				SaleOptOutNotice:     iabconsent.NoticeProvided,
+				SaleOptOut:           iabconsent.NotOptedOut,
+				MspaOptOutOptionMode: iabconsent.MspaYes,
+			},
+			req:      iabconsent.ProcessingRequest{Sale: true},
+			expected: iabconsent.DecisionAllow,
+		},
+		{
+			desc: "California Sharing opted out denies Targeted Advertising.",
+			consent: &iabconsent.MspaParsedConsent{
+				SID:                 iabconsent.UsCaliforniaSID,
+				SharingOptOutNotice: iabconsent.NoticeProvided,
+				SharingOptOut:       iabconsent.OptedOut,
+			},
+			req:      iabconsent.ProcessingRequest{TargetedAdvertising: true},
+			expected: iabconsent.DecisionDeny,
+		},
+		{
+			desc: "California Sharing not opted out allows Targeted Advertising.",
+			consent: &iabconsent.MspaParsedConsent{
+				SID:                 iabconsent.UsCaliforniaSID,
+				SharingOptOutNotice: iabconsent.NoticeProvided,
+				SharingOptOut:       iabconsent.NotOptedOut,
+			},
+			req:      iabconsent.ProcessingRequest{TargetedAdvertising: true},
+			expected: iabconsent.DecisionAllow,
+		},
+		{
+			desc:     "California sensitive data opted out.",
+			consent:  mspaConsentFixtures[iabconsent.UsCaliforniaSID]["BVoYYZoI"],
+			req:      iabconsent.ProcessingRequest{SensitiveDataCategories: []int{1}},
+			expected: iabconsent.DecisionDeny,
+		},
+		{
+			desc:     "California sensitive data not opted out.",
+			consent:  mspaConsentFixtures[iabconsent.UsCaliforniaSID]["BVoYYZoI"],
+			req:      iabconsent.ProcessingRequest{SensitiveDataCategories: []int{2, 5}},
+			expected: iabconsent.DecisionAllow,
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		c.Check(tc.consent.Decide(tc.req), check.Equals, tc.expected)
+	}
+}
+
+func (s *ConsentEvaluatorSuite) TestDecideUsPrivacy(c *check.C) {
+	var tcs = []struct {
+		s        string
+		req      iabconsent.ProcessingRequest
+		expected iabconsent.Decision
+	}{
+		{s: "1YNN", req: iabconsent.ProcessingRequest{Sale: true}, expected: iabconsent.DecisionAllow},
+		{s: "1YYN", req: iabconsent.ProcessingRequest{Sale: true}, expected: iabconsent.DecisionDeny},
+		{s: "1YYN", req: iabconsent.ProcessingRequest{Sharing: true}, expected: iabconsent.DecisionDeny},
+		{s: "1---", req: iabconsent.ProcessingRequest{Sale: true}, expected: iabconsent.DecisionAllow},
+		{s: "1YYN", req: iabconsent.ProcessingRequest{TargetedAdvertising: true}, expected: iabconsent.DecisionNotApplicable},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.s)
+		var p, err = iabconsent.ParseUsPrivacy(tc.s)
+		c.Assert(err, check.IsNil)
+		c.Check(p.Decide(tc.req), check.Equals, tc.expected)
+	}
+}
+
+func (s *ConsentEvaluatorSuite) TestGppConsentEvaluators(c *check.C) {
+	var p, err = iabconsent.ParseGppConsent("DBACLMA~BVVqAAEABCA~BVoYYYI")
+	c.Assert(err, check.IsNil)
+	for _, e := range p.Sections {
+		c.Check(e.Framework(), check.Equals, iabconsent.FrameworkMspa)
+		c.Check(e.Decide(iabconsent.ProcessingRequest{Sale: true}), check.Equals, iabconsent.DecisionAllow)
+	}
+}
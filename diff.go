@@ -0,0 +1,312 @@
+package iabconsent
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// ChangeKind is an enum type of the ways a field can change between two consents.
+type ChangeKind int
+
+const (
+	UnknownChange ChangeKind = iota
+	// An ID was added to a set, or a value to a bit field or restriction.
+	ChangeAdded
+	// An ID was removed from a set, or a value from a bit field or restriction.
+	ChangeRemoved
+	// A value changed.
+	ChangeModified
+)
+
+var changeKindEnum = enum{kind: "change kind", names: []string{"unknown", "added", "removed", "modified"}}
+
+// String returns the name of the kind, e.g. "added".
+func (k ChangeKind) String() string {
+	return changeKindEnum.name(int(k))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (k ChangeKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// Change is a single difference between two consents, as returned by DiffV2 and DiffMspa.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	// Field is the name of the changed field, as in JSON, e.g. "purposes_consent". Elements of MSPA
+	// bit fields are named with their index, e.g. "sensitive_data_processing_consents[2]".
+	Field string `json:"field"`
+	// ID is the purpose, special feature or vendor added or removed for TCF fields holding a set of
+	// IDs, and the vendor for "pub_restriction_entries".
+	ID int `json:"id,omitempty"`
+	// EndID is set when a range of vendors changed, from ID to EndID inclusive.
+	EndID int `json:"end_id,omitempty"`
+	// PurposeID is the purpose of a "pub_restriction_entries" change.
+	PurposeID int `json:"purpose_id,omitempty"`
+	// Old is the value before the change, set unless the change added a set ID or value.
+	Old string `json:"old,omitempty"`
+	// New is the value after the change, set unless the change removed a set ID or value.
+	New string `json:"new,omitempty"`
+}
+
+// String returns a human readable description of the change, e.g. "purposes_consent 3 added",
+// "consented_vendors 10-20 removed" or "sale_opt_out: not_opted_out -> opted_out".
+func (c Change) String() string {
+	var ids = strconv.Itoa(c.ID)
+	if c.EndID > c.ID {
+		ids += "-" + strconv.Itoa(c.EndID)
+	}
+	var subject = c.Field
+	switch {
+	case c.PurposeID != 0 && c.EndID > c.ID:
+		subject = fmt.Sprintf("%s purpose %d vendors %s", c.Field, c.PurposeID, ids)
+	case c.PurposeID != 0:
+		subject = fmt.Sprintf("%s purpose %d vendor %s", c.Field, c.PurposeID, ids)
+	case c.ID != 0:
+		subject = c.Field + " " + ids
+	}
+	switch c.Kind {
+	case ChangeAdded:
+		if c.New != "" {
+			return subject + " added: " + c.New
+		}
+		return subject + " added"
+	case ChangeRemoved:
+		if c.Old != "" {
+			return subject + " removed: " + c.Old
+		}
+		return subject + " removed"
+	case ChangeModified:
+		return subject + ": " + c.Old + " -> " + c.New
+	default:
+		return fmt.Sprintf("unknown change %d to %s", int(c.Kind), subject)
+	}
+}
+
+// DiffV2 returns the changes from consent a to consent b: special feature opt-ins, purpose
+// consents and legitimate interests, vendor consents and legitimate interests, out-of-band vendors,
+// and publisher restrictions. Vendors are compared by ID, so a vendor list encoded as a bit field in
+// one consent and as ranges in the other only differs in the vendors it holds. Vendor changes are
+// reported as ranges of consecutive vendors, from ID to EndID. Changes are ordered by field, then by
+// ID. The result is empty if a and b hold the same signals. A nil consent holds no signals.
+func DiffV2(a, b *V2ParsedConsent) []Change {
+	if a == nil {
+		a = &V2ParsedConsent{}
+	}
+	if b == nil {
+		b = &V2ParsedConsent{}
+	}
+	var changes []Change
+	changes = diffSets(changes, "special_features_opt_in", a.SpecialFeaturesOptIn, b.SpecialFeaturesOptIn)
+	changes = diffSets(changes, "purposes_consent", a.PurposesConsent, b.PurposesConsent)
+	changes = diffSets(changes, "purposes_li_transparency", a.PurposesLITransparency, b.PurposesLITransparency)
+	changes = diffRanges(changes, "consented_vendors",
+		vendorRanges(a.IsConsentRangeEncoding, a.ConsentedVendors, a.ConsentedVendorsRange),
+		vendorRanges(b.IsConsentRangeEncoding, b.ConsentedVendors, b.ConsentedVendorsRange))
+	changes = diffRanges(changes, "interests_vendors",
+		vendorRanges(a.IsInterestsRangeEncoding, a.InterestsVendors, a.InterestsVendorsRange),
+		vendorRanges(b.IsInterestsRangeEncoding, b.InterestsVendors, b.InterestsVendorsRange))
+	changes = diffRanges(changes, "oob_disclosed_vendors", oobVendorRanges(a.OOBDisclosedVendors), oobVendorRanges(b.OOBDisclosedVendors))
+	changes = diffRanges(changes, "oob_allowed_vendors", oobVendorRanges(a.OOBAllowedVendors), oobVendorRanges(b.OOBAllowedVendors))
+	return diffRestrictions(changes, a.PubRestrictionEntries, b.PubRestrictionEntries)
+}
+
+// diffSets appends the IDs added to and removed from set a in set b to changes, in ID order.
+func diffSets(changes []Change, field string, a, b map[int]bool) []Change {
+	var ids []int
+	for id, ok := range a {
+		if ok && !b[id] {
+			ids = append(ids, id)
+		}
+	}
+	for id, ok := range b {
+		if ok && !a[id] {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		if a[id] {
+			changes = append(changes, Change{Kind: ChangeRemoved, Field: field, ID: id})
+		} else {
+			changes = append(changes, Change{Kind: ChangeAdded, Field: field, ID: id})
+		}
+	}
+	return changes
+}
+
+// diffRanges appends the vendors removed from the merged ranges a, and added in the merged ranges
+// b, to changes as ranges, in vendor order.
+func diffRanges(changes []Change, field string, a, b []*RangeEntry) []Change {
+	var ranged []Change
+	for _, re := range subtractRanges(a, b) {
+		ranged = append(ranged, rangeChange(Change{Kind: ChangeRemoved, Field: field}, re))
+	}
+	for _, re := range subtractRanges(b, a) {
+		ranged = append(ranged, rangeChange(Change{Kind: ChangeAdded, Field: field}, re))
+	}
+	sortByID(ranged)
+	return append(changes, ranged...)
+}
+
+// rangeChange returns c, changing the vendors of re.
+func rangeChange(c Change, re *RangeEntry) Change {
+	c.ID = re.StartVendorID
+	if re.EndVendorID > re.StartVendorID {
+		c.EndID = re.EndVendorID
+	}
+	return c
+}
+
+// sortByID sorts changes by ID, keeping the order of changes with the same ID.
+func sortByID(changes []Change) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].ID < changes[j].ID
+	})
+}
+
+// diffRestrictions appends the publisher restrictions added, removed or changed in type from a to
+// b to changes, as ranges of vendors ordered by purpose, then vendor.
+func diffRestrictions(changes []Change, a, b []*PubRestrictionEntry) []Change {
+	var ra, rb = restrictionRanges(a), restrictionRanges(b)
+	// The restriction types used under each purpose, in either a or b.
+	var types = make(map[int]map[RestrictionType]bool)
+	for _, groups := range []map[restrictionGroup][]*RangeEntry{ra, rb} {
+		for g := range groups {
+			if types[g.purpose] == nil {
+				types[g.purpose] = make(map[RestrictionType]bool)
+			}
+			types[g.purpose][g.t] = true
+		}
+	}
+	var purposes = make([]int, 0, len(types))
+	for purpose := range types {
+		purposes = append(purposes, purpose)
+	}
+	sort.Ints(purposes)
+
+	const field = "pub_restriction_entries"
+	for _, purpose := range purposes {
+		var ts = make([]RestrictionType, 0, len(types[purpose]))
+		for t := range types[purpose] {
+			ts = append(ts, t)
+		}
+		sort.Slice(ts, func(i, j int) bool { return ts[i] < ts[j] })
+
+		var allA, allB []*RangeEntry
+		for _, t := range ts {
+			allA = unionRanges(allA, ra[restrictionGroup{purpose: purpose, t: t}])
+			allB = unionRanges(allB, rb[restrictionGroup{purpose: purpose, t: t}])
+		}
+		var ranged []Change
+		for _, ta := range ts {
+			var va = ra[restrictionGroup{purpose: purpose, t: ta}]
+			for _, re := range subtractRanges(va, allB) {
+				ranged = append(ranged, rangeChange(Change{Kind: ChangeRemoved, Field: field, PurposeID: purpose, Old: ta.String()}, re))
+			}
+			for _, tb := range ts {
+				if ta == tb {
+					continue
+				}
+				for _, re := range intersectRanges(va, rb[restrictionGroup{purpose: purpose, t: tb}]) {
+					ranged = append(ranged, rangeChange(Change{Kind: ChangeModified, Field: field, PurposeID: purpose,
+						Old: ta.String(), New: tb.String()}, re))
+				}
+			}
+		}
+		for _, tb := range ts {
+			for _, re := range subtractRanges(rb[restrictionGroup{purpose: purpose, t: tb}], allA) {
+				ranged = append(ranged, rangeChange(Change{Kind: ChangeAdded, Field: field, PurposeID: purpose, New: tb.String()}, re))
+			}
+		}
+		sortByID(ranged)
+		changes = append(changes, ranged...)
+	}
+	return changes
+}
+
+// DiffMspa returns the changes from consent a to consent b, in the order the fields are encoded,
+// followed by GPC. Bit field elements encoded by only one of the consents, e.g. when comparing
+// sections with a different number of sensitive data categories, are added or removed. The
+// section IDs and versions of a and b are not compared. A nil consent is compared as a consent with
+// every field 0 (not applicable).
+func DiffMspa(a, b *MspaParsedConsent) []Change {
+	if a == nil {
+		a = &MspaParsedConsent{}
+	}
+	if b == nil {
+		b = &MspaParsedConsent{}
+	}
+	var changes []Change
+	for f := MspaFieldSharingNotice; f <= MspaFieldServiceProviderMode; f++ {
+		switch va := a.fieldPtr(f).(type) {
+		case *MspaNotice:
+			changes = diffValue(changes, f.String(), va.String(), b.fieldPtr(f).(*MspaNotice).String())
+		case *MspaOptout:
+			changes = diffValue(changes, f.String(), va.String(), b.fieldPtr(f).(*MspaOptout).String())
+		case *MspaConsent:
+			changes = diffValue(changes, f.String(), va.String(), b.fieldPtr(f).(*MspaConsent).String())
+		case *MspaNaYesNo:
+			changes = diffValue(changes, f.String(), va.String(), b.fieldPtr(f).(*MspaNaYesNo).String())
+		case *map[int]MspaConsent:
+			changes = diffBitField(changes, f.String(), consentNames(*va), consentNames(*b.fieldPtr(f).(*map[int]MspaConsent)))
+		case *map[int]MspaOptout:
+			changes = diffBitField(changes, f.String(), optOutNames(*va), optOutNames(*b.fieldPtr(f).(*map[int]MspaOptout)))
+		}
+	}
+	return diffValue(changes, "gpc", strconv.FormatBool(a.Gpc), strconv.FormatBool(b.Gpc))
+}
+
+// diffValue appends a modification of field to changes if old and new differ.
+func diffValue(changes []Change, field, old, new string) []Change {
+	if old == new {
+		return changes
+	}
+	return append(changes, Change{Kind: ChangeModified, Field: field, Old: old, New: new})
+}
+
+// diffBitField appends the changes to the elements of a bit field, whose values in consents a and b
+// are named by a and b, to changes in index order.
+func diffBitField(changes []Change, field string, a, b map[int]string) []Change {
+	var indexes []int
+	for i := range a {
+		indexes = append(indexes, i)
+	}
+	for i := range b {
+		if _, ok := a[i]; !ok {
+			indexes = append(indexes, i)
+		}
+	}
+	sort.Ints(indexes)
+	for _, i := range indexes {
+		var name = field + "[" + strconv.Itoa(i) + "]"
+		var va, inA = a[i]
+		var vb, inB = b[i]
+		switch {
+		case inA && inB:
+			changes = diffValue(changes, name, va, vb)
+		case inA:
+			changes = append(changes, Change{Kind: ChangeRemoved, Field: name, Old: va})
+		default:
+			changes = append(changes, Change{Kind: ChangeAdded, Field: name, New: vb})
+		}
+	}
+	return changes
+}
+
+func consentNames(m map[int]MspaConsent) map[int]string {
+	var names = make(map[int]string, len(m))
+	for i, v := range m {
+		names[i] = v.String()
+	}
+	return names
+}
+
+func optOutNames(m map[int]MspaOptout) map[int]string {
+	var names = make(map[int]string, len(m))
+	for i, v := range m {
+		names[i] = v.String()
+	}
+	return names
+}
@@ -0,0 +1,165 @@
+package iabconsent_test
+
+import (
+	"time"
+
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type DiffSuite struct{}
+
+var _ = check.Suite(&DiffSuite{})
+
+func (s *DiffSuite) TestDiffV2(c *check.C) {
+	var t1 = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var a = mergeConsent(t1, map[int]bool{1: true, 2: true, 3: true}, nil)
+	a.PurposesConsent = map[int]bool{1: true, 2: true, 3: true}
+	a.PubRestrictionEntries = []*iabconsent.PubRestrictionEntry{
+		{
+			PurposeID:         2,
+			RestrictionType:   iabconsent.RequireConsent,
+			NumEntries:        1,
+			RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 1, EndVendorID: 2}},
+		},
+	}
+
+	var b = mergeConsent(t1.Add(time.Hour), nil, nil)
+	b.PurposesConsent = map[int]bool{1: true, 3: true, 4: true}
+	b.IsConsentRangeEncoding = true
+	b.MaxConsentVendorID = 4
+	b.ConsentedVendorsRange = []*iabconsent.RangeEntry{{StartVendorID: 2, EndVendorID: 4}}
+	b.PubRestrictionEntries = []*iabconsent.PubRestrictionEntry{
+		{
+			PurposeID:         2,
+			RestrictionType:   iabconsent.PurposeFlatlyNotAllowed,
+			NumEntries:        1,
+			RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 2, EndVendorID: 3}},
+		},
+	}
+
+	var changes []string
+	for _, ch := range iabconsent.DiffV2(a, b) {
+		changes = append(changes, ch.String())
+	}
+	c.Check(changes, check.DeepEquals, []string{
+		"purposes_consent 2 removed",
+		"purposes_consent 4 added",
+		"consented_vendors 1 removed",
+		"consented_vendors 4 added",
+		"pub_restriction_entries purpose 2 vendor 1 removed: require_consent",
+		"pub_restriction_entries purpose 2 vendor 2: require_consent -> purpose_flatly_not_allowed",
+		"pub_restriction_entries purpose 2 vendor 3 added: purpose_flatly_not_allowed",
+	})
+}
+
+func (s *DiffSuite) TestDiffV2Encodings(c *check.C) {
+	// The same vendors encoded as a bit field and as ranges do not differ.
+	var t1 = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var a = mergeConsent(t1, map[int]bool{5: true, 6: true, 7: true}, &iabconsent.OOBVendorList{
+		SegmentType: iabconsent.DisclosedVendors,
+		MaxVendorID: 7,
+		Vendors:     map[int]bool{5: true, 6: true, 7: true},
+	})
+	var b = mergeConsent(t1, nil, &iabconsent.OOBVendorList{
+		SegmentType:     iabconsent.DisclosedVendors,
+		MaxVendorID:     7,
+		IsRangeEncoding: true,
+		NumEntries:      1,
+		VendorEntries:   []*iabconsent.RangeEntry{{StartVendorID: 5, EndVendorID: 7}},
+	})
+	b.IsConsentRangeEncoding = true
+	b.ConsentedVendorsRange = []*iabconsent.RangeEntry{{StartVendorID: 5, EndVendorID: 7}}
+
+	c.Check(iabconsent.DiffV2(a, b), check.HasLen, 0)
+	c.Check(iabconsent.DiffV2(a, a), check.HasLen, 0)
+}
+
+func (s *DiffSuite) TestDiffV2Ranges(c *check.C) {
+	// Vendor changes are reported as ranges, without expanding them, and nil entries are skipped.
+	var a = &iabconsent.V2ParsedConsent{
+		IsConsentRangeEncoding: true,
+		ConsentedVendorsRange: []*iabconsent.RangeEntry{
+			{StartVendorID: 1, EndVendorID: 65535},
+			nil,
+		},
+		PubRestrictionEntries: []*iabconsent.PubRestrictionEntry{
+			nil,
+			{
+				PurposeID:         3,
+				RestrictionType:   iabconsent.RequireConsent,
+				RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 1, EndVendorID: 65535}, nil},
+			},
+		},
+	}
+	var b = &iabconsent.V2ParsedConsent{
+		IsConsentRangeEncoding: true,
+		ConsentedVendorsRange: []*iabconsent.RangeEntry{
+			{StartVendorID: 50, EndVendorID: 100},
+			{StartVendorID: 1, EndVendorID: 60},
+		},
+		PubRestrictionEntries: []*iabconsent.PubRestrictionEntry{
+			{
+				PurposeID:         3,
+				RestrictionType:   iabconsent.RequireLegitimateInterest,
+				RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 10, EndVendorID: 65535}},
+			},
+		},
+	}
+
+	var changes []string
+	for _, ch := range iabconsent.DiffV2(a, b) {
+		changes = append(changes, ch.String())
+	}
+	c.Check(changes, check.DeepEquals, []string{
+		"consented_vendors 101-65535 removed",
+		"pub_restriction_entries purpose 3 vendors 1-9 removed: require_consent",
+		"pub_restriction_entries purpose 3 vendors 10-65535: require_consent -> require_legitimate_interest",
+	})
+	c.Check(iabconsent.DiffV2(b, a)[0], check.Equals, iabconsent.Change{
+		Kind:  iabconsent.ChangeAdded,
+		Field: "consented_vendors",
+		ID:    101,
+		EndID: 65535,
+	})
+}
+
+func (s *DiffSuite) TestDiffMspa(c *check.C) {
+	var ga, err = iabconsent.ParseGppConsent("DBABLA~BVVqAAEABCA.YA")
+	c.Assert(err, check.IsNil)
+	var gb *iabconsent.GppConsent
+	gb, err = iabconsent.ParseGppConsent("DBABLA~BVVqAAEABCA.YA")
+	c.Assert(err, check.IsNil)
+	var a, b = ga.UsNational(), gb.UsNational()
+	c.Check(iabconsent.DiffMspa(a, b), check.HasLen, 0)
+
+	b.SaleOptOut = iabconsent.OptedOut
+	b.SensitiveDataProcessingConsents[2] = iabconsent.Consent
+	delete(b.SensitiveDataProcessingConsents, 11)
+	b.Gpc = false
+
+	var changes []string
+	for _, ch := range iabconsent.DiffMspa(a, b) {
+		changes = append(changes, ch.String())
+	}
+	c.Check(changes, check.DeepEquals, []string{
+		"sale_opt_out: not_opted_out -> opted_out",
+		"sensitive_data_processing_consents[2]: not_applicable -> consent",
+		"sensitive_data_processing_consents[11] removed: not_applicable",
+		"gpc: true -> false",
+	})
+
+	// A nil consent holds no signals.
+	c.Check(iabconsent.DiffMspa(nil, nil), check.HasLen, 0)
+	c.Check(iabconsent.DiffMspa(nil, a), check.DeepEquals, iabconsent.DiffMspa(&iabconsent.MspaParsedConsent{}, a))
+	c.Check(iabconsent.DiffMspa(a, nil), check.Not(check.HasLen), 0)
+}
+
+func (s *DiffSuite) TestDiffV2Nil(c *check.C) {
+	var p, err = iabconsent.ParseV2("COvzTO5OvzTO5BRAAAENAPCoALIAADgAAAAAAewAwABAAlAB6ABBFAAA")
+	c.Assert(err, check.IsNil)
+	c.Check(iabconsent.DiffV2(nil, nil), check.HasLen, 0)
+	c.Check(iabconsent.DiffV2(nil, p), check.DeepEquals, iabconsent.DiffV2(&iabconsent.V2ParsedConsent{}, p))
+	c.Check(iabconsent.DiffV2(p, nil), check.Not(check.HasLen), 0)
+}
@@ -0,0 +1,603 @@
+package iabconsent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ConsentDump is an annotated, bit-level listing of a TCF v1.1, TCF v2 or GPP consent string.
+// See Dump.
+type ConsentDump struct {
+	// Format is "tcfv1", "tcfv2" or "gpp", or empty if the format could not be determined.
+	Format   string         `json:"format"`
+	Segments []*DumpSegment `json:"segments"`
+}
+
+// DumpSegment is a single base64 encoded segment of a consent string, i.e. the text between
+// the "." and "~" separators.
+type DumpSegment struct {
+	// Name describes the segment, e.g. "core", "disclosed_vendors", "header" or "usnat.gpc".
+	Name string `json:"name"`
+	// Offset is the character offset of the segment in the consent string.
+	Offset  int    `json:"offset"`
+	Encoded string `json:"encoded"`
+	// Size is the number of bits encoded by the characters of the segment.
+	Size   int         `json:"size"`
+	Fields []DumpField `json:"fields"`
+	// Error describes the first problem found in the segment, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// DumpField is a single field of a segment. Bits following the last field are listed as
+// "padding" if they are all 0, and as "trailing" otherwise. Bits following a read error are
+// listed as "unread".
+type DumpField struct {
+	Name string `json:"name"`
+	// Offset is the bit offset of the field from the start of its segment.
+	Offset int `json:"offset"`
+	Width  int `json:"width"`
+	// Bits are the raw bits of the field, most significant first, e.g. "000010".
+	Bits  string `json:"bits"`
+	Value string `json:"value"`
+}
+
+// base64URLAlphabet is the alphabet of base64.RawURLEncoding, used to recover the bits of each character.
+const base64URLAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+// Dump walks consent string s with a ConsentReader the same way as ParseV1, ParseV2 and ParseGppConsent,
+// and lists every field read with its name, bit offset, width, raw bits and decoded value. The format of
+// s is taken from its first 6 bits, which hold the TCF version or the GPP header type.
+//
+// Unlike the parse functions, Dump does not stop at the first invalid value, and stops reading a segment
+// only when a field cannot be read, so strings which fail to parse can be inspected up to the point of
+// failure. The returned error is the first problem found, and every problem is also recorded in the
+// Error of its segment. The returned ConsentDump is never nil.
+//
+// As s is usually untrusted, Dump enforces the limits of DefaultParseOptions, and stops reading a
+// segment once one is exceeded, as the parse functions do.
+func Dump(s string) (cd *ConsentDump, err error) {
+	cd = &ConsentDump{}
+	defer recoverPanic(&err)
+	if err = checkLimit("length", len(s), DefaultParseOptions.MaxLength); err != nil {
+		return cd, err
+	}
+	var version = -1
+	if s != "" {
+		version = strings.IndexByte(base64URLAlphabet, s[0])
+	}
+	switch version {
+	case int(V1):
+		cd.Format = "tcfv1"
+		dumpV1(newSegmentDumper(cd, "core", 0, s, 0))
+	case int(V2):
+		cd.Format = "tcfv2"
+		dumpV2(cd, "", s, 0)
+	case 3:
+		cd.Format = "gpp"
+		dumpGpp(cd, s)
+	default:
+		var d = newSegmentDumper(cd, "core", 0, strings.SplitN(s, ".", 2)[0], 0)
+		d.int("version", 6)
+		d.invalid(errors.New("unrecognized consent string"))
+		d.finish()
+	}
+	for _, seg := range cd.Segments {
+		if seg.Error != "" {
+			return cd, errors.New(seg.Name + ": " + seg.Error)
+		}
+	}
+	return cd, nil
+}
+
+// segmentDumper reads the fields of one segment, recording each into its DumpSegment.
+type segmentDumper struct {
+	r    *ConsentReader
+	seg  *DumpSegment
+	bits string
+	// failed is set once a field could not be read.
+	failed bool
+}
+
+// newSegmentDumper returns a segmentDumper of segment encoded, which starts at character offset of the
+// consent string, and appends its DumpSegment to cd. As ParseGppHeader does, extra 0 bits are appended
+// to the segment before it is decoded to bytes, which drops any bits that do not fill a byte.
+func newSegmentDumper(cd *ConsentDump, name string, offset int, encoded string, extra int) *segmentDumper {
+	var d = &segmentDumper{seg: &DumpSegment{Name: name, Offset: offset, Encoded: encoded, Size: 6 * len(encoded)}}
+	cd.Segments = append(cd.Segments, d.seg)
+
+	var b strings.Builder
+	for i := 0; i < len(encoded); i++ {
+		var v = strings.IndexByte(base64URLAlphabet, encoded[i])
+		if v < 0 {
+			d.invalid(errors.Errorf("invalid base64 character %q at offset %d", encoded[i], offset+i))
+			v = 0
+		}
+		fmt.Fprintf(&b, "%06b", v)
+	}
+	var size = (d.seg.Size + extra) / 8 * 8
+	d.bits = b.String() + strings.Repeat("0", size)
+	d.r = NewConsentReaderWithOptions(bitsToBytes(d.bits[:size]), DefaultParseOptions)
+	return d
+}
+
+// bitsToBytes packs a string of '0' and '1' with a length divisible by 8 into bytes.
+func bitsToBytes(bits string) []byte {
+	var b = make([]byte, len(bits)/8)
+	for i := range b {
+		var v, _ = strconv.ParseUint(bits[8*i:8*i+8], 2, 8)
+		b[i] = byte(v)
+	}
+	return b
+}
+
+func (d *segmentDumper) offset() int {
+	return d.r.Size() - d.r.NumUnread()
+}
+
+// invalid records err against the segment, without stopping the dump.
+func (d *segmentDumper) invalid(err error) {
+	if d.seg.Error == "" {
+		d.seg.Error = err.Error()
+	}
+}
+
+// field records the field read by read, which returns the decoded value. It returns false, and
+// reads nothing further from the segment, once any field could not be read.
+func (d *segmentDumper) field(name string, read func() (string, error)) bool {
+	if d.failed {
+		return false
+	}
+	var start = d.offset()
+	var value, err = read()
+	var end = d.offset()
+	if err != nil {
+		value = ""
+	}
+	d.seg.Fields = append(d.seg.Fields, DumpField{
+		Name:   name,
+		Offset: start,
+		Width:  end - start,
+		Bits:   d.bits[start:end],
+		Value:  value,
+	})
+	if err != nil {
+		d.failed = true
+		d.invalid(errors.WithMessage(err, name))
+		return false
+	}
+	return true
+}
+
+// limit fails the segment with a *LimitExceededError if v exceeds max, as the parse functions do.
+// It returns false, and reads nothing further from the segment, once any field could not be read.
+func (d *segmentDumper) limit(limit string, v, max int) bool {
+	if d.failed {
+		return false
+	}
+	if err := d.r.checkLimit(limit, v, max); err != nil {
+		d.failed = true
+		d.invalid(err)
+		return false
+	}
+	return true
+}
+
+// finish records the bits following the last field read.
+func (d *segmentDumper) finish() {
+	var start = d.offset()
+	if start >= d.seg.Size {
+		return
+	}
+	var rest = d.bits[start:d.seg.Size]
+	var name = "padding"
+	if d.failed {
+		name = "unread"
+	} else if strings.Contains(rest, "1") {
+		name = "trailing"
+	}
+	d.seg.Fields = append(d.seg.Fields, DumpField{Name: name, Offset: start, Width: len(rest), Bits: rest})
+}
+
+func (d *segmentDumper) int(name string, n uint) (int, bool) {
+	var v int
+	var ok = d.field(name, func() (string, error) {
+		var err error
+		v, err = d.r.ReadInt(n)
+		return strconv.Itoa(v), err
+	})
+	return v, ok
+}
+
+// enum reads an n bit field, and returns it along with its name as returned by format.
+func (d *segmentDumper) enum(name string, n uint, format func(int) string) (int, bool) {
+	var v int
+	var ok = d.field(name, func() (string, error) {
+		var err error
+		v, err = d.r.ReadInt(n)
+		return format(v), err
+	})
+	return v, ok
+}
+
+func (d *segmentDumper) bool(name string) (bool, bool) {
+	var v bool
+	var ok = d.field(name, func() (string, error) {
+		var err error
+		v, err = d.r.ReadBool()
+		return strconv.FormatBool(v), err
+	})
+	return v, ok
+}
+
+func (d *segmentDumper) time(name string) bool {
+	return d.field(name, func() (string, error) {
+		var t, err = d.r.ReadTime()
+		return t.Format(time.RFC3339Nano), err
+	})
+}
+
+func (d *segmentDumper) string(name string, n uint) bool {
+	return d.field(name, func() (string, error) {
+		return d.r.ReadString(n)
+	})
+}
+
+// bitField reads an n bit field, listing the IDs which are set.
+func (d *segmentDumper) bitField(name string, n uint) (map[int]bool, bool) {
+	var m map[int]bool
+	var ok = d.field(name, func() (string, error) {
+		var err error
+		m, err = d.r.ReadBitField(n)
+		return formatIDs(idsOf(m)), err
+	})
+	return m, ok
+}
+
+// rangeEntries reads n range entries, listing the fields of each entry as name[i].field.
+func (d *segmentDumper) rangeEntries(name string, n int) bool {
+	d.r.rangeEntries += n
+	if !d.limit("range entries", d.r.rangeEntries, d.r.opts.MaxRangeEntries) {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		var prefix = fmt.Sprintf("%s[%d].", name, i)
+		var isRange, ok = d.bool(prefix + "is_range")
+		if !ok {
+			return false
+		}
+		var end int
+		if end, ok = d.int(prefix+"start_vendor_id", 16); !ok {
+			return false
+		}
+		if isRange {
+			if end, ok = d.int(prefix+"end_vendor_id", 16); !ok {
+				return false
+			}
+		}
+		if !d.limit("vendor id", end, d.r.opts.MaxVendorID) {
+			return false
+		}
+	}
+	return true
+}
+
+// vendorBitField reads a bit field of the vendors up to maxVendorID, once maxVendorID is checked
+// against the limits.
+func (d *segmentDumper) vendorBitField(name string, maxVendorID int) {
+	if d.limit("vendor id", maxVendorID, d.r.opts.MaxVendorID) {
+		d.bitField(name, uint(maxVendorID))
+	}
+}
+
+// fibonacciRange reads a Fibonacci encoded range, listing the fields of each group as name[i].field.
+// See ReadFibonacciRange.
+func (d *segmentDumper) fibonacciRange(name string) ([]int, bool) {
+	var n, ok = d.int("num_"+name, 12)
+	if !ok {
+		return nil, false
+	}
+	var ids []int
+	for i := 0; i < n; i++ {
+		var prefix = fmt.Sprintf("%s[%d].", name, i)
+		var isRange bool
+		if isRange, ok = d.bool(prefix + "is_range"); !ok {
+			return ids, false
+		}
+		var last = 0
+		if len(ids) > 0 {
+			last = ids[len(ids)-1]
+		}
+		var start int
+		ok = d.field(prefix+"offset", func() (string, error) {
+			var offset, err = d.r.ReadFibonacciInt()
+			start = last + offset
+			return fmt.Sprintf("%d (id %d)", offset, start), err
+		})
+		if !ok {
+			return ids, false
+		}
+		if !isRange {
+			if !d.limit("gpp sections", len(ids)+1, d.r.opts.MaxGppSections) {
+				return ids, false
+			}
+			ids = append(ids, start)
+			continue
+		}
+		ok = d.field(prefix+"length", func() (string, error) {
+			var length, err = d.r.ReadFibonacciInt()
+			if err != nil {
+				return "", err
+			}
+			// Check the length of the group before expanding it, as ReadFibonacciRange does.
+			if err = d.r.checkLimit("gpp sections", len(ids)+length+1, d.r.opts.MaxGppSections); err != nil {
+				return "", err
+			}
+			for id := start; id <= start+length; id++ {
+				ids = append(ids, id)
+			}
+			return fmt.Sprintf("%d (ids %d-%d)", length, start, start+length), nil
+		})
+		if !ok {
+			return ids, false
+		}
+	}
+	return ids, true
+}
+
+// formatIDs formats ids as e.g. "[1, 3, 4]".
+func formatIDs(ids []int) string {
+	var s = make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = strconv.Itoa(id)
+	}
+	return "[" + strings.Join(s, ", ") + "]"
+}
+
+// dumpV1 dumps the fields of a TCF v1.1 consent string, as read by ParseV1.
+func dumpV1(d *segmentDumper) {
+	defer d.finish()
+	if v, _ := d.int("version", 6); v != int(V1) {
+		d.invalid(errors.New("non-v1 string passed to v1 parse method"))
+	}
+	d.time("created")
+	d.time("last_updated")
+	d.int("cmp_id", 12)
+	d.int("cmp_version", 12)
+	d.int("consent_screen", 6)
+	d.string("consent_language", 2)
+	d.int("vendor_list_version", 12)
+	d.bitField("purposes_allowed", 24)
+	var maxVendorID, _ = d.int("max_vendor_id", 16)
+	if isRange, ok := d.bool("is_range_encoding"); !ok {
+		return
+	} else if isRange {
+		d.bool("default_consent")
+		var n, _ = d.int("num_entries", 12)
+		d.rangeEntries("range_entries", n)
+	} else {
+		d.vendorBitField("consented_vendors", maxVendorID)
+	}
+}
+
+// dumpV2 dumps the segments of TCF v2 consent string s, as read by ParseV2. Segment names are prefixed
+// with prefix, and offsets are relative to offset.
+func dumpV2(cd *ConsentDump, prefix, s string, offset int) {
+	var segments = strings.Split(s, ".")
+	var d = newSegmentDumper(cd, prefix+"core", offset, segments[0], 0)
+	dumpV2Core(d)
+	d.finish()
+
+	offset += len(segments[0]) + 1
+	for i, segment := range segments[1:] {
+		d = newSegmentDumper(cd, prefix+"segment "+strconv.Itoa(i+1), offset, segment, 0)
+		offset += len(segment) + 1
+
+		var st, ok = d.enum("segment_type", 3, func(v int) string { return SegmentType(v).String() })
+		if ok {
+			d.seg.Name = prefix + SegmentType(st).String()
+		}
+		switch SegmentType(st) {
+		case DisclosedVendors, AllowedVendors:
+			dumpOOBVendors(d)
+		case PublisherTC:
+			dumpPublisherTC(d)
+		default:
+			d.invalid(errors.New("unrecognized segment type"))
+		}
+		d.finish()
+	}
+}
+
+func dumpV2Core(d *segmentDumper) {
+	if v, _ := d.int("version", 6); v != int(V2) {
+		d.invalid(errors.New("non-v2 string passed to v2 parse method"))
+	}
+	d.time("created")
+	d.time("last_updated")
+	d.int("cmp_id", 12)
+	d.int("cmp_version", 12)
+	d.int("consent_screen", 6)
+	d.string("consent_language", 2)
+	d.int("vendor_list_version", 12)
+	var policyVersion, _ = d.int("tcf_policy_version", 6)
+	d.bool("is_service_specific")
+	d.bool("use_non_standard_stacks")
+	d.bitField("special_features_opt_in", 12)
+	d.bitField("purposes_consent", 24)
+	var lit, ok = d.bitField("purposes_li_transparency", 24)
+	if mv, _ := (&V2ParsedConsent{TCFPolicyVersion: policyVersion}).MinorVersion(); ok && mv >= 2 {
+		for p := 3; p <= 6; p++ {
+			if lit[p] {
+				d.invalid(errors.Errorf("TCF String Version 2.2 or higher has invalid PurposesLIT %d not set to 0.", p))
+			}
+		}
+	}
+	d.bool("purpose_one_treatment")
+	d.string("publisher_cc", 2)
+
+	dumpVendors(d, "consent", "consented_vendors")
+	dumpVendors(d, "interests", "interests_vendors")
+
+	var n, _ = d.int("num_pub_restrictions", 12)
+	if !d.limit("pub restrictions", n, d.r.opts.MaxPubRestrictions) {
+		return
+	}
+	for i := 0; i < n; i++ {
+		var prefix = fmt.Sprintf("pub_restriction_entries[%d].", i)
+		d.int(prefix+"purpose_id", 6)
+		d.enum(prefix+"restriction_type", 2, func(v int) string { return RestrictionType(v).String() })
+		var entries, _ = d.int(prefix+"num_entries", 12)
+		if !d.rangeEntries(prefix+"restrictions_range", entries) {
+			return
+		}
+	}
+}
+
+// dumpVendors dumps the consented or legitimate interest vendors of a v2 core string, where kind is
+// "consent" or "interests" and vendors is the name of the vendor list.
+func dumpVendors(d *segmentDumper, kind, vendors string) {
+	var maxVendorID, _ = d.int("max_"+kind+"_vendor_id", 16)
+	if isRange, ok := d.bool("is_" + kind + "_range_encoding"); !ok {
+		return
+	} else if isRange {
+		var n, _ = d.int("num_"+kind+"_entries", 12)
+		d.rangeEntries(vendors+"_range", n)
+	} else {
+		d.vendorBitField(vendors, maxVendorID)
+	}
+}
+
+func dumpOOBVendors(d *segmentDumper) {
+	var maxVendorID, _ = d.int("max_vendor_id", 16)
+	if isRange, ok := d.bool("is_range_encoding"); !ok {
+		return
+	} else if isRange {
+		var n, _ = d.int("num_entries", 12)
+		d.rangeEntries("vendor_entries", n)
+	} else {
+		d.vendorBitField("vendors", maxVendorID)
+	}
+}
+
+func dumpPublisherTC(d *segmentDumper) {
+	d.bitField("pub_purposes_consent", 24)
+	d.bitField("pub_purposes_li_transparency", 24)
+	var n, _ = d.int("num_custom_purposes", 6)
+	d.bitField("custom_purposes_consent", uint(n))
+	d.bitField("custom_purposes_li_transparency", uint(n))
+}
+
+// dumpGpp dumps the header and sections of GPP string s, as read by ParseGppConsent.
+func dumpGpp(cd *ConsentDump, s string) {
+	var segments = strings.Split(s, "~")
+	var h = newSegmentDumper(cd, "header", 0, segments[0], 6)
+	if t, _ := h.int("type", 6); t != 3 {
+		h.invalid(errors.New("wrong gpp header type " + fmt.Sprint(t)))
+	}
+	if v, _ := h.int("version", 6); v != 1 {
+		h.invalid(errors.New("unsupported gpp version " + fmt.Sprint(v)))
+	}
+	var sids, _ = h.fibonacciRange("sections")
+	h.finish()
+	if len(segments) < 2 {
+		h.invalid(errors.New("not enough gpp segments"))
+	} else if len(segments[1:]) != len(sids) {
+		h.invalid(errors.New("mismatch number of sections"))
+	}
+
+	var offset = len(segments[0]) + 1
+	for i, section := range segments[1:] {
+		var name = "section " + strconv.Itoa(i+1)
+		var sid = 0
+		if i < len(sids) {
+			sid = sids[i]
+			if name = GppSectionName(sid); name == "" {
+				name = "section " + strconv.Itoa(sid)
+			}
+		}
+		switch {
+		case sid == TcfEuV2SID:
+			dumpV2(cd, name+".", section, offset)
+		case mspaSections[sid].name != "":
+			dumpMspa(cd, sid, section, offset)
+		default:
+			// Unsupported sections are listed without fields.
+			var d = newSegmentDumper(cd, name, offset, section, 0)
+			d.seg.Fields = []DumpField{}
+		}
+		offset += len(section) + 1
+	}
+}
+
+// dumpMspa dumps the core segment and subsections of MSPA section value, as read by NewMspa(sid, value).ParseConsent().
+func dumpMspa(cd *ConsentDump, sid int, value string, offset int) {
+	var section = mspaSections[sid]
+	var segments = strings.Split(value, ".")
+	var d = newSegmentDumper(cd, section.name, offset, segments[0], 0)
+	var version, _ = d.int("version", 6)
+	if format, ok := section.versions[version]; !ok {
+		d.invalid(errors.New("unsupported version: " + fmt.Sprint(version)))
+	} else {
+		if d.r.Size() != format.length {
+			d.invalid(errors.New("invalid consent string length for v" + fmt.Sprint(version)))
+		}
+		for _, f := range format.fields {
+			if !dumpMspaField(d, f) {
+				break
+			}
+		}
+	}
+	d.finish()
+
+	offset += len(segments[0]) + 1
+	for _, sub := range segments[1:] {
+		d = newSegmentDumper(cd, section.name+".subsection", offset, sub, 0)
+		offset += len(sub) + 1
+		var t, ok = d.enum("subsection_type", 2, func(v int) string {
+			if GppSubSectionTypes(v) == SubSectGpc {
+				return "gpc"
+			}
+			return strconv.Itoa(v)
+		})
+		if ok && GppSubSectionTypes(t) == SubSectGpc {
+			d.seg.Name = section.name + ".gpc"
+			d.bool("gpc")
+		}
+		d.finish()
+	}
+}
+
+// dumpMspaField dumps field f of an MSPA core segment, listing each value of a bitfield as name[i].
+func dumpMspaField(d *segmentDumper, f mspaFieldSpec) bool {
+	var p MspaParsedConsent
+	var name = f.field.String()
+	var format func(int) string
+	var n = f.n
+	switch p.fieldPtr(f.field).(type) {
+	case *MspaNotice:
+		format = func(v int) string { return MspaNotice(v).String() }
+	case *MspaOptout, *map[int]MspaOptout:
+		format = func(v int) string { return MspaOptout(v).String() }
+	case *MspaConsent, *map[int]MspaConsent:
+		format = func(v int) string { return MspaConsent(v).String() }
+	case *MspaNaYesNo:
+		format = func(v int) string { return MspaNaYesNo(v).String() }
+	default:
+		d.invalid(errors.New("unknown mspa field " + fmt.Sprint(int(f.field))))
+		return false
+	}
+	if n == 0 {
+		var _, ok = d.enum(name, 2, format)
+		return ok
+	}
+	for i := uint(0); i < n; i++ {
+		if _, ok := d.enum(fmt.Sprintf("%s[%d]", name, i), 2, format); !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,136 @@
+package iabconsent_test
+
+import (
+	"strings"
+
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type DumpSuite struct{}
+
+var _ = check.Suite(&DumpSuite{})
+
+// checkDumpLayout checks that the fields of every segment of d are contiguous, and cover each segment.
+func checkDumpLayout(c *check.C, d *iabconsent.ConsentDump) {
+	for _, seg := range d.Segments {
+		var offset = 0
+		for _, f := range seg.Fields {
+			c.Check(f.Offset, check.Equals, offset, check.Commentf("%s %s", seg.Name, f.Name))
+			c.Check(f.Bits, check.HasLen, f.Width, check.Commentf("%s %s", seg.Name, f.Name))
+			offset += f.Width
+		}
+		if len(seg.Fields) > 0 {
+			c.Check(offset >= seg.Size, check.Equals, true, check.Commentf("%s", seg.Name))
+		}
+	}
+}
+
+// dumpField returns the field of seg named name.
+func dumpField(seg *iabconsent.DumpSegment, name string) iabconsent.DumpField {
+	for _, f := range seg.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return iabconsent.DumpField{}
+}
+
+func (s *DumpSuite) TestDumpFixtures(c *check.C) {
+	var strs = []string{"BONMj34ONMj34ABACDENALqAAAAAplY", "BONMj34ONMj34ABACDENALqAAAAAqACgD2AdUBWQHIAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"}
+	for str := range v2ConsentFixtures {
+		strs = append(strs, str)
+	}
+	for str := range gppParsedConsentFixtures {
+		strs = append(strs, str)
+	}
+	for _, str := range strs {
+		c.Log(str)
+		var d, err = iabconsent.Dump(str)
+		c.Check(err, check.IsNil)
+		checkDumpLayout(c, d)
+
+		var encoded []string
+		for _, seg := range d.Segments {
+			encoded = append(encoded, seg.Encoded)
+			c.Check(str[seg.Offset:seg.Offset+len(seg.Encoded)], check.Equals, seg.Encoded)
+		}
+		c.Check(len(strings.Join(encoded, ".")), check.Equals, len(str))
+	}
+}
+
+func (s *DumpSuite) TestDumpV2(c *check.C) {
+	var d, err = iabconsent.Dump("COvzTO5OvzTO5BRAAAENAPCoALIAADgAAAAAAewAwABAAlAB6ABBFAAA")
+	c.Assert(err, check.IsNil)
+	c.Check(d.Format, check.Equals, "tcfv2")
+	c.Assert(d.Segments, check.HasLen, 1)
+	var fields = d.Segments[0].Fields
+	c.Check(fields[0], check.Equals, iabconsent.DumpField{Name: "version", Offset: 0, Width: 6, Bits: "000010", Value: "2"})
+	c.Check(fields[1], check.Equals, iabconsent.DumpField{Name: "created", Offset: 6, Width: 36,
+		Bits: "001110101111110011010011001110111001", Value: "2020-03-05T19:24:40.9Z"})
+	c.Check(dumpField(d.Segments[0], "purposes_consent"), check.Equals, iabconsent.DumpField{Name: "purposes_consent", Offset: 152, Width: 24,
+		Bits: "101100100000000000000000", Value: "[1, 3, 4, 7]"})
+	c.Check(dumpField(d.Segments[0], "consented_vendors_range[0].start_vendor_id"), check.Equals, iabconsent.DumpField{Name: "consented_vendors_range[0].start_vendor_id", Offset: 243,
+		Width: 16, Bits: "0000000000000010", Value: "2"})
+	c.Check(fields[len(fields)-1], check.Equals, iabconsent.DumpField{Name: "padding", Offset: 330, Width: 6, Bits: "000000"})
+
+	d, err = iabconsent.Dump("COvwooAOvwooAB7ABCENAPEYAIAAADkAAIqIAAoAAoAA.QAAo.IAAo")
+	c.Check(err, check.ErrorMatches, "core: TCF String Version 2.2 or higher has invalid PurposesLIT 3 not set to 0.")
+	c.Assert(d.Segments, check.HasLen, 3)
+	c.Check(d.Segments[1].Name, check.Equals, "allowed_vendors")
+	c.Check(d.Segments[1].Offset, check.Equals, 45)
+	c.Check(d.Segments[2].Name, check.Equals, "disclosed_vendors")
+	c.Check(dumpField(d.Segments[2], "vendors"), check.Equals, iabconsent.DumpField{Name: "vendors", Offset: 20, Width: 1, Bits: "1", Value: "[1]"})
+}
+
+func (s *DumpSuite) TestDumpGpp(c *check.C) {
+	var d, err = iabconsent.Dump("DBACNYA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1YNN")
+	c.Assert(err, check.IsNil)
+	c.Check(d.Format, check.Equals, "gpp")
+	var names []string
+	for _, seg := range d.Segments {
+		names = append(names, seg.Name)
+	}
+	c.Check(names, check.DeepEquals, []string{"header", "tcfeuv2.core", "section 6"})
+	c.Check(dumpField(d.Segments[0], "sections[1].offset"), check.Equals, iabconsent.DumpField{Name: "sections[1].offset", Offset: 29,
+		Width: 4, Bits: "1011", Value: "4 (id 6)"})
+	c.Check(d.Segments[2].Fields, check.HasLen, 0)
+
+	d, err = iabconsent.Dump("DBABLA~CVVVVVVVVVVW.YA")
+	c.Assert(err, check.IsNil)
+	c.Check(dumpField(d.Segments[1], "service_provider_mode"), check.Equals, iabconsent.DumpField{Name: "service_provider_mode", Offset: 68,
+		Width: 2, Bits: "01", Value: "yes"})
+	c.Check(dumpField(d.Segments[1], "trailing"), check.Equals, iabconsent.DumpField{Name: "trailing", Offset: 70, Width: 2, Bits: "10"})
+	c.Check(d.Segments[2].Name, check.Equals, "usnat.gpc")
+	c.Check(dumpField(d.Segments[2], "gpc"), check.Equals, iabconsent.DumpField{Name: "gpc", Offset: 2, Width: 1, Bits: "1", Value: "true"})
+}
+
+func (s *DumpSuite) TestDumpInvalid(c *check.C) {
+	// A truncated v2 string is read up to the field which does not fit.
+	var d, err = iabconsent.Dump("COvzTO5OvzTO5BRAAAENAPCoAL")
+	c.Check(err, check.ErrorMatches, "core: purposes_consent: read bit field: .*index out of range")
+	checkDumpLayout(c, d)
+	var fields = d.Segments[0].Fields
+	c.Check(fields[len(fields)-2], check.Equals, iabconsent.DumpField{Name: "purposes_consent", Offset: 152})
+	c.Check(fields[len(fields)-1], check.Equals, iabconsent.DumpField{Name: "unread", Offset: 152, Width: 4, Bits: "1011"})
+
+	// MSPA sections of the wrong length are still read.
+	d, err = iabconsent.Dump("DBABLA~BVVqAAEABC")
+	c.Check(err, check.ErrorMatches, "usnat: invalid consent string length for v1")
+	c.Check(dumpField(d.Segments[1], "covered_transaction").Value, check.Equals, "not_applicable")
+	c.Check(dumpField(d.Segments[1], "unread"), check.Equals, iabconsent.DumpField{Name: "unread", Offset: 56, Width: 4, Bits: "0010"})
+
+	d, err = iabconsent.Dump("DBABLA~BVV*AAEABCA")
+	c.Check(err, check.ErrorMatches, `usnat: invalid base64 character '\*' at offset 10`)
+
+	// Declared counts are checked against the limits before they are read.
+	d, err = iabconsent.Dump("DBABrJFKCiEApIGA~BVVqAAEABCA")
+	c.Check(err, check.ErrorMatches, "header: sections\\[0\\].length: gpp sections 1000000000001 exceeds limit 64")
+	checkDumpLayout(c, d)
+
+	d, err = iabconsent.Dump("xyz")
+	c.Check(err, check.ErrorMatches, "core: unrecognized consent string")
+	c.Check(d.Format, check.Equals, "")
+	c.Check(d.Segments[0].Fields, check.HasLen, 2)
+}
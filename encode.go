@@ -0,0 +1,221 @@
+package iabconsent
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// EncodeV1 encodes p as a TCF v1.1 consent string, and is the inverse of ParseV1.
+// Vendors are written with the encoding selected by IsRangeEncoding, and the number
+// of range entries is taken from the length of RangeEntries rather than NumEntries.
+func EncodeV1(p *ParsedConsent) (string, error) {
+	if p == nil {
+		return "", errors.New("nil consent passed to v1 encode method")
+	}
+	if p.Version != int(V1) {
+		return "", errors.New("non-v1 consent passed to v1 encode method")
+	}
+	var w = NewConsentWriter()
+	w.WriteInt(p.Version, 6)
+	w.WriteTime(p.Created)
+	w.WriteTime(p.LastUpdated)
+	w.WriteInt(p.CMPID, 12)
+	w.WriteInt(p.CMPVersion, 12)
+	w.WriteInt(p.ConsentScreen, 6)
+	w.WriteString(p.ConsentLanguage, 2)
+	w.WriteInt(p.VendorListVersion, 12)
+	w.WriteBitField(p.PurposesAllowed, 24)
+	w.WriteInt(p.MaxVendorID, 16)
+	w.WriteBool(p.IsRangeEncoding)
+	if p.IsRangeEncoding {
+		w.WriteBool(p.DefaultConsent)
+		w.WriteInt(len(p.RangeEntries), 12)
+		w.WriteRangeEntries(p.RangeEntries)
+	} else {
+		w.WriteBitField(p.ConsentedVendors, uint(p.MaxVendorID))
+	}
+	if w.Err != nil {
+		return "", errors.Wrap(w.Err, "encode v1 consent string")
+	}
+	return encodeBytes(w), nil
+}
+
+// EncodeV2 encodes p as a TC String, and is the inverse of ParseV2. Vendors,
+// interests and OOB vendor lists are written with the encoding selected by their
+// IsRangeEncoding flag. Counts of entries (e.g. NumConsentEntries) are taken from
+// the length of the entries rather than the Num fields.
+func EncodeV2(p *V2ParsedConsent) (string, error) {
+	if p == nil {
+		return "", errors.New("nil consent passed to v2 encode method")
+	}
+	if p.Version != int(V2) {
+		return "", errors.New("non-v2 consent passed to v2 encode method")
+	}
+	var w = NewConsentWriter()
+	w.WriteInt(p.Version, 6)
+	w.WriteTime(p.Created)
+	w.WriteTime(p.LastUpdated)
+	w.WriteInt(p.CMPID, 12)
+	w.WriteInt(p.CMPVersion, 12)
+	w.WriteInt(p.ConsentScreen, 6)
+	w.WriteString(p.ConsentLanguage, 2)
+	w.WriteInt(p.VendorListVersion, 12)
+	w.WriteInt(p.TCFPolicyVersion, 6)
+	w.WriteBool(p.IsServiceSpecific)
+	w.WriteBool(p.UseNonStandardStacks)
+	w.WriteBitField(p.SpecialFeaturesOptIn, 12)
+	w.WriteBitField(p.PurposesConsent, 24)
+	w.WriteBitField(p.PurposesLITransparency, 24)
+	w.WriteBool(p.PurposeOneTreatment)
+	w.WriteString(p.PublisherCC, 2)
+
+	w.WriteInt(p.MaxConsentVendorID, 16)
+	w.WriteBool(p.IsConsentRangeEncoding)
+	if p.IsConsentRangeEncoding {
+		w.WriteInt(len(p.ConsentedVendorsRange), 12)
+		w.WriteRangeEntries(p.ConsentedVendorsRange)
+	} else {
+		w.WriteBitField(p.ConsentedVendors, uint(p.MaxConsentVendorID))
+	}
+
+	w.WriteInt(p.MaxInterestsVendorID, 16)
+	w.WriteBool(p.IsInterestsRangeEncoding)
+	if p.IsInterestsRangeEncoding {
+		w.WriteInt(len(p.InterestsVendorsRange), 12)
+		w.WriteRangeEntries(p.InterestsVendorsRange)
+	} else {
+		w.WriteBitField(p.InterestsVendors, uint(p.MaxInterestsVendorID))
+	}
+
+	w.WriteInt(len(p.PubRestrictionEntries), 12)
+	w.WritePubRestrictionEntries(p.PubRestrictionEntries)
+	if w.Err != nil {
+		return "", errors.Wrap(w.Err, "encode v2 core string")
+	}
+	var segments = []string{encodeBytes(w)}
+
+	for _, v := range []*OOBVendorList{p.OOBDisclosedVendors, p.OOBAllowedVendors} {
+		if v == nil {
+			continue
+		}
+		w = NewConsentWriter()
+		if err := w.WriteVendors(v); err != nil {
+			return "", errors.Wrap(err, "encode v2 "+v.SegmentType.String()+" segment")
+		}
+		segments = append(segments, encodeBytes(w))
+	}
+	if p.PublisherTCEntry != nil {
+		w = NewConsentWriter()
+		if err := w.WritePublisherTCEntry(p.PublisherTCEntry); err != nil {
+			return "", errors.Wrap(err, "encode v2 publisher_tc segment")
+		}
+		segments = append(segments, encodeBytes(w))
+	}
+	return strings.Join(segments, "."), nil
+}
+
+// encodeBytes returns the base64 encoding of w padded to a whole number of bytes,
+// which is how TC String segments are encoded.
+func encodeBytes(w *ConsentWriter) string {
+	w.Pad((w.Len() + 7) / 8 * 8)
+	return w.String()
+}
+
+// EncodeMspa encodes p as the value of the GPP section p.SID, using the format of
+// p.Version, and is the inverse of parsing the section. A GPC subsection is only
+// written if Gpc is set.
+func EncodeMspa(p *MspaParsedConsent) (string, error) {
+	if p == nil {
+		return "", errors.New("nil consent passed to mspa encode method")
+	}
+	var section, ok = mspaSections[p.SID]
+	if !ok {
+		return "", errors.Errorf("unsupported mspa section %d", p.SID)
+	}
+	format, ok := section.versions[p.Version]
+	if !ok {
+		return "", errors.Errorf("unsupported %s version: %d", section.name, p.Version)
+	}
+	var w = NewConsentWriter()
+	w.WriteInt(p.Version, 6)
+	for _, f := range format.fields {
+		w.WriteMspaField(p, f.field, f.n)
+	}
+	w.Pad(uint(format.length))
+	if w.Err != nil {
+		return "", errors.Wrap(w.Err, "encode "+section.name)
+	}
+	var s = w.String()
+	if p.Gpc {
+		s += "." + EncodeGpcSubsection(p.Gpc)
+	}
+	return s, nil
+}
+
+// EncodeGpcSubsection encodes a GPC subsection, which is the inverse of ParseGppSubSections.
+func EncodeGpcSubsection(gpc bool) string {
+	var w = NewConsentWriter()
+	w.WriteInt(int(SubSectGpc), 2)
+	w.WriteBool(gpc)
+	return encodeBytes(w)
+}
+
+// EncodeGppHeader encodes h, and is the inverse of ParseGppHeader. As with the
+// IAB's reference encoder, the header is padded to a whole number of bytes.
+func EncodeGppHeader(h *GppHeader) (string, error) {
+	if h == nil {
+		return "", errors.New("nil header passed to gpp header encode method")
+	}
+	var w = NewConsentWriter()
+	w.WriteInt(h.Type, 6)
+	w.WriteInt(h.Version, 6)
+	w.WriteFibonacciRange(h.Sections)
+	if w.Err != nil {
+		return "", errors.Wrap(w.Err, "encode gpp header")
+	}
+	return encodeBytes(w), nil
+}
+
+// EncodeGppConsent encodes g as a GPP string, and is the inverse of ParseGppConsent.
+// The header is built from the sections of g in ascending Section ID order, so
+// g.Header is not used.
+func EncodeGppConsent(g *GppConsent) (string, error) {
+	if g == nil {
+		return "", errors.New("nil consent passed to gpp encode method")
+	}
+	var sids = make([]int, 0, len(g.Sections))
+	for sid := range g.Sections {
+		sids = append(sids, sid)
+	}
+	sort.Ints(sids)
+
+	var header, err = EncodeGppHeader(&GppHeader{Type: 3, Version: 1, Sections: sids})
+	if err != nil {
+		return "", err
+	}
+	var segments = []string{header}
+	for _, sid := range sids {
+		var s string
+		switch p := g.Sections[sid].(type) {
+		case *V2ParsedConsent:
+			if sid != TcfEuV2SID {
+				return "", errors.Errorf("tcfeuv2 consent in section %d", sid)
+			}
+			s, err = EncodeV2(p)
+		case *MspaParsedConsent:
+			if sid != p.SID {
+				return "", errors.Errorf("%s consent in section %d", p.SectionName(), sid)
+			}
+			s, err = EncodeMspa(p)
+		default:
+			err = errors.Errorf("unsupported section %d", sid)
+		}
+		if err != nil {
+			return "", err
+		}
+		segments = append(segments, s)
+	}
+	return strings.Join(segments, "~"), nil
+}
@@ -0,0 +1,121 @@
+package iabconsent_test
+
+import (
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type EncodeSuite struct{}
+
+var _ = check.Suite(&EncodeSuite{})
+
+func (s *EncodeSuite) TestEncodeV2RoundTrip(c *check.C) {
+	for str, expected := range v2ConsentFixtures {
+		c.Log(str)
+		var e, err = iabconsent.EncodeV2(expected)
+		c.Assert(err, check.IsNil)
+
+		p, err := iabconsent.ParseV2(e)
+		c.Check(err, check.IsNil)
+		c.Check(p, check.DeepEquals, expected)
+	}
+}
+
+func (s *EncodeSuite) TestEncodeV2(c *check.C) {
+	var str = "COvzTO5OvzTO5BRAAAENAPCoALIAADgAAAAAAewAwABAAlAB6ABBFAAA"
+	var p, err = iabconsent.ParseV2(str)
+	c.Assert(err, check.IsNil)
+	e, err := iabconsent.EncodeV2(p)
+	c.Check(err, check.IsNil)
+	c.Check(e, check.Equals, str)
+
+	_, err = iabconsent.EncodeV2(&iabconsent.V2ParsedConsent{Version: 1})
+	c.Check(err, check.ErrorMatches, "non-v2 consent passed to v2 encode method")
+	_, err = iabconsent.EncodeV2(nil)
+	c.Check(err, check.ErrorMatches, "nil consent passed to v2 encode method")
+	_, err = iabconsent.EncodeV1(nil)
+	c.Check(err, check.ErrorMatches, "nil consent passed to v1 encode method")
+
+	p.ConsentLanguage = "E"
+	_, err = iabconsent.EncodeV2(p)
+	c.Check(err, check.ErrorMatches, `encode v2 core string: write string: "E" is not 2 letters`)
+}
+
+func (s *EncodeSuite) TestEncodeMspa(c *check.C) {
+	for sid, fixtures := range mspaConsentFixtures {
+		for str, expected := range fixtures {
+			c.Log(str)
+			var e, err = iabconsent.EncodeMspa(expected)
+			c.Assert(err, check.IsNil)
+
+			p, err := iabconsent.NewMspa(sid, e).ParseConsent()
+			c.Check(err, check.IsNil)
+			c.Check(p, check.DeepEquals, expected)
+		}
+	}
+
+	var e, err = iabconsent.EncodeMspa(mspaConsentFixtures[iabconsent.UsCaliforniaSID]["BVoYYZoI.YA"])
+	c.Check(err, check.IsNil)
+	c.Check(e, check.Equals, "BVoYYZoI.YA")
+
+	_, err = iabconsent.EncodeMspa(&iabconsent.MspaParsedConsent{SID: iabconsent.UsCaliforniaSID, Version: 2})
+	c.Check(err, check.ErrorMatches, "unsupported usca version: 2")
+	_, err = iabconsent.EncodeMspa(&iabconsent.MspaParsedConsent{SID: 2, Version: 1})
+	c.Check(err, check.ErrorMatches, "unsupported mspa section 2")
+	_, err = iabconsent.EncodeMspa(nil)
+	c.Check(err, check.ErrorMatches, "nil consent passed to mspa encode method")
+	_, err = iabconsent.EncodeMspa(&iabconsent.MspaParsedConsent{SID: iabconsent.UsVirginiaSID, Version: 1,
+		SensitiveDataProcessingConsents: map[int]iabconsent.MspaConsent{8: iabconsent.Consent}})
+	c.Check(err, check.ErrorMatches, "encode usva: write n-bitfield: index 8 not in range 0-7")
+}
+
+func (s *EncodeSuite) TestEncodeGppHeader(c *check.C) {
+	var tcs = []struct {
+		sections []int
+		expected string
+	}{
+		{sections: []int{2}, expected: "DBABMA"},
+		{sections: []int{7}, expected: "DBABLA"},
+		{sections: []int{7, 9}, expected: "DBACLMA"},
+		{sections: []int{7, 8, 9, 10, 11, 12}, expected: "DBABrGA"},
+		{sections: []int{22}, expected: "DBABQYA"},
+	}
+	for _, tc := range tcs {
+		var e, err = iabconsent.EncodeGppHeader(&iabconsent.GppHeader{Type: 3, Version: 1, Sections: tc.sections})
+		c.Check(err, check.IsNil)
+		c.Check(e, check.Equals, tc.expected)
+	}
+
+	var _, err = iabconsent.EncodeGppHeader(nil)
+	c.Check(err, check.ErrorMatches, "nil header passed to gpp header encode method")
+}
+
+func (s *EncodeSuite) TestEncodeGppConsent(c *check.C) {
+	for str := range gppParsedConsentFixtures {
+		c.Log(str)
+		var g, err = iabconsent.ParseGppConsent(str)
+		c.Assert(err, check.IsNil)
+
+		e, err := iabconsent.EncodeGppConsent(g)
+		c.Assert(err, check.IsNil)
+		p, err := iabconsent.ParseGppConsent(e)
+		c.Check(err, check.IsNil)
+		c.Check(p.Sections, check.DeepEquals, g.Sections)
+	}
+
+	var e, err = iabconsent.EncodeGppConsent(&iabconsent.GppConsent{Sections: map[int]iabconsent.GppParsedConsent{
+		iabconsent.UsVirginiaSID: mspaConsentFixtures[iabconsent.UsVirginiaSID]["BVoYYYI"],
+		iabconsent.UsNationalSID: mspaConsentFixtures[iabconsent.UsNationalSID]["BVVqAAEABCA.YA"],
+	}})
+	c.Check(err, check.IsNil)
+	c.Check(e, check.Equals, "DBACLMA~BVVqAAEABCA.YA~BVoYYYI")
+
+	_, err = iabconsent.EncodeGppConsent(&iabconsent.GppConsent{Sections: map[int]iabconsent.GppParsedConsent{
+		iabconsent.UsVirginiaSID: mspaConsentFixtures[iabconsent.UsNationalSID]["BVVqAAEABCA.QA"],
+	}})
+	c.Check(err, check.ErrorMatches, "usnat consent in section 9")
+
+	_, err = iabconsent.EncodeGppConsent(nil)
+	c.Check(err, check.ErrorMatches, "nil consent passed to gpp encode method")
+}
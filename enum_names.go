@@ -0,0 +1,304 @@
+package iabconsent
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// enum holds the names of the values of an enum type, used by String and when marshaling to text
+// and JSON. Names are indexed by value, so must be kept in the same order as the constants. As each
+// type implements encoding.TextMarshaler, it is written to JSON as its name, while UnmarshalJSON
+// also accepts the integer value.
+type enum struct {
+	// kind names the type in errors, e.g. "mspa notice".
+	kind  string
+	names []string
+}
+
+var (
+	tcfVersionEnum      = enum{kind: "tcf version", names: []string{"invalid", "v1", "v2"}}
+	restrictionTypeEnum = enum{kind: "restriction type", names: []string{
+		"purpose_flatly_not_allowed",
+		"require_consent",
+		"require_legitimate_interest",
+		"undefined",
+	}}
+	segmentTypeEnum    = enum{kind: "segment type", names: []string{"core_string", "disclosed_vendors", "allowed_vendors", "publisher_tc"}}
+	specialFeatureEnum = enum{kind: "special feature", names: []string{"invalid", "use_precise_geolocation", "actively_scan_device"}}
+	specialPurposeEnum = enum{kind: "special purpose", names: []string{"invalid", "ensure_security", "technically_deliver_ads"}}
+	mspaNoticeEnum     = enum{kind: "mspa notice", names: []string{"not_applicable", "provided", "not_provided", "invalid"}}
+	mspaOptOutEnum     = enum{kind: "mspa opt out", names: []string{"not_applicable", "opted_out", "not_opted_out", "invalid"}}
+	mspaConsentEnum    = enum{kind: "mspa consent", names: []string{"not_applicable", "no_consent", "consent", "invalid"}}
+	mspaNaYesNoEnum    = enum{kind: "mspa value", names: []string{"not_applicable", "yes", "no", "invalid"}}
+	mspaFieldEnum      = enum{kind: "mspa field", names: []string{
+		"invalid",
+		"sharing_notice",
+		"sale_opt_out_notice",
+		"sharing_opt_out_notice",
+		"targeted_advertising_opt_out_notice",
+		"sensitive_data_processing_opt_out_notice",
+		"sensitive_data_limit_use_notice",
+		"sale_opt_out",
+		"sharing_opt_out",
+		"targeted_advertising_opt_out",
+		"sensitive_data_processing_consents",
+		"sensitive_data_processing_opt_outs",
+		"known_child_sensitive_data_consents",
+		"personal_data_consents",
+		"covered_transaction",
+		"opt_out_option_mode",
+		"service_provider_mode",
+	}}
+)
+
+// String returns the name of the TCF version, e.g. "v2".
+func (v TCFVersion) String() string {
+	return tcfVersionEnum.name(int(v))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (v TCFVersion) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (v *TCFVersion) UnmarshalText(b []byte) error {
+	return tcfVersionEnum.unmarshalText(b, (*int)(v))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting either the name or the integer value.
+func (v *TCFVersion) UnmarshalJSON(b []byte) error {
+	return tcfVersionEnum.unmarshalJSON(b, (*int)(v))
+}
+
+// String returns the name of the restriction type, e.g. "require_consent".
+func (t RestrictionType) String() string {
+	return restrictionTypeEnum.name(int(t))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t RestrictionType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *RestrictionType) UnmarshalText(b []byte) error {
+	return restrictionTypeEnum.unmarshalText(b, (*int)(t))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting either the name or the integer value.
+func (t *RestrictionType) UnmarshalJSON(b []byte) error {
+	return restrictionTypeEnum.unmarshalJSON(b, (*int)(t))
+}
+
+// String returns the name of the segment type, e.g. "disclosed_vendors".
+func (t SegmentType) String() string {
+	return segmentTypeEnum.name(int(t))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t SegmentType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *SegmentType) UnmarshalText(b []byte) error {
+	return segmentTypeEnum.unmarshalText(b, (*int)(t))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting either the name or the integer value.
+func (t *SegmentType) UnmarshalJSON(b []byte) error {
+	return segmentTypeEnum.unmarshalJSON(b, (*int)(t))
+}
+
+// String returns the name of the special feature, e.g. "use_precise_geolocation".
+func (f SpecialFeature) String() string {
+	return specialFeatureEnum.name(int(f))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (f SpecialFeature) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (f *SpecialFeature) UnmarshalText(b []byte) error {
+	return specialFeatureEnum.unmarshalText(b, (*int)(f))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting either the name or the integer value.
+func (f *SpecialFeature) UnmarshalJSON(b []byte) error {
+	return specialFeatureEnum.unmarshalJSON(b, (*int)(f))
+}
+
+// String returns the name of the special purpose, e.g. "ensure_security".
+func (p SpecialPurpose) String() string {
+	return specialPurposeEnum.name(int(p))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (p SpecialPurpose) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (p *SpecialPurpose) UnmarshalText(b []byte) error {
+	return specialPurposeEnum.unmarshalText(b, (*int)(p))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting either the name or the integer value.
+func (p *SpecialPurpose) UnmarshalJSON(b []byte) error {
+	return specialPurposeEnum.unmarshalJSON(b, (*int)(p))
+}
+
+// String returns the name of the notice value, e.g. "provided".
+func (n MspaNotice) String() string {
+	return mspaNoticeEnum.name(int(n))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (n MspaNotice) MarshalText() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (n *MspaNotice) UnmarshalText(b []byte) error {
+	return mspaNoticeEnum.unmarshalText(b, (*int)(n))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting either the name or the integer value.
+func (n *MspaNotice) UnmarshalJSON(b []byte) error {
+	return mspaNoticeEnum.unmarshalJSON(b, (*int)(n))
+}
+
+// String returns the name of the opt out value, e.g. "opted_out".
+func (o MspaOptout) String() string {
+	return mspaOptOutEnum.name(int(o))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (o MspaOptout) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (o *MspaOptout) UnmarshalText(b []byte) error {
+	return mspaOptOutEnum.unmarshalText(b, (*int)(o))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting either the name or the integer value.
+func (o *MspaOptout) UnmarshalJSON(b []byte) error {
+	return mspaOptOutEnum.unmarshalJSON(b, (*int)(o))
+}
+
+// String returns the name of the consent value, e.g. "no_consent".
+func (c MspaConsent) String() string {
+	return mspaConsentEnum.name(int(c))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c MspaConsent) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *MspaConsent) UnmarshalText(b []byte) error {
+	return mspaConsentEnum.unmarshalText(b, (*int)(c))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting either the name or the integer value.
+func (c *MspaConsent) UnmarshalJSON(b []byte) error {
+	return mspaConsentEnum.unmarshalJSON(b, (*int)(c))
+}
+
+// String returns the name of the value, e.g. "yes".
+func (v MspaNaYesNo) String() string {
+	return mspaNaYesNoEnum.name(int(v))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (v MspaNaYesNo) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (v *MspaNaYesNo) UnmarshalText(b []byte) error {
+	return mspaNaYesNoEnum.unmarshalText(b, (*int)(v))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting either the name or the integer value.
+func (v *MspaNaYesNo) UnmarshalJSON(b []byte) error {
+	return mspaNaYesNoEnum.unmarshalJSON(b, (*int)(v))
+}
+
+// String returns the name of the field, e.g. "sale_opt_out".
+func (f MspaField) String() string {
+	return mspaFieldEnum.name(int(f))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (f MspaField) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (f *MspaField) UnmarshalText(b []byte) error {
+	return mspaFieldEnum.unmarshalText(b, (*int)(f))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting either the name or the integer value.
+func (f *MspaField) UnmarshalJSON(b []byte) error {
+	return mspaFieldEnum.unmarshalJSON(b, (*int)(f))
+}
+
+// name returns the name of value v, or v formatted as an integer if it has no name.
+func (e enum) name(v int) string {
+	if v >= 0 && v < len(e.names) {
+		return e.names[v]
+	}
+	return strconv.Itoa(v)
+}
+
+// value is the inverse of name.
+func (e enum) value(s string) (int, error) {
+	for i, n := range e.names {
+		if n == s {
+			return i, nil
+		}
+	}
+	if i, err := strconv.Atoi(s); err == nil {
+		return i, nil
+	}
+	return 0, errors.Errorf("unknown %s %q", e.kind, s)
+}
+
+// unmarshalText sets *v to the value named by b.
+func (e enum) unmarshalText(b []byte, v *int) error {
+	var i, err = e.value(string(b))
+	if err != nil {
+		return err
+	}
+	*v = i
+	return nil
+}
+
+// unmarshalJSON sets *v to the value read from either a JSON string holding its name, or a JSON
+// number. *v is left unchanged if b is null.
+func (e enum) unmarshalJSON(b []byte, v *int) error {
+	if string(b) == "null" {
+		return nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		return e.unmarshalText([]byte(s), v)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return errors.Wrap(err, "unmarshal "+e.kind)
+	}
+	return nil
+}
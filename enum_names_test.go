@@ -0,0 +1,111 @@
+package iabconsent_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type EnumNamesSuite struct{}
+
+var _ = check.Suite(&EnumNamesSuite{})
+
+func (s *EnumNamesSuite) TestString(c *check.C) {
+	var tcs = []struct {
+		v        fmt.Stringer
+		expected string
+	}{
+		{v: iabconsent.V2, expected: "v2"},
+		{v: iabconsent.InvalidTCFVersion, expected: "invalid"},
+		{v: iabconsent.PurposeFlatlyNotAllowed, expected: "purpose_flatly_not_allowed"},
+		{v: iabconsent.RequireLegitimateInterest, expected: "require_legitimate_interest"},
+		{v: iabconsent.DisclosedVendors, expected: "disclosed_vendors"},
+		{v: iabconsent.PublisherTC, expected: "publisher_tc"},
+		{v: iabconsent.UsePreciseGeolocation, expected: "use_precise_geolocation"},
+		{v: iabconsent.TechnicallyDeliverAds, expected: "technically_deliver_ads"},
+		{v: iabconsent.NoticeNotProvided, expected: "not_provided"},
+		{v: iabconsent.OptedOut, expected: "opted_out"},
+		{v: iabconsent.NotOptedOut, expected: "not_opted_out"},
+		{v: iabconsent.NoConsent, expected: "no_consent"},
+		{v: iabconsent.MspaYes, expected: "yes"},
+		{v: iabconsent.InvalidMspaValue, expected: "invalid"},
+		{v: iabconsent.MspaFieldSensitiveDataProcessingOptOuts, expected: "sensitive_data_processing_opt_outs"},
+		// Values without a name are formatted as integers.
+		{v: iabconsent.MspaOptout(7), expected: "7"},
+		{v: iabconsent.RestrictionType(-1), expected: "-1"},
+	}
+	for _, tc := range tcs {
+		c.Check(tc.v.String(), check.Equals, tc.expected)
+	}
+}
+
+func (s *EnumNamesSuite) TestJSON(c *check.C) {
+	var v = struct {
+		Version     iabconsent.TCFVersion      `json:"version"`
+		Restriction iabconsent.RestrictionType `json:"restriction"`
+		Segment     iabconsent.SegmentType     `json:"segment"`
+		Feature     iabconsent.SpecialFeature  `json:"feature"`
+		Purpose     iabconsent.SpecialPurpose  `json:"purpose"`
+		Notice      iabconsent.MspaNotice      `json:"notice"`
+		OptOut      iabconsent.MspaOptout      `json:"opt_out"`
+		Consent     iabconsent.MspaConsent     `json:"consent"`
+		NaYesNo     iabconsent.MspaNaYesNo     `json:"na_yes_no"`
+		Unknown     iabconsent.MspaOptout      `json:"unknown"`
+	}{
+		Version:     iabconsent.V1,
+		Restriction: iabconsent.RequireConsent,
+		Segment:     iabconsent.AllowedVendors,
+		Feature:     iabconsent.ActivelyScanDevice,
+		Purpose:     iabconsent.EnsureSecurity,
+		Notice:      iabconsent.NoticeProvided,
+		OptOut:      iabconsent.OptedOut,
+		Consent:     iabconsent.Consent,
+		NaYesNo:     iabconsent.MspaNo,
+		Unknown:     iabconsent.MspaOptout(9),
+	}
+	var b, err = json.Marshal(v)
+	c.Assert(err, check.IsNil)
+	c.Check(string(b), check.Equals, `{"version":"v1","restriction":"require_consent","segment":"allowed_vendors",`+
+		`"feature":"actively_scan_device","purpose":"ensure_security","notice":"provided","opt_out":"opted_out",`+
+		`"consent":"consent","na_yes_no":"no","unknown":"9"}`)
+
+	var out = v
+	out.Version, out.OptOut, out.Unknown = 0, 0, 0
+	c.Assert(json.Unmarshal(b, &out), check.IsNil)
+	c.Check(out, check.DeepEquals, v)
+}
+
+func (s *EnumNamesSuite) TestUnmarshalJSON(c *check.C) {
+	var o iabconsent.MspaOptout
+	// Integer values are accepted, for data encoded before enums were named.
+	c.Check(json.Unmarshal([]byte(`2`), &o), check.IsNil)
+	c.Check(o, check.Equals, iabconsent.NotOptedOut)
+	c.Check(json.Unmarshal([]byte(`"opted_out"`), &o), check.IsNil)
+	c.Check(o, check.Equals, iabconsent.OptedOut)
+	// null leaves the value unchanged.
+	c.Check(json.Unmarshal([]byte(`null`), &o), check.IsNil)
+	c.Check(o, check.Equals, iabconsent.OptedOut)
+
+	c.Check(json.Unmarshal([]byte(`"opted_in"`), &o), check.ErrorMatches, `unknown mspa opt out "opted_in"`)
+	c.Check(json.Unmarshal([]byte(`true`), &o), check.ErrorMatches, "unmarshal mspa opt out: .*")
+	c.Check(o, check.Equals, iabconsent.OptedOut)
+}
+
+func (s *EnumNamesSuite) TestText(c *check.C) {
+	var b, err = iabconsent.NoticeNotApplicable.MarshalText()
+	c.Check(err, check.IsNil)
+	c.Check(string(b), check.Equals, "not_applicable")
+
+	var n iabconsent.MspaNotice
+	c.Check(n.UnmarshalText([]byte("not_provided")), check.IsNil)
+	c.Check(n, check.Equals, iabconsent.NoticeNotProvided)
+	c.Check(n.UnmarshalText([]byte("maybe")), check.ErrorMatches, `unknown mspa notice "maybe"`)
+
+	// Text marshaling makes enums usable as JSON map keys.
+	b, err = json.Marshal(map[iabconsent.MspaField]bool{iabconsent.MspaFieldSharingNotice: true})
+	c.Check(err, check.IsNil)
+	c.Check(string(b), check.Equals, `{"sharing_notice":true}`)
+}
@@ -0,0 +1,197 @@
+package iabconsent
+
+// Equal reports whether p and q hold the same consent, regardless of how it is encoded: the vendors
+// consented are compared by ID, so a bit field and a range section consenting the same vendors, or
+// range entries in a different order, are equal. Created and LastUpdated are compared with
+// time.Time.Equal. NumEntries is not compared, as it is implied by RangeEntries.
+func (p *ParsedConsent) Equal(q *ParsedConsent) bool {
+	if p == nil || q == nil {
+		return p == q
+	}
+	return p.Version == q.Version &&
+		p.Created.Equal(q.Created) &&
+		p.LastUpdated.Equal(q.LastUpdated) &&
+		p.CMPID == q.CMPID &&
+		p.CMPVersion == q.CMPVersion &&
+		p.ConsentScreen == q.ConsentScreen &&
+		p.ConsentLanguage == q.ConsentLanguage &&
+		p.VendorListVersion == q.VendorListVersion &&
+		p.MaxVendorID == q.MaxVendorID &&
+		equalSets(p.PurposesAllowed, q.PurposesAllowed) &&
+		equalRanges(v1VendorRanges(p), v1VendorRanges(q))
+}
+
+// v1VendorRanges returns the merged vendors from 1 to MaxVendorID consented by p.
+func v1VendorRanges(p *ParsedConsent) []*RangeEntry {
+	var all = v1AllVendors(p)
+	var ranged = vendorRanges(p.IsRangeEncoding, p.ConsentedVendors, p.RangeEntries)
+	if p.IsRangeEncoding && p.DefaultConsent {
+		// With DefaultConsent set, the ranges list the vendors which are not consented.
+		return subtractRanges(all, ranged)
+	}
+	return intersectRanges(ranged, all)
+}
+
+// v1AllVendors returns the vendors from 1 to the MaxVendorID of p.
+func v1AllVendors(p *ParsedConsent) []*RangeEntry {
+	if p.MaxVendorID < 1 {
+		return nil
+	}
+	return []*RangeEntry{{StartVendorID: 1, EndVendorID: p.MaxVendorID}}
+}
+
+// Equal reports whether p and q hold the same consent, regardless of how it is encoded: vendors are
+// compared by ID, so bit field and range sections holding the same vendors are equal, and publisher
+// restrictions are compared per purpose, restriction type and vendor, regardless of how they are grouped into entries.
+// MaxConsentVendorID, MaxInterestsVendorID and the Num fields are not compared, as they are implied
+// by the vendors. Created and LastUpdated are compared with time.Time.Equal. The presence of the
+// DisclosedVendors, AllowedVendors and Publisher TC segments is compared, as well as their values.
+func (p *V2ParsedConsent) Equal(q *V2ParsedConsent) bool {
+	if p == nil || q == nil {
+		return p == q
+	}
+	if p.Version != q.Version ||
+		!p.Created.Equal(q.Created) ||
+		!p.LastUpdated.Equal(q.LastUpdated) ||
+		p.CMPID != q.CMPID ||
+		p.CMPVersion != q.CMPVersion ||
+		p.ConsentScreen != q.ConsentScreen ||
+		p.ConsentLanguage != q.ConsentLanguage ||
+		p.VendorListVersion != q.VendorListVersion ||
+		p.TCFPolicyVersion != q.TCFPolicyVersion ||
+		p.IsServiceSpecific != q.IsServiceSpecific ||
+		p.UseNonStandardStacks != q.UseNonStandardStacks ||
+		p.PurposeOneTreatment != q.PurposeOneTreatment ||
+		p.PublisherCC != q.PublisherCC {
+		return false
+	}
+	if !equalSets(p.SpecialFeaturesOptIn, q.SpecialFeaturesOptIn) ||
+		!equalSets(p.PurposesConsent, q.PurposesConsent) ||
+		!equalSets(p.PurposesLITransparency, q.PurposesLITransparency) ||
+		!equalRanges(vendorRanges(p.IsConsentRangeEncoding, p.ConsentedVendors, p.ConsentedVendorsRange),
+			vendorRanges(q.IsConsentRangeEncoding, q.ConsentedVendors, q.ConsentedVendorsRange)) ||
+		!equalRanges(vendorRanges(p.IsInterestsRangeEncoding, p.InterestsVendors, p.InterestsVendorsRange),
+			vendorRanges(q.IsInterestsRangeEncoding, q.InterestsVendors, q.InterestsVendorsRange)) {
+		return false
+	}
+	if !equalOOBVendorLists(p.OOBDisclosedVendors, q.OOBDisclosedVendors) ||
+		!equalOOBVendorLists(p.OOBAllowedVendors, q.OOBAllowedVendors) {
+		return false
+	}
+
+	var rp, rq = restrictionRanges(p.PubRestrictionEntries), restrictionRanges(q.PubRestrictionEntries)
+	if len(rp) != len(rq) {
+		return false
+	}
+	for g, ranges := range rp {
+		if !equalRanges(ranges, rq[g]) {
+			return false
+		}
+	}
+	return equalPublisherTCEntries(p.PublisherTCEntry, q.PublisherTCEntry)
+}
+
+func equalOOBVendorLists(a, b *OOBVendorList) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return equalRanges(oobVendorRanges(a), oobVendorRanges(b))
+}
+
+func equalPublisherTCEntries(a, b *PublisherTCEntry) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.NumCustomPurposes == b.NumCustomPurposes &&
+		equalSets(a.PubPurposesConsent, b.PubPurposesConsent) &&
+		equalSets(a.PubPurposesLITransparency, b.PubPurposesLITransparency) &&
+		equalSets(a.CustomPurposesConsent, b.CustomPurposesConsent) &&
+		equalSets(a.CustomPurposesLITransparency, b.CustomPurposesLITransparency)
+}
+
+// equalSets reports whether a and b set the same IDs. IDs mapped to false are not set.
+func equalSets(a, b map[int]bool) bool {
+	for id, ok := range a {
+		if ok && !b[id] {
+			return false
+		}
+	}
+	for id, ok := range b {
+		if ok && !a[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal reports whether p and q hold the same consent: the same section, version, field values and
+// GPC signal. Bit field elements missing from one of the consents are compared as 0 (not applicable),
+// which is how they are encoded.
+func (p *MspaParsedConsent) Equal(q *MspaParsedConsent) bool {
+	if p == nil || q == nil {
+		return p == q
+	}
+	if p.SID != q.SID || p.Version != q.Version || p.Gpc != q.Gpc {
+		return false
+	}
+	for f := MspaFieldSharingNotice; f <= MspaFieldServiceProviderMode; f++ {
+		switch vp := p.fieldPtr(f).(type) {
+		case *MspaNotice:
+			if *vp != *q.fieldPtr(f).(*MspaNotice) {
+				return false
+			}
+		case *MspaOptout:
+			if *vp != *q.fieldPtr(f).(*MspaOptout) {
+				return false
+			}
+		case *MspaConsent:
+			if *vp != *q.fieldPtr(f).(*MspaConsent) {
+				return false
+			}
+		case *MspaNaYesNo:
+			if *vp != *q.fieldPtr(f).(*MspaNaYesNo) {
+				return false
+			}
+		case *map[int]MspaConsent:
+			if !equalBitFields(consentValues(*vp), consentValues(*q.fieldPtr(f).(*map[int]MspaConsent))) {
+				return false
+			}
+		case *map[int]MspaOptout:
+			if !equalBitFields(optOutValues(*vp), optOutValues(*q.fieldPtr(f).(*map[int]MspaOptout))) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// equalBitFields reports whether the elements of a and b are equal, treating missing elements as 0.
+func equalBitFields(a, b map[int]int) bool {
+	for i, v := range a {
+		if b[i] != v {
+			return false
+		}
+	}
+	for i, v := range b {
+		if a[i] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func consentValues(m map[int]MspaConsent) map[int]int {
+	var values = make(map[int]int, len(m))
+	for i, v := range m {
+		values[i] = int(v)
+	}
+	return values
+}
+
+func optOutValues(m map[int]MspaOptout) map[int]int {
+	var values = make(map[int]int, len(m))
+	for i, v := range m {
+		values[i] = int(v)
+	}
+	return values
+}
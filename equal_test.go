@@ -0,0 +1,223 @@
+package iabconsent_test
+
+import (
+	"time"
+
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type EqualSuite struct{}
+
+var _ = check.Suite(&EqualSuite{})
+
+func (s *EqualSuite) TestV1Equal(c *check.C) {
+	var bitField = &iabconsent.ParsedConsent{
+		Version:          1,
+		MaxVendorID:      5,
+		PurposesAllowed:  map[int]bool{1: true, 2: false},
+		ConsentedVendors: map[int]bool{1: true, 2: true, 4: true, 5: true},
+	}
+	var tcs = []struct {
+		desc     string
+		other    *iabconsent.ParsedConsent
+		expected bool
+	}{
+		{
+			desc: "Range encoding.",
+			other: &iabconsent.ParsedConsent{
+				Version:         1,
+				MaxVendorID:     5,
+				PurposesAllowed: map[int]bool{1: true},
+				IsRangeEncoding: true,
+				NumEntries:      2,
+				RangeEntries: []*iabconsent.RangeEntry{
+					{StartVendorID: 4, EndVendorID: 5},
+					{StartVendorID: 1, EndVendorID: 2},
+				},
+			},
+			expected: true,
+		},
+		{
+			desc: "Default consent range encoding.",
+			other: &iabconsent.ParsedConsent{
+				Version:         1,
+				MaxVendorID:     5,
+				PurposesAllowed: map[int]bool{1: true},
+				IsRangeEncoding: true,
+				DefaultConsent:  true,
+				NumEntries:      1,
+				RangeEntries:    []*iabconsent.RangeEntry{{StartVendorID: 3, EndVendorID: 3}},
+			},
+			expected: true,
+		},
+		{
+			desc: "Different vendors.",
+			other: &iabconsent.ParsedConsent{
+				Version:          1,
+				MaxVendorID:      5,
+				PurposesAllowed:  map[int]bool{1: true},
+				ConsentedVendors: map[int]bool{1: true, 2: true, 5: true},
+			},
+			expected: false,
+		},
+		{
+			desc: "Different purposes.",
+			other: &iabconsent.ParsedConsent{
+				Version:          1,
+				MaxVendorID:      5,
+				PurposesAllowed:  map[int]bool{1: true, 2: true},
+				ConsentedVendors: map[int]bool{1: true, 2: true, 4: true, 5: true},
+			},
+			expected: false,
+		},
+		{
+			desc:     "Nil.",
+			expected: false,
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		c.Check(bitField.Equal(tc.other), check.Equals, tc.expected)
+		c.Check(tc.other.Equal(bitField), check.Equals, tc.expected)
+	}
+}
+
+func (s *EqualSuite) TestV2Equal(c *check.C) {
+	var t1 = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var newConsent = func() *iabconsent.V2ParsedConsent {
+		var p = mergeConsent(t1, map[int]bool{1: true, 2: true, 3: true}, &iabconsent.OOBVendorList{
+			SegmentType: iabconsent.DisclosedVendors,
+			MaxVendorID: 3,
+			Vendors:     map[int]bool{1: true, 2: true, 3: true},
+		})
+		p.PubRestrictionEntries = []*iabconsent.PubRestrictionEntry{
+			{
+				PurposeID:         2,
+				RestrictionType:   iabconsent.RequireConsent,
+				NumEntries:        1,
+				RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 1, EndVendorID: 2}},
+			},
+		}
+		return p
+	}
+	var a = newConsent()
+
+	var tcs = []struct {
+		desc     string
+		modify   func(p *iabconsent.V2ParsedConsent)
+		expected bool
+	}{
+		{
+			desc:     "Identical.",
+			modify:   func(p *iabconsent.V2ParsedConsent) {},
+			expected: true,
+		},
+		{
+			desc: "Range encoded vendors, with a higher max vendor id.",
+			modify: func(p *iabconsent.V2ParsedConsent) {
+				p.MaxConsentVendorID = 10
+				p.IsConsentRangeEncoding = true
+				p.ConsentedVendors = nil
+				p.NumConsentEntries = 2
+				p.ConsentedVendorsRange = []*iabconsent.RangeEntry{
+					{StartVendorID: 3, EndVendorID: 3},
+					{StartVendorID: 1, EndVendorID: 2},
+				}
+			},
+			expected: true,
+		},
+		{
+			desc: "Restrictions split across entries.",
+			modify: func(p *iabconsent.V2ParsedConsent) {
+				p.NumPubRestrictions = 2
+				p.PubRestrictionEntries = []*iabconsent.PubRestrictionEntry{
+					{
+						PurposeID:         2,
+						RestrictionType:   iabconsent.RequireConsent,
+						RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 2, EndVendorID: 2}},
+					},
+					{
+						PurposeID:         2,
+						RestrictionType:   iabconsent.RequireConsent,
+						RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 1, EndVendorID: 1}},
+					},
+				}
+			},
+			expected: true,
+		},
+		{
+			desc: "Overlapping restrictions up to the highest vendor id.",
+			modify: func(p *iabconsent.V2ParsedConsent) {
+				p.PubRestrictionEntries[0].RestrictionsRange = []*iabconsent.RangeEntry{
+					{StartVendorID: 1, EndVendorID: 65535},
+					{StartVendorID: 2, EndVendorID: 65535},
+				}
+			},
+			expected: false,
+		},
+		{
+			desc: "Purpose false entries.",
+			modify: func(p *iabconsent.V2ParsedConsent) {
+				p.PurposesConsent[5] = false
+			},
+			expected: true,
+		},
+		{
+			desc: "Different restriction type.",
+			modify: func(p *iabconsent.V2ParsedConsent) {
+				p.PubRestrictionEntries[0].RestrictionType = iabconsent.PurposeFlatlyNotAllowed
+			},
+			expected: false,
+		},
+		{
+			desc: "Different disclosed vendors.",
+			modify: func(p *iabconsent.V2ParsedConsent) {
+				p.OOBDisclosedVendors.Vendors[4] = true
+			},
+			expected: false,
+		},
+		{
+			desc: "Missing disclosed vendors segment.",
+			modify: func(p *iabconsent.V2ParsedConsent) {
+				p.OOBDisclosedVendors = nil
+			},
+			expected: false,
+		},
+		{
+			desc: "Different last updated.",
+			modify: func(p *iabconsent.V2ParsedConsent) {
+				p.LastUpdated = p.LastUpdated.Add(time.Second)
+			},
+			expected: false,
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		var b = newConsent()
+		tc.modify(b)
+		c.Check(a.Equal(b), check.Equals, tc.expected)
+		c.Check(b.Equal(a), check.Equals, tc.expected)
+	}
+	c.Check(a.Equal(nil), check.Equals, false)
+	c.Check((*iabconsent.V2ParsedConsent)(nil).Equal(nil), check.Equals, true)
+}
+
+func (s *EqualSuite) TestMspaEqual(c *check.C) {
+	var ga, err = iabconsent.ParseGppConsent("DBABLA~BVVqAAEABCA.YA")
+	c.Assert(err, check.IsNil)
+	var gb *iabconsent.GppConsent
+	gb, err = iabconsent.ParseGppConsent("DBABLA~BVVqAAEABCA.YA")
+	c.Assert(err, check.IsNil)
+	var a, b = ga.UsNational(), gb.UsNational()
+	c.Check(a.Equal(b), check.Equals, true)
+
+	// Missing bit field elements are encoded as not applicable.
+	delete(b.SensitiveDataProcessingConsents, 11)
+	c.Check(a.Equal(b), check.Equals, true)
+
+	b.SaleOptOut = iabconsent.OptedOut
+	c.Check(a.Equal(b), check.Equals, false)
+	c.Check(a.Equal(nil), check.Equals, false)
+}
@@ -0,0 +1,115 @@
+package iabconsent
+
+import (
+	"fmt"
+)
+
+// ReasonCode is an enum type of the reasons a consent does not permit a vendor to process.
+type ReasonCode int
+
+const (
+	UnknownReason ReasonCode = iota
+	// The vendor does not have consent.
+	ReasonVendorNotConsented
+	// A required purpose does not have consent.
+	ReasonPurposeNotConsented
+	// The publisher has restricted the vendor under a required purpose.
+	ReasonPublisherRestricted
+	// The vendor was not disclosed to the user, which TCF v2.3 requires for consent.
+	ReasonVendorNotDisclosed
+)
+
+// Reason is a single cause of a consent not permitting a vendor to process.
+type Reason struct {
+	Code ReasonCode
+	// The purpose the reason applies to, if any.
+	PurposeID int
+	// The vendor the reason applies to, if any.
+	VendorID int
+	// The type of the publisher restriction, set for ReasonPublisherRestricted.
+	RestrictionType RestrictionType
+}
+
+// String returns a human readable description of the reason, e.g. "purpose 4 not consented".
+func (r Reason) String() string {
+	switch r.Code {
+	case ReasonVendorNotConsented:
+		return fmt.Sprintf("vendor %d not consented", r.VendorID)
+	case ReasonPurposeNotConsented:
+		return fmt.Sprintf("purpose %d not consented", r.PurposeID)
+	case ReasonVendorNotDisclosed:
+		return fmt.Sprintf("vendor %d not disclosed", r.VendorID)
+	case ReasonPublisherRestricted:
+		return fmt.Sprintf("vendor %d restricted under purpose %d by publisher (%s)",
+			r.VendorID, r.PurposeID, restrictionDescription(r.RestrictionType))
+	default:
+		return fmt.Sprintf("unknown reason %d", r.Code)
+	}
+}
+
+// restrictionDescription describes a RestrictionType for use in a Reason.
+func restrictionDescription(t RestrictionType) string {
+	switch t {
+	case PurposeFlatlyNotAllowed:
+		return "flatly not allowed"
+	case RequireConsent:
+		return "require consent"
+	case RequireLegitimateInterest:
+		return "require legitimate interest"
+	default:
+		return "undefined"
+	}
+}
+
+// Explain returns every reason SuitableToProcess(ps, v) is false: the vendor is
+// not consented, then each purpose in ps that is not consented. The result is
+// empty iff SuitableToProcess(ps, v) is true.
+func (p *ParsedConsent) Explain(ps []int, v int) []Reason {
+	var reasons []Reason
+	if !p.VendorAllowed(v) {
+		reasons = append(reasons, Reason{Code: ReasonVendorNotConsented, VendorID: v})
+	}
+	for _, rp := range ps {
+		if !p.PurposeAllowed(rp) {
+			reasons = append(reasons, Reason{Code: ReasonPurposeNotConsented, PurposeID: rp})
+		}
+	}
+	return reasons
+}
+
+// Explain returns every reason SuitableToProcess(ps, v) is false: the vendor is
+// not disclosed (from TCF v2.3) or not consented, then each purpose in ps that
+// is not consented, then each Flatly Not Allowed publisher restriction covering
+// v under a purpose in ps. The result is empty iff SuitableToProcess(ps, v) is true.
+func (p *V2ParsedConsent) Explain(ps []int, v int) []Reason {
+	var reasons []Reason
+	if p.requiresDisclosure() && !p.VendorDisclosed(v) {
+		reasons = append(reasons, Reason{Code: ReasonVendorNotDisclosed, VendorID: v})
+	} else if !p.VendorAllowed(v) {
+		reasons = append(reasons, Reason{Code: ReasonVendorNotConsented, VendorID: v})
+	}
+	// Map-ify ps for use in checking pub restrictions.
+	var pm = make(map[int]bool)
+	for _, rp := range ps {
+		if !p.PurposeAllowed(rp) {
+			reasons = append(reasons, Reason{Code: ReasonPurposeNotConsented, PurposeID: rp})
+		}
+		pm[rp] = true
+	}
+	if p != nil && p.NumPubRestrictions > 0 {
+		for _, re := range p.PubRestrictionEntries {
+			if re != nil && pm[re.PurposeID] &&
+				re.RestrictionType == PurposeFlatlyNotAllowed &&
+				inRangeEntries(v, re.RestrictionsRange) {
+
+				reasons = append(reasons, Reason{
+					Code:            ReasonPublisherRestricted,
+					PurposeID:       re.PurposeID,
+					VendorID:        v,
+					RestrictionType: re.RestrictionType,
+				})
+			}
+		}
+	}
+	return reasons
+}
@@ -0,0 +1,151 @@
+package iabconsent_test
+
+import (
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type ExplainSuite struct{}
+
+var _ = check.Suite(&ExplainSuite{})
+
+func (s *ExplainSuite) TestV2Explain(c *check.C) {
+	var restrictions = []*iabconsent.PubRestrictionEntry{
+		{
+			PurposeID:       2,
+			RestrictionType: iabconsent.PurposeFlatlyNotAllowed,
+			NumEntries:      1,
+			RestrictionsRange: []*iabconsent.RangeEntry{
+				{StartVendorID: 750, EndVendorID: 760},
+			},
+		},
+		{
+			PurposeID:       3,
+			RestrictionType: iabconsent.RequireConsent,
+			NumEntries:      1,
+			RestrictionsRange: []*iabconsent.RangeEntry{
+				{StartVendorID: 755, EndVendorID: 755},
+			},
+		},
+	}
+	var tcs = []struct {
+		desc     string
+		vendor   int
+		purposes []int
+		expected []string
+	}{
+		{
+			desc:     "Suitable to process.",
+			vendor:   123,
+			purposes: []int{1, 2, 3},
+		},
+		{
+			desc:     "Vendor not consented.",
+			vendor:   124,
+			purposes: []int{1},
+			expected: []string{"vendor 124 not consented"},
+		},
+		{
+			desc:     "Purposes not consented.",
+			vendor:   123,
+			purposes: []int{1, 4, 5},
+			expected: []string{"purpose 4 not consented", "purpose 5 not consented"},
+		},
+		{
+			desc:     "Restricted by publisher, Require Consent restrictions are ignored.",
+			vendor:   755,
+			purposes: []int{2, 3},
+			expected: []string{"vendor 755 restricted under purpose 2 by publisher (flatly not allowed)"},
+		},
+		{
+			desc:     "Every reason.",
+			vendor:   756,
+			purposes: []int{2, 4},
+			expected: []string{
+				"vendor 756 not consented",
+				"purpose 4 not consented",
+				"vendor 756 restricted under purpose 2 by publisher (flatly not allowed)",
+			},
+		},
+	}
+
+	var pc = &iabconsent.V2ParsedConsent{
+		PurposesConsent:       map[int]bool{1: true, 2: true, 3: true},
+		ConsentedVendors:      map[int]bool{123: true, 755: true},
+		NumPubRestrictions:    len(restrictions),
+		PubRestrictionEntries: restrictions,
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		var reasons = pc.Explain(tc.purposes, tc.vendor)
+
+		var descriptions []string
+		for _, r := range reasons {
+			descriptions = append(descriptions, r.String())
+		}
+		c.Check(descriptions, check.DeepEquals, tc.expected)
+		c.Check(len(reasons) == 0, check.Equals, pc.SuitableToProcess(tc.purposes, tc.vendor))
+	}
+}
+
+func (s *ExplainSuite) TestV2ExplainReasonFields(c *check.C) {
+	var pc = &iabconsent.V2ParsedConsent{
+		PurposesConsent:    map[int]bool{2: true},
+		ConsentedVendors:   map[int]bool{755: true},
+		NumPubRestrictions: 1,
+		PubRestrictionEntries: []*iabconsent.PubRestrictionEntry{
+			{
+				PurposeID:         2,
+				RestrictionType:   iabconsent.PurposeFlatlyNotAllowed,
+				NumEntries:        1,
+				RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 755, EndVendorID: 755}},
+			},
+		},
+	}
+	c.Check(pc.Explain([]int{2}, 755), check.DeepEquals, []iabconsent.Reason{
+		{
+			Code:            iabconsent.ReasonPublisherRestricted,
+			PurposeID:       2,
+			VendorID:        755,
+			RestrictionType: iabconsent.PurposeFlatlyNotAllowed,
+		},
+	})
+}
+
+func (s *ExplainSuite) TestV2ExplainNotDisclosed(c *check.C) {
+	var pc = &iabconsent.V2ParsedConsent{
+		TCFPolicyVersion: 5,
+		PurposesConsent:  map[int]bool{1: true},
+		ConsentedVendors: map[int]bool{1: true, 2: true},
+		OOBDisclosedVendors: &iabconsent.OOBVendorList{
+			SegmentType: iabconsent.DisclosedVendors,
+			MaxVendorID: 1,
+			Vendors:     map[int]bool{1: true},
+		},
+	}
+	c.Check(pc.Explain([]int{1}, 1), check.HasLen, 0)
+	c.Check(pc.Explain([]int{1}, 2), check.DeepEquals, []iabconsent.Reason{
+		{Code: iabconsent.ReasonVendorNotDisclosed, VendorID: 2},
+	})
+	c.Check(pc.Explain([]int{1}, 2)[0].String(), check.Equals, "vendor 2 not disclosed")
+}
+
+func (s *ExplainSuite) TestV1Explain(c *check.C) {
+	var pc = &iabconsent.ParsedConsent{
+		PurposesAllowed:  map[int]bool{1: true, 2: true},
+		ConsentedVendors: map[int]bool{10: true},
+	}
+	c.Check(pc.Explain([]int{1, 2}, 10), check.HasLen, 0)
+	c.Check(pc.Explain([]int{1, 3}, 11), check.DeepEquals, []iabconsent.Reason{
+		{Code: iabconsent.ReasonVendorNotConsented, VendorID: 11},
+		{Code: iabconsent.ReasonPurposeNotConsented, PurposeID: 3},
+	})
+}
+
+func (s *ExplainSuite) TestReasonString(c *check.C) {
+	c.Check(iabconsent.Reason{Code: iabconsent.ReasonPublisherRestricted, PurposeID: 7, VendorID: 1,
+		RestrictionType: iabconsent.RequireLegitimateInterest}.String(),
+		check.Equals, "vendor 1 restricted under purpose 7 by publisher (require legitimate interest)")
+	c.Check(iabconsent.Reason{}.String(), check.Equals, "unknown reason 0")
+}
@@ -0,0 +1,115 @@
+//go:build go1.18
+// +build go1.18
+
+package iabconsent_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+// checkParsed fails t if parsing s panicked, even if the panic was recovered, or returned neither
+// a result nor an error. Each fuzz target checks its parser with it, starting from a seed corpus
+// drawn from the fixture files. Run a target with e.g.
+//
+//	go test -run=^$ -fuzz=^FuzzParseV2$
+func checkParsed(t *testing.T, s string, parsed bool, err error) {
+	if pe, ok := errors.Cause(err).(*iabconsent.PanicError); ok {
+		t.Fatalf("%q panicked: %v\n%s", s, pe.Value, pe.Stack)
+	}
+	if err == nil && !parsed {
+		t.Errorf("%q parsed to nil", s)
+	}
+}
+
+func FuzzParseV1(f *testing.F) {
+	for _, p := range v1ConsentFixtures {
+		var s, err = iabconsent.EncodeV1(p)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		var p, err = iabconsent.ParseV1(s)
+		checkParsed(t, s, p != nil, err)
+	})
+}
+
+func FuzzParseV2(f *testing.F) {
+	for s := range v2ConsentFixtures {
+		f.Add(s)
+	}
+	for s := range v2InvalidConsentFixtures {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		var p, err = iabconsent.ParseV2(s)
+		checkParsed(t, s, p != nil, err)
+	})
+}
+
+func FuzzParseGppHeader(f *testing.F) {
+	for s := range gppParsedConsentFixtures {
+		f.Add(strings.Split(s, "~")[0])
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		var h, err = iabconsent.ParseGppHeader(s)
+		checkParsed(t, s, h != nil, err)
+	})
+}
+
+func FuzzParseGppConsent(f *testing.F) {
+	for s := range gppParsedConsentFixtures {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		var g, err = iabconsent.ParseGppConsent(s)
+		checkParsed(t, s, g != nil, err)
+	})
+}
+
+func FuzzDump(f *testing.F) {
+	for s := range v2ConsentFixtures {
+		f.Add(s)
+	}
+	for s := range gppParsedConsentFixtures {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		var d, err = iabconsent.Dump(s)
+		checkParsed(t, s, d != nil, err)
+	})
+}
+
+// fuzzMspa fuzzes the parser of the MSPA section with Section ID sid.
+func fuzzMspa(f *testing.F, sid int) {
+	for s := range mspaConsentFixtures[sid] {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		var p, err = iabconsent.NewMspa(sid, s).ParseConsent()
+		checkParsed(t, s, p != nil, err)
+	})
+}
+
+func FuzzMspaUsNational(f *testing.F) { fuzzMspa(f, iabconsent.UsNationalSID) }
+func FuzzMspaUsCA(f *testing.F)       { fuzzMspa(f, iabconsent.UsCaliforniaSID) }
+func FuzzMspaUsVA(f *testing.F)       { fuzzMspa(f, iabconsent.UsVirginiaSID) }
+func FuzzMspaUsCO(f *testing.F)       { fuzzMspa(f, iabconsent.UsColoradoSID) }
+func FuzzMspaUsUT(f *testing.F)       { fuzzMspa(f, iabconsent.UsUtahSID) }
+func FuzzMspaUsCT(f *testing.F)       { fuzzMspa(f, iabconsent.UsConnecticutSID) }
+func FuzzMspaUsFL(f *testing.F)       { fuzzMspa(f, iabconsent.UsFloridaSID) }
+func FuzzMspaUsMT(f *testing.F)       { fuzzMspa(f, iabconsent.UsMontanaSID) }
+func FuzzMspaUsOR(f *testing.F)       { fuzzMspa(f, iabconsent.UsOregonSID) }
+func FuzzMspaUsTX(f *testing.F)       { fuzzMspa(f, iabconsent.UsTexasSID) }
+func FuzzMspaUsDE(f *testing.F)       { fuzzMspa(f, iabconsent.UsDelawareSID) }
+func FuzzMspaUsIA(f *testing.F)       { fuzzMspa(f, iabconsent.UsIowaSID) }
+func FuzzMspaUsNE(f *testing.F)       { fuzzMspa(f, iabconsent.UsNebraskaSID) }
+func FuzzMspaUsNH(f *testing.F)       { fuzzMspa(f, iabconsent.UsNewHampshireSID) }
+func FuzzMspaUsNJ(f *testing.F)       { fuzzMspa(f, iabconsent.UsNewJerseySID) }
+func FuzzMspaUsTN(f *testing.F)       { fuzzMspa(f, iabconsent.UsTennesseeSID) }
@@ -0,0 +1,114 @@
+package iabconsent
+
+var (
+	_ GppParsedConsent = &V2ParsedConsent{}
+	_ GppParsedConsent = &MspaParsedConsent{}
+)
+
+// GppConsent is the result of parsing a GPP string with ParseGppConsent.
+type GppConsent struct {
+	// The parsed GPP header, listing every section in the string.
+	Header *GppHeader
+	// The consent of every supported section that parsed successfully, keyed by Section ID.
+	Sections map[int]GppParsedConsent
+}
+
+// Section returns the consent of the section with Section ID sid, or nil if it was not parsed.
+func (g *GppConsent) Section(sid int) GppParsedConsent {
+	if g == nil {
+		return nil
+	}
+	return g.Sections[sid]
+}
+
+// TcfEuV2 returns the EU TCF v2 section, or nil if it was not parsed.
+func (g *GppConsent) TcfEuV2() *V2ParsedConsent {
+	var p, _ = g.Section(TcfEuV2SID).(*V2ParsedConsent)
+	return p
+}
+
+// Mspa returns the MSPA section with Section ID sid, or nil if it was not parsed.
+func (g *GppConsent) Mspa(sid int) *MspaParsedConsent {
+	var p, _ = g.Section(sid).(*MspaParsedConsent)
+	return p
+}
+
+// UsNational returns the US National section, or nil if it was not parsed.
+func (g *GppConsent) UsNational() *MspaParsedConsent {
+	return g.Mspa(UsNationalSID)
+}
+
+// UsCalifornia returns the US California section, or nil if it was not parsed.
+func (g *GppConsent) UsCalifornia() *MspaParsedConsent {
+	return g.Mspa(UsCaliforniaSID)
+}
+
+// UsVirginia returns the US Virginia section, or nil if it was not parsed.
+func (g *GppConsent) UsVirginia() *MspaParsedConsent {
+	return g.Mspa(UsVirginiaSID)
+}
+
+// UsColorado returns the US Colorado section, or nil if it was not parsed.
+func (g *GppConsent) UsColorado() *MspaParsedConsent {
+	return g.Mspa(UsColoradoSID)
+}
+
+// UsUtah returns the US Utah section, or nil if it was not parsed.
+func (g *GppConsent) UsUtah() *MspaParsedConsent {
+	return g.Mspa(UsUtahSID)
+}
+
+// UsConnecticut returns the US Connecticut section, or nil if it was not parsed.
+func (g *GppConsent) UsConnecticut() *MspaParsedConsent {
+	return g.Mspa(UsConnecticutSID)
+}
+
+// UsFlorida returns the US Florida section, or nil if it was not parsed.
+func (g *GppConsent) UsFlorida() *MspaParsedConsent {
+	return g.Mspa(UsFloridaSID)
+}
+
+// UsMontana returns the US Montana section, or nil if it was not parsed.
+func (g *GppConsent) UsMontana() *MspaParsedConsent {
+	return g.Mspa(UsMontanaSID)
+}
+
+// UsOregon returns the US Oregon section, or nil if it was not parsed.
+func (g *GppConsent) UsOregon() *MspaParsedConsent {
+	return g.Mspa(UsOregonSID)
+}
+
+// UsTexas returns the US Texas section, or nil if it was not parsed.
+func (g *GppConsent) UsTexas() *MspaParsedConsent {
+	return g.Mspa(UsTexasSID)
+}
+
+// UsDelaware returns the US Delaware section, or nil if it was not parsed.
+func (g *GppConsent) UsDelaware() *MspaParsedConsent {
+	return g.Mspa(UsDelawareSID)
+}
+
+// UsIowa returns the US Iowa section, or nil if it was not parsed.
+func (g *GppConsent) UsIowa() *MspaParsedConsent {
+	return g.Mspa(UsIowaSID)
+}
+
+// UsNebraska returns the US Nebraska section, or nil if it was not parsed.
+func (g *GppConsent) UsNebraska() *MspaParsedConsent {
+	return g.Mspa(UsNebraskaSID)
+}
+
+// UsNewHampshire returns the US New Hampshire section, or nil if it was not parsed.
+func (g *GppConsent) UsNewHampshire() *MspaParsedConsent {
+	return g.Mspa(UsNewHampshireSID)
+}
+
+// UsNewJersey returns the US New Jersey section, or nil if it was not parsed.
+func (g *GppConsent) UsNewJersey() *MspaParsedConsent {
+	return g.Mspa(UsNewJerseySID)
+}
+
+// UsTennessee returns the US Tennessee section, or nil if it was not parsed.
+func (g *GppConsent) UsTennessee() *MspaParsedConsent {
+	return g.Mspa(UsTennesseeSID)
+}
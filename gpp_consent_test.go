@@ -0,0 +1,71 @@
+package iabconsent_test
+
+import (
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type GppConsentSuite struct{}
+
+var _ = check.Suite(&GppConsentSuite{})
+
+func (s *GppConsentSuite) TestAccessors(c *check.C) {
+	var g, err = iabconsent.ParseGppConsent("DBABrGA~BVVqAAEABCA~BVoYYZoI~BVoYYYI~BVoYYQg~BVaGGGCA~BVoYYYQg")
+	c.Assert(err, check.IsNil)
+
+	c.Check(g.Header.Sections, check.DeepEquals, []int{7, 8, 9, 10, 11, 12})
+	c.Check(g.UsNational(), check.DeepEquals, mspaConsentFixtures[iabconsent.UsNationalSID]["BVVqAAEABCA.QA"])
+	c.Check(g.UsCalifornia(), check.DeepEquals, mspaConsentFixtures[iabconsent.UsCaliforniaSID]["BVoYYZoI"])
+	c.Check(g.UsVirginia(), check.DeepEquals, mspaConsentFixtures[iabconsent.UsVirginiaSID]["BVoYYYI"])
+	c.Check(g.UsColorado(), check.DeepEquals, mspaConsentFixtures[iabconsent.UsColoradoSID]["BVoYYQg"])
+	c.Check(g.UsUtah(), check.DeepEquals, mspaConsentFixtures[iabconsent.UsUtahSID]["BVaGGGCA.QA"])
+	c.Check(g.UsConnecticut(), check.DeepEquals, mspaConsentFixtures[iabconsent.UsConnecticutSID]["BVoYYYQg"])
+
+	// Sections absent from the string are nil.
+	c.Check(g.UsFlorida(), check.IsNil)
+	c.Check(g.UsTennessee(), check.IsNil)
+	c.Check(g.TcfEuV2(), check.IsNil)
+	c.Check(g.Section(iabconsent.TcfEuV2SID), check.IsNil)
+
+	g, err = iabconsent.ParseGppConsent("DBACNY~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1YNN")
+	c.Assert(err, check.IsNil)
+	c.Check(g.TcfEuV2(), check.NotNil)
+	c.Check(g.TcfEuV2().CMPID, check.Equals, 31)
+	c.Check(g.UsNational(), check.IsNil)
+
+	var nilConsent *iabconsent.GppConsent
+	c.Check(nilConsent.Section(iabconsent.UsNationalSID), check.IsNil)
+	c.Check(nilConsent.UsNational(), check.IsNil)
+}
+
+func (s *GppConsentSuite) TestSectionIdentity(c *check.C) {
+	var tcs = []struct {
+		gpp     string
+		sid     int
+		name    string
+		version int
+	}{
+		{gpp: "DBABLA~BVVqAAEABCA", sid: iabconsent.UsNationalSID, name: "usnat", version: 1},
+		{gpp: "DBABLA~CVVVVVVVVVVW.YA", sid: iabconsent.UsNationalSID, name: "usnat", version: 2},
+		{gpp: "DBABBg~BVoYYZoI", sid: iabconsent.UsCaliforniaSID, name: "usca", version: 1},
+		{gpp: "DBABQYA~Bqqqqqo", sid: iabconsent.UsTennesseeSID, name: "ustn", version: 1},
+		{gpp: "DBABM~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA", sid: iabconsent.TcfEuV2SID, name: "tcfeuv2", version: 2},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.gpp)
+		var g, err = iabconsent.ParseGppConsent(tc.gpp)
+		c.Assert(err, check.IsNil)
+		var p = g.Section(tc.sid)
+		c.Assert(p, check.NotNil)
+		c.Check(p.SectionID(), check.Equals, tc.sid)
+		c.Check(p.SectionName(), check.Equals, tc.name)
+		c.Check(p.SectionVersion(), check.Equals, tc.version)
+	}
+}
+
+func (s *GppConsentSuite) TestGppSectionName(c *check.C) {
+	c.Check(iabconsent.GppSectionName(iabconsent.TcfEuV2SID), check.Equals, "tcfeuv2")
+	c.Check(iabconsent.GppSectionName(iabconsent.UsNewJerseySID), check.Equals, "usnj")
+	c.Check(iabconsent.GppSectionName(6), check.Equals, "")
+}
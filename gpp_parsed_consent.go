@@ -8,6 +8,10 @@ import (
 	"github.com/pkg/errors"
 )
 
+const (
+	TcfEuV2SID = 2
+)
+
 const (
 	UsNationalSID = iota + 7
 	UsCaliforniaSID
@@ -30,14 +34,20 @@ const (
 // GppHeader is the first section of a GPP Consent String.
 // See ParseGppHeader for in-depth format.
 type GppHeader struct {
-	Type     int
-	Version  int
-	Sections []int
+	Type     int   `json:"type"`
+	Version  int   `json:"version"`
+	Sections []int `json:"sections"`
 }
 
-// GppParsedConsent is an empty interface since GPP will need to handle more consent structs
-// than just the Multi-state Privacy Agreement structs.
+// GppParsedConsent is implemented by the parsed consent of every supported GPP section.
 type GppParsedConsent interface {
+	ConsentEvaluator
+	// SectionID returns the GPP Section ID the consent was parsed from.
+	SectionID() int
+	// SectionName returns the API prefix of the section, e.g. "usnat" or "tcfeuv2".
+	SectionName() string
+	// SectionVersion returns the version of the section specification used to encode the consent.
+	SectionVersion() int
 }
 
 // GppSection contains the specific Section ID (important to match up correct parsing).
@@ -57,6 +67,44 @@ func (g *GppSection) GetSectionId() int {
 	return g.sectionId
 }
 
+// GppTcfEuV2 is the EU TCF v2 section of a GPP string, which is encoded as a TC String.
+type GppTcfEuV2 struct {
+	GppSection
+	// opts are the limits of the GPP string the section was read from, or nil for DefaultParseOptions.
+	opts *ParseOptions
+}
+
+// ParseConsent parses the section as a TC String.
+func (t *GppTcfEuV2) ParseConsent() (GppParsedConsent, error) {
+	var opts = DefaultParseOptions
+	if t.opts != nil {
+		opts = *t.opts
+	}
+	var p, err = ParseV2WithOptions(t.sectionValue, opts)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// NewGppSection returns a supported parser given a GPP Section ID.
+// If the SID is not yet supported, it will be null.
+func NewGppSection(sid int, section string) GppSectionParser {
+	if sid == TcfEuV2SID {
+		return &GppTcfEuV2{GppSection: GppSection{sectionId: TcfEuV2SID, sectionValue: section}}
+	}
+	return NewMspa(sid, section)
+}
+
+// GppSectionName returns the API prefix of the section with Section ID sid, e.g. "usnat", or an
+// empty string if the section is not supported.
+func GppSectionName(sid int) string {
+	if sid == TcfEuV2SID {
+		return "tcfeuv2"
+	}
+	return mspaSections[sid].name
+}
+
 type GppSubSection struct {
 	// Global Privacy Control (GPC) is signaled and set.
 	Gpc bool
@@ -75,7 +123,24 @@ const (
 // Type	    Int(6)	Fixed to 3 as “GPP Header field”
 // Version	Int(6)	Version of the GPP spec (version 1, as of Jan. 2023)
 // Sections	Range(Fibonacci)	List of Section IDs that are contained in the GPP string.
+//
+// ParseGppHeader enforces the limits of DefaultParseOptions, as ParseGppConsent does.
 func ParseGppHeader(s string) (*GppHeader, error) {
+	return ParseGppHeaderWithOptions(s, DefaultParseOptions)
+}
+
+// ParseGppHeaderWithOptions is ParseGppHeader, but fails with a *LimitExceededError if s exceeds the
+// limits of opts.
+func ParseGppHeaderWithOptions(s string, opts ParseOptions) (h *GppHeader, err error) {
+	defer recoverPanic(&err)
+	return parseGppHeader(s, opts)
+}
+
+// parseGppHeader parses GPP header s, within the limits of opts.
+func parseGppHeader(s string, opts ParseOptions) (*GppHeader, error) {
+	if err := checkLimit("length", len(s), opts.MaxLength); err != nil {
+		return nil, err
+	}
 	// IAB's base64 conversion means a 6 bit grouped value can be converted to 8 bit bytes.
 	// Any leftover bits <8 would be skipped in normal base64 decoding.
 	// Therefore, pad with 6 '0's w/ `A` to ensure that all bits are decoded into bytes.
@@ -84,7 +149,7 @@ func ParseGppHeader(s string) (*GppHeader, error) {
 		return nil, errors.Wrap(err, "parse gpp header consent string")
 	}
 
-	var r = NewConsentReader(b)
+	var r = NewConsentReaderWithOptions(b, opts)
 
 	var g = &GppHeader{}
 	g.Type, _ = r.ReadInt(6)
@@ -103,62 +168,134 @@ func ParseGppHeader(s string) (*GppHeader, error) {
 // of the format {gpp header}~{section 1}[.{sub-section}][~{section n}]
 // and returns each pair of section value and parsing function that should be used.
 // The pairs are returned to allow more control over how parsing functions are applied.
-func MapGppSectionToParser(s string) ([]GppSectionParser, error) {
+// It enforces the limits of DefaultParseOptions, as ParseGppConsent does.
+func MapGppSectionToParser(s string) (gppSections []GppSectionParser, err error) {
+	defer recoverPanic(&err)
+	_, gppSections, err = mapGppSectionToParser(s, DefaultParseOptions)
+	return gppSections, err
+}
+
+// mapGppSectionToParser is MapGppSectionToParser, but also returns the parsed GPP header, and
+// enforces the limits of opts.
+func mapGppSectionToParser(s string, opts ParseOptions) (*GppHeader, []GppSectionParser, error) {
 	var gppHeader *GppHeader
 	var err error
 	// ~ separated fields. with the format {gpp header}~{section 1}[.{sub-section}][~{section n}]
 	var segments = strings.Split(s, "~")
 	if len(segments) < 2 {
-		return nil, errors.New("not enough gpp segments")
+		return nil, nil, errors.New("not enough gpp segments")
 	}
 
-	gppHeader, err = ParseGppHeader(segments[0])
+	gppHeader, err = parseGppHeader(segments[0], opts)
 	if err != nil {
-		return nil, errors.Wrap(err, "read gpp header")
+		return nil, nil, errors.Wrap(err, "read gpp header")
 	} else if len(segments[1:]) != len(gppHeader.Sections) {
 		// Return early if sections in header do not match sections passed.
-		return nil, errors.New("mismatch number of sections")
+		return nil, nil, errors.New("mismatch number of sections")
 	}
 	// Go through each section and add parsing function and section value to returned value.
 	var gppSections = make([]GppSectionParser, 0)
 	for i := 1; i < len(segments); i++ {
 		var gppSection GppSectionParser
-		gppSection = NewMspa(gppHeader.Sections[i-1], segments[i])
+		gppSection = NewGppSection(gppHeader.Sections[i-1], segments[i])
+		if t, ok := gppSection.(*GppTcfEuV2); ok {
+			t.opts = &opts
+		}
 		if gppSection != nil {
 			gppSections = append(gppSections, gppSection)
 		}
 	}
-	return gppSections, nil
+	return gppHeader, gppSections, nil
 }
 
 // ParseGppConsent takes a base64 Raw URL Encoded string which represents a GPP v1 string and
-// returns a map of Section ID to ParsedConsents with consent parsed via a consecutive parsing.
-func ParseGppConsent(s string) (map[int]GppParsedConsent, error) {
+// returns a GppConsent holding the header and each section's consent, parsed via a consecutive parsing.
+//
+// ParseGppConsent enforces the limits of DefaultParseOptions on s and each of its sections, and fails
+// with a *LimitExceededError for strings which exceed them. Earlier releases parsed strings of any
+// size; use ParseGppConsentWithOptions with ParseOptions{} to parse trusted strings without limits.
+func ParseGppConsent(s string) (g *GppConsent, err error) {
+	defer recoverPanic(&err)
+	return parseGppConsent(s, nil, DefaultParseOptions)
+}
+
+// ParseGppConsentWithOptions is ParseGppConsent, but fails with a *LimitExceededError if s, or any
+// of its sections, exceeds the limits of opts.
+func ParseGppConsentWithOptions(s string, opts ParseOptions) (g *GppConsent, err error) {
+	defer recoverPanic(&err)
+	return parseGppConsent(s, nil, opts)
+}
+
+// ParseApplicableGppConsent is ParseGppConsent, but only parses the sections listed in gppSID, the
+// gpp_sid signal sent alongside the GPP string stating which sections apply to the transaction.
+// Sections which do not apply are not parsed, and are absent from the returned GppConsent, although
+// the header still lists every section of the string. Section IDs listed in gppSID but absent from
+// the header are returned as missing. It enforces the limits of DefaultParseOptions, as ParseGppConsent does.
+func ParseApplicableGppConsent(s string, gppSID []int) (g *GppConsent, missing []int, err error) {
+	defer recoverPanic(&err)
+	var applies = make(map[int]bool, len(gppSID))
+	for _, sid := range gppSID {
+		applies[sid] = true
+	}
+	if g, err = parseGppConsent(s, applies, DefaultParseOptions); err != nil {
+		return nil, nil, err
+	}
+	var inHeader = make(map[int]bool, len(g.Header.Sections))
+	for _, sid := range g.Header.Sections {
+		inHeader[sid] = true
+	}
+	for _, sid := range gppSID {
+		if !inHeader[sid] {
+			missing = append(missing, sid)
+		}
+	}
+	return g, missing, nil
+}
+
+// parseGppConsent parses GPP string s within the limits of opts. If applies is not nil, only the
+// sections it holds are parsed.
+func parseGppConsent(s string, applies map[int]bool, opts ParseOptions) (*GppConsent, error) {
+	if err := checkLimit("length", len(s), opts.MaxLength); err != nil {
+		return nil, err
+	}
+	var gppHeader *GppHeader
 	var gppSections []GppSectionParser
 	var err error
-	gppSections, err = MapGppSectionToParser(s)
+	gppHeader, gppSections, err = mapGppSectionToParser(s, opts)
 	if err != nil {
 		return nil, err
 	}
 	var gppConsents = make(map[int]GppParsedConsent, len(gppSections))
 	// Consecutively, go through each section and try to parse.
 	for _, gpp := range gppSections {
+		if applies != nil && !applies[gpp.GetSectionId()] {
+			continue
+		}
 		var consent GppParsedConsent
 		var consentErr error
 		consent, consentErr = gpp.ParseConsent()
-		if consentErr != nil {
+		if _, ok := errors.Cause(consentErr).(*LimitExceededError); ok {
+			// Unlike other errors, an exceeded limit fails the whole string.
+			return nil, errors.WithMessage(consentErr, "parse gpp section "+fmt.Sprint(gpp.GetSectionId()))
+		} else if consentErr != nil {
 			// If an error, quietly do not add the consent value to map.
 		} else {
 			gppConsents[gpp.GetSectionId()] = consent
 		}
 	}
-	return gppConsents, nil
+	return &GppConsent{Header: gppHeader, Sections: gppConsents}, nil
 }
 
 // ParseGppSubSections parses the subsections that may be appended to GPP sections after a `.`
 // Currently, GPC is the only subsection, so we only have a single Subsection parsing function.
 // In the future, Section IDs may need their own SubSection parser.
-func ParseGppSubSections(subSections []string) (*GppSubSection, error) {
+func ParseGppSubSections(subSections []string) (gppSub *GppSubSection, err error) {
+	defer recoverPanic(&err)
+	return parseGppSubSections(subSections)
+}
+
+// parseGppSubSections parses the subsections of a GPP section.
+func parseGppSubSections(subSections []string) (*GppSubSection, error) {
 	var gppSub = new(GppSubSection)
 	// There could be >1 subsection, but we will only return a single GppSubSection result.
 	for _, s := range subSections {
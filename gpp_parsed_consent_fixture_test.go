@@ -1,11 +1,13 @@
 package iabconsent_test
 
 import (
+	"time"
+
 	"github.com/LiveRamp/iabconsent"
 )
 
 // Test fixtures can be created here: https://iabgpp.com/
-var gppParsedConsentFixtures = map[string]map[int]*iabconsent.MspaParsedConsent{
+var gppParsedConsentFixtures = map[string]map[int]iabconsent.GppParsedConsent{
 	// Valid GPP w/ V1 US National MSPA, No Subsection (is the same as false GPC subsection).
 	"DBABLA~BVVqAAEABCA": {iabconsent.UsNationalSID: mspaConsentFixtures[iabconsent.UsNationalSID]["BVVqAAEABCA.QA"]},
 	// Valid GPP w/ V1 US National MSPA, Subsection of GPC False.
@@ -50,8 +52,28 @@ var gppParsedConsentFixtures = map[string]map[int]*iabconsent.MspaParsedConsent{
 		iabconsent.UsMontanaSID:     mspaConsentFixtures[iabconsent.UsMontanaSID]["Bqqqqqqo"],
 	},
 	// Valid GPP string w/ sections for EU TCF V2 and US Privacy
-	// Since both are not supported, Consent fixture should be blank.
-	"DBACNY~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1YNN": {},
+	// Since US Privacy is not supported, only EU TCF V2 is parsed.
+	"DBACNY~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1YNN": {
+		iabconsent.TcfEuV2SID: &iabconsent.V2ParsedConsent{
+			Version:                2,
+			Created:                time.Date(2022, 4, 20, 22, 0, 0, 0, time.UTC),
+			LastUpdated:            time.Date(2022, 4, 20, 22, 0, 0, 0, time.UTC),
+			CMPID:                  31,
+			CMPVersion:             640,
+			ConsentScreen:          1,
+			ConsentLanguage:        "EN",
+			VendorListVersion:      126,
+			TCFPolicyVersion:       2,
+			IsServiceSpecific:      true,
+			SpecialFeaturesOptIn:   map[int]bool{},
+			PurposesConsent:        map[int]bool{},
+			PurposesLITransparency: map[int]bool{},
+			PublisherCC:            "DE",
+			ConsentedVendors:       map[int]bool{},
+			InterestsVendors:       map[int]bool{},
+			PubRestrictionEntries:  []*iabconsent.PubRestrictionEntry{},
+		},
+	},
 	// Valid GPP w/ V1 US National MSPA and US Privacy, but skip US Privacy until supported.
 	"DBABzw~1YNN~BVVqAAEABCA.QA": {7: mspaConsentFixtures[7]["BVVqAAEABCA.QA"]},
 	// Valid GPP w/ US Florida MSPA, Subsection of GPC False.
@@ -167,9 +167,10 @@ func (s *MspaSuite) TestParseGppConsent(c *check.C) {
 		var p, err = iabconsent.ParseGppConsent(g)
 
 		c.Check(err, check.IsNil)
-		c.Check(p, check.HasLen, len(e))
+		c.Check(p.Header, check.NotNil)
+		c.Check(p.Sections, check.HasLen, len(e))
 		for i, expected := range e {
-			parsed, found := p[i]
+			parsed, found := p.Sections[i]
 			c.Check(found, check.Equals, true)
 			c.Check(parsed, check.DeepEquals, expected)
 		}
@@ -197,10 +198,54 @@ func (s *MspaSuite) TestParseGppConsentError(c *check.C) {
 
 		// Despite an error in the underlying parsing, we quietly do not add the bad value to the map.
 		c.Check(err, check.IsNil)
-		c.Check(p, check.HasLen, 0)
+		c.Check(p.Sections, check.HasLen, 0)
 	}
 }
 
+func (s *MspaSuite) TestParseApplicableGppConsent(c *check.C) {
+	var gpp = "DBABrGA~BVVqAAEABCA~BVoYYZoI~BVoYYYI~BVoYYQg~BVaGGGCA~BVoYYYQg"
+	var all, err = iabconsent.ParseGppConsent(gpp)
+	c.Assert(err, check.IsNil)
+	var tcs = []struct {
+		desc     string
+		gppSID   []int
+		sections []int
+		missing  []int
+	}{
+		{
+			desc:     "Single applicable section.",
+			gppSID:   []int{9},
+			sections: []int{9},
+		},
+		{
+			desc:     "Sections missing from the header.",
+			gppSID:   []int{2, 8, 7, 13},
+			sections: []int{7, 8},
+			missing:  []int{2, 13},
+		},
+		{
+			desc:     "No applicable sections.",
+			gppSID:   nil,
+			sections: nil,
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+
+		var p, missing, err = iabconsent.ParseApplicableGppConsent(gpp, tc.gppSID)
+		c.Check(err, check.IsNil)
+		c.Check(p.Header.Sections, check.DeepEquals, []int{7, 8, 9, 10, 11, 12})
+		c.Check(missing, check.DeepEquals, tc.missing)
+		c.Check(p.Sections, check.HasLen, len(tc.sections))
+		for _, sid := range tc.sections {
+			c.Check(p.Sections[sid], check.DeepEquals, all.Sections[sid])
+		}
+	}
+
+	_, _, err = iabconsent.ParseApplicableGppConsent("DBABL", []int{7})
+	c.Check(err, check.ErrorMatches, "not enough gpp segments")
+}
+
 func (s *GppParseSuite) TestParseGppSubSections(c *check.C) {
 	var tcs = []struct {
 		description        string
@@ -0,0 +1,413 @@
+package iabconsent
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The parsed consent structs hold ID sets as map[int]bool. In JSON these are
+// encoded as sorted arrays of the IDs which are set, so that the encoding is
+// stable and readable. A nil set is encoded as null and an empty set as [].
+
+// idsOf returns the IDs set in m, in ascending order.
+func idsOf(m map[int]bool) []int {
+	if m == nil {
+		return nil
+	}
+	var ids = make([]int, 0, len(m))
+	for id, set := range m {
+		if set {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+// setOf is the inverse of idsOf.
+func setOf(ids []int) map[int]bool {
+	if ids == nil {
+		return nil
+	}
+	var m = make(map[int]bool, len(ids))
+	for _, id := range ids {
+		m[id] = true
+	}
+	return m
+}
+
+// Vendors are encoded as sorted arrays of their IDs whether the string encodes them as a bit field
+// or as range entries, so that consumers need not handle both. The encoding is kept as metadata in
+// the is_range_encoding fields, and decoding rebuilds the vendors in that encoding.
+
+// vendorIDsOf returns the vendors of a section encoded either as a bit field or as ranges, in
+// ascending order. As with idsOf, a nil bit field is encoded as null.
+func vendorIDsOf(isRange bool, bitField map[int]bool, ranges []*RangeEntry) []int {
+	if !isRange {
+		return idsOf(bitField)
+	}
+	var ids = []int{}
+	for _, re := range mergeRanges(ranges) {
+		for v := re.StartVendorID; v <= re.EndVendorID; v++ {
+			ids = append(ids, v)
+		}
+	}
+	return ids
+}
+
+// vendorsOf is the inverse of vendorIDsOf, returning the vendors ids as a bit field, or as the
+// fewest range entries if isRange is set.
+func vendorsOf(isRange bool, ids []int) (map[int]bool, []*RangeEntry) {
+	if isRange {
+		return nil, rangeEntriesOf(setOf(ids))
+	}
+	return setOf(ids), nil
+}
+
+// specialFeaturesOf returns the special features set in m, in ascending order. They are encoded as
+// their names, like other enums, and numbers without a name as strings of the number.
+func specialFeaturesOf(m map[int]bool) []SpecialFeature {
+	var ids = idsOf(m)
+	if ids == nil {
+		return nil
+	}
+	var features = make([]SpecialFeature, len(ids))
+	for i, id := range ids {
+		features[i] = SpecialFeature(id)
+	}
+	return features
+}
+
+// specialFeatureSetOf is the inverse of specialFeaturesOf. Special features may also be given as
+// integers.
+func specialFeatureSetOf(features []SpecialFeature) map[int]bool {
+	if features == nil {
+		return nil
+	}
+	var m = make(map[int]bool, len(features))
+	for _, f := range features {
+		m[int(f)] = true
+	}
+	return m
+}
+
+type parsedConsentJSON struct {
+	Version           int       `json:"version"`
+	Created           time.Time `json:"created"`
+	LastUpdated       time.Time `json:"last_updated"`
+	CMPID             int       `json:"cmp_id"`
+	CMPVersion        int       `json:"cmp_version"`
+	ConsentScreen     int       `json:"consent_screen"`
+	ConsentLanguage   string    `json:"consent_language"`
+	VendorListVersion int       `json:"vendor_list_version"`
+	PurposesAllowed   []int     `json:"purposes_allowed"`
+	MaxVendorID       int       `json:"max_vendor_id"`
+	IsRangeEncoding   bool      `json:"is_range_encoding"`
+	DefaultConsent    bool      `json:"default_consent"`
+	ConsentedVendors  []int     `json:"consented_vendors"`
+}
+
+// MarshalJSON implements json.Marshaler. consented_vendors lists the vendors from 1 to MaxVendorID
+// with consent, whichever encoding the string uses.
+func (p *ParsedConsent) MarshalJSON() ([]byte, error) {
+	var consented []int
+	if p.IsRangeEncoding || p.ConsentedVendors != nil {
+		consented = vendorIDsOf(true, nil, v1VendorRanges(p))
+	}
+	return json.Marshal(parsedConsentJSON{
+		Version:           p.Version,
+		Created:           p.Created,
+		LastUpdated:       p.LastUpdated,
+		CMPID:             p.CMPID,
+		CMPVersion:        p.CMPVersion,
+		ConsentScreen:     p.ConsentScreen,
+		ConsentLanguage:   p.ConsentLanguage,
+		VendorListVersion: p.VendorListVersion,
+		PurposesAllowed:   idsOf(p.PurposesAllowed),
+		MaxVendorID:       p.MaxVendorID,
+		IsRangeEncoding:   p.IsRangeEncoding,
+		DefaultConsent:    p.DefaultConsent,
+		ConsentedVendors:  consented,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler. With default_consent set, the range entries list the
+// vendors up to max_vendor_id without consent.
+func (p *ParsedConsent) UnmarshalJSON(b []byte) error {
+	var j parsedConsentJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	var consented, ranges = vendorsOf(j.IsRangeEncoding, j.ConsentedVendors)
+	if j.IsRangeEncoding && j.DefaultConsent {
+		ranges = subtractRanges(v1AllVendors(&ParsedConsent{MaxVendorID: j.MaxVendorID}), mergeRanges(ranges))
+	}
+	*p = ParsedConsent{
+		Version:           j.Version,
+		Created:           j.Created,
+		LastUpdated:       j.LastUpdated,
+		CMPID:             j.CMPID,
+		CMPVersion:        j.CMPVersion,
+		ConsentScreen:     j.ConsentScreen,
+		ConsentLanguage:   j.ConsentLanguage,
+		VendorListVersion: j.VendorListVersion,
+		PurposesAllowed:   setOf(j.PurposesAllowed),
+		MaxVendorID:       j.MaxVendorID,
+		IsRangeEncoding:   j.IsRangeEncoding,
+		ConsentedVendors:  consented,
+		DefaultConsent:    j.DefaultConsent,
+		NumEntries:        len(ranges),
+		RangeEntries:      ranges,
+	}
+	return nil
+}
+
+type v2ParsedConsentJSON struct {
+	Version                  int                    `json:"version"`
+	Created                  time.Time              `json:"created"`
+	LastUpdated              time.Time              `json:"last_updated"`
+	CMPID                    int                    `json:"cmp_id"`
+	CMPVersion               int                    `json:"cmp_version"`
+	ConsentScreen            int                    `json:"consent_screen"`
+	ConsentLanguage          string                 `json:"consent_language"`
+	VendorListVersion        int                    `json:"vendor_list_version"`
+	TCFPolicyVersion         int                    `json:"tcf_policy_version"`
+	IsServiceSpecific        bool                   `json:"is_service_specific"`
+	UseNonStandardStacks     bool                   `json:"use_non_standard_stacks"`
+	SpecialFeaturesOptIn     []SpecialFeature       `json:"special_features_opt_in"`
+	PurposesConsent          []int                  `json:"purposes_consent"`
+	PurposesLITransparency   []int                  `json:"purposes_li_transparency"`
+	PurposeOneTreatment      bool                   `json:"purpose_one_treatment"`
+	PublisherCC              string                 `json:"publisher_cc"`
+	MaxConsentVendorID       int                    `json:"max_consent_vendor_id"`
+	IsConsentRangeEncoding   bool                   `json:"is_consent_range_encoding"`
+	ConsentedVendors         []int                  `json:"consented_vendors"`
+	MaxInterestsVendorID     int                    `json:"max_interests_vendor_id"`
+	IsInterestsRangeEncoding bool                   `json:"is_interests_range_encoding"`
+	InterestsVendors         []int                  `json:"interests_vendors"`
+	NumPubRestrictions       int                    `json:"num_pub_restrictions"`
+	PubRestrictionEntries    []*PubRestrictionEntry `json:"pub_restriction_entries"`
+	OOBDisclosedVendors      *OOBVendorList         `json:"oob_disclosed_vendors"`
+	OOBAllowedVendors        *OOBVendorList         `json:"oob_allowed_vendors"`
+	PublisherTCEntry         *PublisherTCEntry      `json:"publisher_tc"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (p *V2ParsedConsent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v2ParsedConsentJSON{
+		Version:                  p.Version,
+		Created:                  p.Created,
+		LastUpdated:              p.LastUpdated,
+		CMPID:                    p.CMPID,
+		CMPVersion:               p.CMPVersion,
+		ConsentScreen:            p.ConsentScreen,
+		ConsentLanguage:          p.ConsentLanguage,
+		VendorListVersion:        p.VendorListVersion,
+		TCFPolicyVersion:         p.TCFPolicyVersion,
+		IsServiceSpecific:        p.IsServiceSpecific,
+		UseNonStandardStacks:     p.UseNonStandardStacks,
+		SpecialFeaturesOptIn:     specialFeaturesOf(p.SpecialFeaturesOptIn),
+		PurposesConsent:          idsOf(p.PurposesConsent),
+		PurposesLITransparency:   idsOf(p.PurposesLITransparency),
+		PurposeOneTreatment:      p.PurposeOneTreatment,
+		PublisherCC:              p.PublisherCC,
+		MaxConsentVendorID:       p.MaxConsentVendorID,
+		IsConsentRangeEncoding:   p.IsConsentRangeEncoding,
+		ConsentedVendors:         vendorIDsOf(p.IsConsentRangeEncoding, p.ConsentedVendors, p.ConsentedVendorsRange),
+		MaxInterestsVendorID:     p.MaxInterestsVendorID,
+		IsInterestsRangeEncoding: p.IsInterestsRangeEncoding,
+		InterestsVendors:         vendorIDsOf(p.IsInterestsRangeEncoding, p.InterestsVendors, p.InterestsVendorsRange),
+		NumPubRestrictions:       p.NumPubRestrictions,
+		PubRestrictionEntries:    p.PubRestrictionEntries,
+		OOBDisclosedVendors:      p.OOBDisclosedVendors,
+		OOBAllowedVendors:        p.OOBAllowedVendors,
+		PublisherTCEntry:         p.PublisherTCEntry,
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *V2ParsedConsent) UnmarshalJSON(b []byte) error {
+	var j v2ParsedConsentJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	var consented, consentedRange = vendorsOf(j.IsConsentRangeEncoding, j.ConsentedVendors)
+	var interests, interestsRange = vendorsOf(j.IsInterestsRangeEncoding, j.InterestsVendors)
+	*p = V2ParsedConsent{
+		Version:                  j.Version,
+		Created:                  j.Created,
+		LastUpdated:              j.LastUpdated,
+		CMPID:                    j.CMPID,
+		CMPVersion:               j.CMPVersion,
+		ConsentScreen:            j.ConsentScreen,
+		ConsentLanguage:          j.ConsentLanguage,
+		VendorListVersion:        j.VendorListVersion,
+		TCFPolicyVersion:         j.TCFPolicyVersion,
+		IsServiceSpecific:        j.IsServiceSpecific,
+		UseNonStandardStacks:     j.UseNonStandardStacks,
+		SpecialFeaturesOptIn:     specialFeatureSetOf(j.SpecialFeaturesOptIn),
+		PurposesConsent:          setOf(j.PurposesConsent),
+		PurposesLITransparency:   setOf(j.PurposesLITransparency),
+		PurposeOneTreatment:      j.PurposeOneTreatment,
+		PublisherCC:              j.PublisherCC,
+		MaxConsentVendorID:       j.MaxConsentVendorID,
+		IsConsentRangeEncoding:   j.IsConsentRangeEncoding,
+		ConsentedVendors:         consented,
+		NumConsentEntries:        len(consentedRange),
+		ConsentedVendorsRange:    consentedRange,
+		MaxInterestsVendorID:     j.MaxInterestsVendorID,
+		IsInterestsRangeEncoding: j.IsInterestsRangeEncoding,
+		InterestsVendors:         interests,
+		NumInterestsEntries:      len(interestsRange),
+		InterestsVendorsRange:    interestsRange,
+		NumPubRestrictions:       j.NumPubRestrictions,
+		PubRestrictionEntries:    j.PubRestrictionEntries,
+		OOBDisclosedVendors:      j.OOBDisclosedVendors,
+		OOBAllowedVendors:        j.OOBAllowedVendors,
+		PublisherTCEntry:         j.PublisherTCEntry,
+	}
+	return nil
+}
+
+type oobVendorListJSON struct {
+	SegmentType     SegmentType `json:"segment_type"`
+	MaxVendorID     int         `json:"max_vendor_id"`
+	IsRangeEncoding bool        `json:"is_range_encoding"`
+	Vendors         []int       `json:"vendors"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (o *OOBVendorList) MarshalJSON() ([]byte, error) {
+	return json.Marshal(oobVendorListJSON{
+		SegmentType:     o.SegmentType,
+		MaxVendorID:     o.MaxVendorID,
+		IsRangeEncoding: o.IsRangeEncoding,
+		Vendors:         vendorIDsOf(o.IsRangeEncoding, o.Vendors, o.VendorEntries),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (o *OOBVendorList) UnmarshalJSON(b []byte) error {
+	var j oobVendorListJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	var vendors, entries = vendorsOf(j.IsRangeEncoding, j.Vendors)
+	*o = OOBVendorList{
+		SegmentType:     j.SegmentType,
+		MaxVendorID:     j.MaxVendorID,
+		IsRangeEncoding: j.IsRangeEncoding,
+		Vendors:         vendors,
+		NumEntries:      len(entries),
+		VendorEntries:   entries,
+	}
+	return nil
+}
+
+type publisherTCEntryJSON struct {
+	SegmentType                  SegmentType `json:"segment_type"`
+	PubPurposesConsent           []int       `json:"pub_purposes_consent"`
+	PubPurposesLITransparency    []int       `json:"pub_purposes_li_transparency"`
+	NumCustomPurposes            int         `json:"num_custom_purposes"`
+	CustomPurposesConsent        []int       `json:"custom_purposes_consent"`
+	CustomPurposesLITransparency []int       `json:"custom_purposes_li_transparency"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e *PublisherTCEntry) MarshalJSON() ([]byte, error) {
+	return json.Marshal(publisherTCEntryJSON{
+		SegmentType:                  e.SegmentType,
+		PubPurposesConsent:           idsOf(e.PubPurposesConsent),
+		PubPurposesLITransparency:    idsOf(e.PubPurposesLITransparency),
+		NumCustomPurposes:            e.NumCustomPurposes,
+		CustomPurposesConsent:        idsOf(e.CustomPurposesConsent),
+		CustomPurposesLITransparency: idsOf(e.CustomPurposesLITransparency),
+	})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *PublisherTCEntry) UnmarshalJSON(b []byte) error {
+	var j publisherTCEntryJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	*e = PublisherTCEntry{
+		SegmentType:                  j.SegmentType,
+		PubPurposesConsent:           setOf(j.PubPurposesConsent),
+		PubPurposesLITransparency:    setOf(j.PubPurposesLITransparency),
+		NumCustomPurposes:            j.NumCustomPurposes,
+		CustomPurposesConsent:        setOf(j.CustomPurposesConsent),
+		CustomPurposesLITransparency: setOf(j.CustomPurposesLITransparency),
+	}
+	return nil
+}
+
+type gppConsentJSON struct {
+	Header   *GppHeader                 `json:"header"`
+	Sections map[string]json.RawMessage `json:"sections"`
+}
+
+// MarshalJSON implements json.Marshaler. Sections are keyed by their SectionName.
+func (g *GppConsent) MarshalJSON() ([]byte, error) {
+	var j = gppConsentJSON{Header: g.Header}
+	if g.Sections != nil {
+		j.Sections = make(map[string]json.RawMessage, len(g.Sections))
+	}
+	for _, s := range g.Sections {
+		var b, err = json.Marshal(s)
+		if err != nil {
+			return nil, errors.Wrap(err, "marshal "+s.SectionName())
+		}
+		j.Sections[s.SectionName()] = b
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (g *GppConsent) UnmarshalJSON(b []byte) error {
+	var j gppConsentJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	*g = GppConsent{Header: j.Header}
+	if j.Sections != nil {
+		g.Sections = make(map[int]GppParsedConsent, len(j.Sections))
+	}
+	for name, raw := range j.Sections {
+		var sid, ok = gppSectionSID(name)
+		if !ok {
+			return errors.Errorf("unsupported gpp section %q", name)
+		}
+		var s GppParsedConsent
+		if sid == TcfEuV2SID {
+			s = &V2ParsedConsent{}
+		} else {
+			s = &MspaParsedConsent{}
+		}
+		if err := json.Unmarshal(raw, s); err != nil {
+			return errors.Wrap(err, "unmarshal "+name)
+		}
+		// The section name identifies the section, so "sid" may be omitted from the object.
+		if m, ok := s.(*MspaParsedConsent); ok && m.SID == 0 {
+			m.SID = sid
+		}
+		g.Sections[sid] = s
+	}
+	return nil
+}
+
+// gppSectionSID is the inverse of GppSectionName.
+func gppSectionSID(name string) (int, bool) {
+	if name == GppSectionName(TcfEuV2SID) {
+		return TcfEuV2SID, true
+	}
+	for sid, s := range mspaSections {
+		if s.name == name {
+			return sid, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,208 @@
+package iabconsent_test
+
+import (
+	"encoding/json"
+
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type JSONSuite struct{}
+
+var _ = check.Suite(&JSONSuite{})
+
+func (s *JSONSuite) TestV1RoundTrip(c *check.C) {
+	for _, str := range []string{
+		"BONMj34ONMj34ABACDENALqAAAAAplY",
+		"BONMj34ONMj34ABACDENALqAAAAAqACAD3AVkByAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+	} {
+		c.Log(str)
+		var p, err = iabconsent.ParseV1(str)
+		c.Assert(err, check.IsNil)
+
+		b, err := json.Marshal(p)
+		c.Assert(err, check.IsNil)
+		var out *iabconsent.ParsedConsent
+		c.Check(json.Unmarshal(b, &out), check.IsNil)
+		// Only vendors up to MaxVendorID are written, so range entries above it are dropped.
+		c.Check(out.Equal(p), check.Equals, true)
+	}
+}
+
+func (s *JSONSuite) TestV1DefaultConsent(c *check.C) {
+	// With default consent, the range entries list the vendors without consent.
+	var p = &iabconsent.ParsedConsent{
+		Version:         1,
+		MaxVendorID:     5,
+		IsRangeEncoding: true,
+		DefaultConsent:  true,
+		NumEntries:      1,
+		RangeEntries:    []*iabconsent.RangeEntry{{StartVendorID: 2, EndVendorID: 3}},
+	}
+	var b, err = json.Marshal(p)
+	c.Assert(err, check.IsNil)
+
+	var m map[string]interface{}
+	c.Assert(json.Unmarshal(b, &m), check.IsNil)
+	c.Check(m["consented_vendors"], check.DeepEquals, []interface{}{1.0, 4.0, 5.0})
+
+	var out *iabconsent.ParsedConsent
+	c.Assert(json.Unmarshal(b, &out), check.IsNil)
+	c.Check(out, check.DeepEquals, p)
+}
+
+func (s *JSONSuite) TestV2RoundTrip(c *check.C) {
+	for str := range v2ConsentFixtures {
+		c.Log(str)
+		var p, err = iabconsent.ParseV2(str)
+		c.Assert(err, check.IsNil)
+
+		b, err := json.Marshal(p)
+		c.Assert(err, check.IsNil)
+		var out *iabconsent.V2ParsedConsent
+		c.Check(json.Unmarshal(b, &out), check.IsNil)
+		c.Check(out, check.DeepEquals, p)
+	}
+}
+
+func (s *JSONSuite) TestGppRoundTrip(c *check.C) {
+	for str := range gppParsedConsentFixtures {
+		c.Log(str)
+		var p, err = iabconsent.ParseGppConsent(str)
+		c.Assert(err, check.IsNil)
+
+		b, err := json.Marshal(p)
+		c.Assert(err, check.IsNil)
+		var out *iabconsent.GppConsent
+		c.Check(json.Unmarshal(b, &out), check.IsNil)
+		c.Check(out, check.DeepEquals, p)
+	}
+}
+
+func (s *JSONSuite) TestV2Schema(c *check.C) {
+	var p = &iabconsent.V2ParsedConsent{
+		Version:          2,
+		PurposesConsent:  map[int]bool{3: true, 1: true, 2: false},
+		ConsentedVendors: map[int]bool{755: true, 12: true, 100: true},
+		InterestsVendors: map[int]bool{},
+		PubRestrictionEntries: []*iabconsent.PubRestrictionEntry{
+			{
+				PurposeID:         2,
+				RestrictionType:   iabconsent.PurposeFlatlyNotAllowed,
+				NumEntries:        1,
+				RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 1, EndVendorID: 5}},
+			},
+		},
+	}
+	var b, err = json.Marshal(p)
+	c.Assert(err, check.IsNil)
+
+	var m map[string]interface{}
+	c.Assert(json.Unmarshal(b, &m), check.IsNil)
+	c.Check(m["purposes_consent"], check.DeepEquals, []interface{}{1.0, 3.0})
+	c.Check(m["consented_vendors"], check.DeepEquals, []interface{}{12.0, 100.0, 755.0})
+	c.Check(m["interests_vendors"], check.DeepEquals, []interface{}{})
+	c.Check(m["special_features_opt_in"], check.IsNil)
+	c.Check(m["pub_restriction_entries"], check.DeepEquals, []interface{}{
+		map[string]interface{}{
+			"purpose_id":         2.0,
+			"restriction_type":   "purpose_flatly_not_allowed",
+			"num_entries":        1.0,
+			"restrictions_range": []interface{}{map[string]interface{}{"start_vendor_id": 1.0, "end_vendor_id": 5.0}},
+		},
+	})
+}
+
+func (s *JSONSuite) TestV2RangeVendors(c *check.C) {
+	// Range encoded vendors are written as IDs, like bit fields, and read back as ranges.
+	var p = &iabconsent.V2ParsedConsent{
+		Version:                2,
+		IsConsentRangeEncoding: true,
+		NumConsentEntries:      2,
+		ConsentedVendorsRange: []*iabconsent.RangeEntry{
+			{StartVendorID: 7, EndVendorID: 9},
+			{StartVendorID: 2, EndVendorID: 2},
+		},
+		OOBDisclosedVendors: &iabconsent.OOBVendorList{
+			SegmentType:     iabconsent.DisclosedVendors,
+			MaxVendorID:     4,
+			IsRangeEncoding: true,
+			NumEntries:      1,
+			VendorEntries:   []*iabconsent.RangeEntry{{StartVendorID: 3, EndVendorID: 4}},
+		},
+	}
+	var b, err = json.Marshal(p)
+	c.Assert(err, check.IsNil)
+
+	var m map[string]interface{}
+	c.Assert(json.Unmarshal(b, &m), check.IsNil)
+	c.Check(m["is_consent_range_encoding"], check.Equals, true)
+	c.Check(m["consented_vendors"], check.DeepEquals, []interface{}{2.0, 7.0, 8.0, 9.0})
+	c.Check(m["consented_vendors_range"], check.IsNil)
+	c.Check(m["oob_disclosed_vendors"].(map[string]interface{})["vendors"], check.DeepEquals, []interface{}{3.0, 4.0})
+
+	var out *iabconsent.V2ParsedConsent
+	c.Assert(json.Unmarshal(b, &out), check.IsNil)
+	c.Check(out.NumConsentEntries, check.Equals, 2)
+	c.Check(out.ConsentedVendorsRange, check.DeepEquals, []*iabconsent.RangeEntry{
+		{StartVendorID: 2, EndVendorID: 2},
+		{StartVendorID: 7, EndVendorID: 9},
+	})
+	c.Check(out.OOBDisclosedVendors.VendorEntries, check.DeepEquals, []*iabconsent.RangeEntry{{StartVendorID: 3, EndVendorID: 4}})
+	c.Check(out.Equal(p), check.Equals, true)
+}
+
+func (s *JSONSuite) TestV2SpecialFeatures(c *check.C) {
+	var p = &iabconsent.V2ParsedConsent{
+		Version:              2,
+		SpecialFeaturesOptIn: map[int]bool{2: true, 1: true, 5: true, 3: false},
+	}
+	var b, err = json.Marshal(p)
+	c.Assert(err, check.IsNil)
+
+	var m map[string]interface{}
+	c.Assert(json.Unmarshal(b, &m), check.IsNil)
+	c.Check(m["special_features_opt_in"], check.DeepEquals, []interface{}{"use_precise_geolocation", "actively_scan_device", "5"})
+
+	var out *iabconsent.V2ParsedConsent
+	c.Assert(json.Unmarshal(b, &out), check.IsNil)
+	c.Check(out.SpecialFeaturesOptIn, check.DeepEquals, map[int]bool{1: true, 2: true, 5: true})
+
+	// Special features may also be given as integers.
+	c.Assert(json.Unmarshal([]byte(`{"special_features_opt_in":[1,"actively_scan_device",7]}`), &out), check.IsNil)
+	c.Check(out.SpecialFeaturesOptIn, check.DeepEquals, map[int]bool{1: true, 2: true, 7: true})
+
+	c.Check(json.Unmarshal([]byte(`{"special_features_opt_in":["fly"]}`), &out), check.ErrorMatches, `unknown special feature "fly"`)
+}
+
+func (s *JSONSuite) TestMspaSchema(c *check.C) {
+	var b, err = json.Marshal(mspaConsentFixtures[iabconsent.UsVirginiaSID]["BVoYYYI"])
+	c.Assert(err, check.IsNil)
+	c.Check(string(b), check.Equals, `{"sid":9,"version":1,"sharing_notice":"provided","sale_opt_out_notice":"provided",`+
+		`"sharing_opt_out_notice":"not_applicable","targeted_advertising_opt_out_notice":"provided",`+
+		`"sensitive_data_processing_opt_out_notice":"not_applicable","sensitive_data_limit_use_notice":"not_applicable",`+
+		`"sale_opt_out":"not_opted_out","sharing_opt_out":"not_applicable","targeted_advertising_opt_out":"not_opted_out",`+
+		`"sensitive_data_processing_consents":{"0":"not_applicable","1":"no_consent","2":"consent","3":"not_applicable",`+
+		`"4":"no_consent","5":"consent","6":"not_applicable","7":"no_consent"},"sensitive_data_processing_opt_outs":null,`+
+		`"known_child_sensitive_data_consents":{"0":"consent"},"personal_data_consents":"not_applicable",`+
+		`"covered_transaction":"not_applicable","opt_out_option_mode":"not_applicable","service_provider_mode":"no",`+
+		`"gpc":false}`)
+}
+
+func (s *JSONSuite) TestGppSchema(c *check.C) {
+	var p, err = iabconsent.ParseGppConsent("DBACLMA~BVVqAAEABCA~BVoYYYI")
+	c.Assert(err, check.IsNil)
+
+	b, err := json.Marshal(p)
+	c.Assert(err, check.IsNil)
+	var m map[string]map[string]interface{}
+	c.Assert(json.Unmarshal(b, &m), check.IsNil)
+	c.Check(m["header"]["sections"], check.DeepEquals, []interface{}{7.0, 9.0})
+	c.Check(m["sections"], check.HasLen, 2)
+	c.Check(m["sections"]["usnat"], check.NotNil)
+	c.Check(m["sections"]["usva"], check.NotNil)
+
+	var out iabconsent.GppConsent
+	c.Check(json.Unmarshal([]byte(`{"sections":{"usxx":{}}}`), &out), check.ErrorMatches, `unsupported gpp section "usxx"`)
+}
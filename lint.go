@@ -0,0 +1,223 @@
+package iabconsent
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LintSeverity is an enum type of the severity of a LintFinding.
+type LintSeverity int
+
+const (
+	UnknownSeverity LintSeverity = iota
+	// The string is valid, but unusual.
+	SeverityInfo
+	// The string is valid, but CMPs and vendors may treat it inconsistently.
+	SeverityWarning
+	// The string violates the TCF v2 specification, and must be discarded.
+	SeverityError
+)
+
+// String returns the name of the severity, e.g. "warning".
+func (s LintSeverity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return fmt.Sprintf("unknown severity %d", int(s))
+	}
+}
+
+// LintRule is an enum type of the rules checked by LintV2.
+type LintRule int
+
+const (
+	UnknownLintRule LintRule = iota
+	// Created is after LastUpdated.
+	LintCreatedAfterLastUpdated
+	// PurposeOneTreatment is set on a globally-scoped string.
+	LintPurposeOneTreatmentGlobal
+	// PublisherCC is not an ISO 3166-1 alpha-2 country code.
+	LintInvalidPublisherCC
+	// ConsentLanguage is not an ISO 639-1 language code.
+	LintInvalidConsentLanguage
+	// A range entry includes a vendor ID above the MaxVendorID of its section.
+	LintMaxVendorIDTooLow
+	// A range entry starts at 0, or ends before it starts.
+	LintInvalidRange
+	// The range entries of a section are not sorted by vendor ID.
+	LintRangeUnsorted
+	// The range entries of a section overlap.
+	LintRangeOverlap
+	// A publisher restriction has a purpose ID outside 1-24.
+	LintPubRestrictionPurposeOutOfRange
+	// A publisher restriction has the Undefined restriction type.
+	LintUndefinedRestrictionType
+	// CMPID is not in the CMPList. See CMPList.CheckV2.
+	LintUnknownCMP
+	// CMPID is of a CMP deleted from the CMPList. See CMPList.CheckV2.
+	LintDeletedCMP
+	// A range or publisher restriction entry is nil, which can only occur in consents built in code.
+	LintNilEntry
+)
+
+// LintFinding is a single problem found by LintV2.
+type LintFinding struct {
+	Rule     LintRule
+	Severity LintSeverity
+	// Message describes the problem, e.g. "publisher_cc "AA" is not an ISO 3166-1 alpha-2 code".
+	Message string
+}
+
+// String returns the severity and message of the finding, e.g. "error: purpose_one_treatment is set on a global scope string".
+func (f LintFinding) String() string {
+	return f.Severity.String() + ": " + f.Message
+}
+
+// LintV2 checks p against the rules of the TCF v2 specification which ParseV2 does not enforce, as the
+// IAB CMP validator does, and returns every problem found. The result is empty if none are found, or
+// if p is nil.
+func LintV2(p *V2ParsedConsent) []LintFinding {
+	var l linter
+	if p == nil {
+		return nil
+	}
+
+	if p.Created.After(p.LastUpdated) {
+		l.add(LintCreatedAfterLastUpdated, SeverityError, "created %s is after last_updated %s",
+			p.Created.UTC().Format("2006-01-02T15:04:05.0Z"), p.LastUpdated.UTC().Format("2006-01-02T15:04:05.0Z"))
+	}
+	if p.PurposeOneTreatment && !p.IsServiceSpecific {
+		l.add(LintPurposeOneTreatmentGlobal, SeverityError, "purpose_one_treatment is set on a global scope string")
+	}
+	if !iso3166Alpha2[p.PublisherCC] {
+		l.add(LintInvalidPublisherCC, SeverityWarning, "publisher_cc %q is not an ISO 3166-1 alpha-2 code", p.PublisherCC)
+	}
+	if !iso639Alpha2[p.ConsentLanguage] {
+		l.add(LintInvalidConsentLanguage, SeverityWarning, "consent_language %q is not an ISO 639-1 code", p.ConsentLanguage)
+	}
+
+	if p.IsConsentRangeEncoding {
+		l.ranges("consented_vendors_range", p.ConsentedVendorsRange, p.MaxConsentVendorID)
+	}
+	if p.IsInterestsRangeEncoding {
+		l.ranges("interests_vendors_range", p.InterestsVendorsRange, p.MaxInterestsVendorID)
+	}
+	for i, pr := range p.PubRestrictionEntries {
+		var name = fmt.Sprintf("pub_restriction_entries[%d]", i)
+		if pr == nil {
+			l.add(LintNilEntry, SeverityError, "%s is nil", name)
+			continue
+		}
+		if pr.PurposeID < 1 || pr.PurposeID > 24 {
+			l.add(LintPubRestrictionPurposeOutOfRange, SeverityError, "%s purpose_id %d is outside 1-24", name, pr.PurposeID)
+		}
+		if pr.RestrictionType == Undefined {
+			l.add(LintUndefinedRestrictionType, SeverityError, "%s restriction_type is undefined", name)
+		}
+		l.ranges(name+".restrictions_range", pr.RestrictionsRange, 0)
+	}
+	for _, v := range []*OOBVendorList{p.OOBDisclosedVendors, p.OOBAllowedVendors} {
+		if v != nil && v.IsRangeEncoding {
+			l.ranges(v.SegmentType.String()+".vendor_entries", v.VendorEntries, v.MaxVendorID)
+		}
+	}
+	return l.findings
+}
+
+type linter struct {
+	findings []LintFinding
+}
+
+func (l *linter) add(rule LintRule, severity LintSeverity, format string, args ...interface{}) {
+	l.findings = append(l.findings, LintFinding{Rule: rule, Severity: severity, Message: fmt.Sprintf(format, args...)})
+}
+
+// ranges checks the range entries of section name, whose vendor IDs must not exceed max. A max of 0
+// means the section has no MaxVendorID.
+func (l *linter) ranges(name string, entries []*RangeEntry, max int) {
+	var highest, prevStart int
+	var sorted = true
+	for i, re := range entries {
+		if re == nil {
+			l.add(LintNilEntry, SeverityError, "%s[%d] is nil", name, i)
+			continue
+		}
+		if re.StartVendorID < 1 || re.EndVendorID < re.StartVendorID {
+			l.add(LintInvalidRange, SeverityError, "%s[%d] %d-%d is not a valid range", name, i, re.StartVendorID, re.EndVendorID)
+			continue
+		}
+		if re.EndVendorID > highest {
+			highest = re.EndVendorID
+		}
+		// Overlaps are reported by LintRangeOverlap, so only the start of each entry is compared.
+		if prevStart != 0 && re.StartVendorID < prevStart {
+			sorted = false
+		}
+		prevStart = re.StartVendorID
+	}
+	if max > 0 && highest > max {
+		l.add(LintMaxVendorIDTooLow, SeverityError, "%s includes vendor %d, above max vendor id %d", name, highest, max)
+	}
+	if !sorted {
+		l.add(LintRangeUnsorted, SeverityWarning, "%s is not sorted by vendor id", name)
+	}
+	if overlap := overlappingRanges(entries); overlap != "" {
+		l.add(LintRangeOverlap, SeverityWarning, "%s has overlapping ranges %s", name, overlap)
+	}
+}
+
+// overlappingRanges returns the first pair of valid entries which overlap, formatted as "1-5 and 3-7",
+// or "" if none do.
+func overlappingRanges(entries []*RangeEntry) string {
+	var valid = make([]*RangeEntry, 0, len(entries))
+	for _, re := range entries {
+		if re != nil && re.StartVendorID >= 1 && re.EndVendorID >= re.StartVendorID {
+			valid = append(valid, re)
+		}
+	}
+	sort.SliceStable(valid, func(i, j int) bool { return valid[i].StartVendorID < valid[j].StartVendorID })
+	for i := 1; i < len(valid); i++ {
+		if valid[i].StartVendorID <= valid[i-1].EndVendorID {
+			return fmt.Sprintf("%d-%d and %d-%d", valid[i-1].StartVendorID, valid[i-1].EndVendorID,
+				valid[i].StartVendorID, valid[i].EndVendorID)
+		}
+	}
+	return ""
+}
+
+// iso639Alpha2 is the set of ISO 639-1 language codes, in upper case as read by ReadString.
+var iso639Alpha2 = codeSet(`
+	AA AB AE AF AK AM AN AR AS AV AY AZ BA BE BG BI BM BN BO BR BS CA CE CH CO CR CS CU CV CY
+	DA DE DV DZ EE EL EN EO ES ET EU FA FF FI FJ FO FR FY GA GD GL GN GU GV HA HE HI HO HR HT
+	HU HY HZ IA ID IE IG II IK IO IS IT IU JA JV KA KG KI KJ KK KL KM KN KO KR KS KU KV KW KY
+	LA LB LG LI LN LO LT LU LV MG MH MI MK ML MN MR MS MT MY NA NB ND NE NG NL NN NO NR NV NY
+	OC OJ OM OR OS PA PI PL PS PT QU RM RN RO RU RW SA SC SD SE SG SI SK SL SM SN SO SQ SR SS
+	ST SU SV SW TA TE TG TH TI TK TL TN TO TR TS TT TW TY UG UK UR UZ VE VI VO WA WO XH YI YO
+	ZA ZH ZU`)
+
+// iso3166Alpha2 is the set of ISO 3166-1 alpha-2 country codes.
+var iso3166Alpha2 = codeSet(`
+	AD AE AF AG AI AL AM AO AQ AR AS AT AU AW AX AZ BA BB BD BE BF BG BH BI BJ BL BM BN BO BQ
+	BR BS BT BV BW BY BZ CA CC CD CF CG CH CI CK CL CM CN CO CR CU CV CW CX CY CZ DE DJ DK DM
+	DO DZ EC EE EG EH ER ES ET FI FJ FK FM FO FR GA GB GD GE GF GG GH GI GL GM GN GP GQ GR GS
+	GT GU GW GY HK HM HN HR HT HU ID IE IL IM IN IO IQ IR IS IT JE JM JO JP KE KG KH KI KM KN
+	KP KR KW KY KZ LA LB LC LI LK LR LS LT LU LV LY MA MC MD ME MF MG MH MK ML MM MN MO MP MQ
+	MR MS MT MU MV MW MX MY MZ NA NC NE NF NG NI NL NO NP NR NU NZ OM PA PE PF PG PH PK PL PM
+	PN PR PS PT PW PY QA RE RO RS RU RW SA SB SC SD SE SG SH SI SJ SK SL SM SN SO SR SS ST SV
+	SX SY SZ TC TD TF TG TH TJ TK TL TM TN TO TR TT TV TW TZ UA UG UM US UY UZ VA VC VE VG VI
+	VN VU WF WS YE YT ZA ZM ZW`)
+
+// codeSet returns the set of the white space separated codes.
+func codeSet(codes string) map[string]bool {
+	var m = make(map[string]bool)
+	for _, c := range strings.Fields(codes) {
+		m[c] = true
+	}
+	return m
+}
@@ -0,0 +1,253 @@
+package iabconsent_test
+
+import (
+	"time"
+
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type LintSuite struct{}
+
+var _ = check.Suite(&LintSuite{})
+
+// lintBase returns a service-specific consent with range encoded vendors and a publisher
+// restriction, which LintV2 finds no problems with.
+func lintBase() *iabconsent.V2ParsedConsent {
+	var p = v2BaseConsent()
+	p.LastUpdated = p.Created.Add(time.Hour)
+	p.IsServiceSpecific = true
+	p.PurposeOneTreatment = true
+	p.MaxConsentVendorID = 20
+	p.IsConsentRangeEncoding = true
+	p.ConsentedVendors = nil
+	p.NumConsentEntries = 2
+	p.ConsentedVendorsRange = []*iabconsent.RangeEntry{
+		{StartVendorID: 1, EndVendorID: 5},
+		{StartVendorID: 10, EndVendorID: 20},
+	}
+	p.MaxInterestsVendorID = 3
+	p.InterestsVendors = map[int]bool{1: true, 3: true}
+	p.NumPubRestrictions = 1
+	p.PubRestrictionEntries = []*iabconsent.PubRestrictionEntry{
+		{
+			PurposeID:         24,
+			RestrictionType:   iabconsent.RequireConsent,
+			NumEntries:        1,
+			RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 2, EndVendorID: 2}},
+		},
+	}
+	return p
+}
+
+func (s *LintSuite) TestLintV2(c *check.C) {
+	var tcs = []struct {
+		desc     string
+		mutate   func(p *iabconsent.V2ParsedConsent)
+		expected []iabconsent.LintFinding
+	}{
+		{
+			desc:   "No findings.",
+			mutate: func(p *iabconsent.V2ParsedConsent) {},
+		},
+		{
+			desc:   "Created after LastUpdated.",
+			mutate: func(p *iabconsent.V2ParsedConsent) { p.Created, p.LastUpdated = p.LastUpdated, p.Created },
+			expected: []iabconsent.LintFinding{{
+				Rule:     iabconsent.LintCreatedAfterLastUpdated,
+				Severity: iabconsent.SeverityError,
+				Message:  "created 2020-01-01T01:00:00.0Z is after last_updated 2020-01-01T00:00:00.0Z",
+			}},
+		},
+		{
+			desc:   "PurposeOneTreatment on a global scope string.",
+			mutate: func(p *iabconsent.V2ParsedConsent) { p.IsServiceSpecific = false },
+			expected: []iabconsent.LintFinding{{
+				Rule:     iabconsent.LintPurposeOneTreatmentGlobal,
+				Severity: iabconsent.SeverityError,
+				Message:  "purpose_one_treatment is set on a global scope string",
+			}},
+		},
+		{
+			desc: "Invalid PublisherCC and ConsentLanguage.",
+			mutate: func(p *iabconsent.V2ParsedConsent) {
+				p.PublisherCC = "AA"
+				p.ConsentLanguage = "XX"
+			},
+			expected: []iabconsent.LintFinding{
+				{
+					Rule:     iabconsent.LintInvalidPublisherCC,
+					Severity: iabconsent.SeverityWarning,
+					Message:  `publisher_cc "AA" is not an ISO 3166-1 alpha-2 code`,
+				},
+				{
+					Rule:     iabconsent.LintInvalidConsentLanguage,
+					Severity: iabconsent.SeverityWarning,
+					Message:  `consent_language "XX" is not an ISO 639-1 code`,
+				},
+			},
+		},
+		{
+			desc:   "MaxVendorID lower than a range.",
+			mutate: func(p *iabconsent.V2ParsedConsent) { p.MaxConsentVendorID = 15 },
+			expected: []iabconsent.LintFinding{{
+				Rule:     iabconsent.LintMaxVendorIDTooLow,
+				Severity: iabconsent.SeverityError,
+				Message:  "consented_vendors_range includes vendor 20, above max vendor id 15",
+			}},
+		},
+		{
+			desc: "Unsorted and overlapping ranges.",
+			mutate: func(p *iabconsent.V2ParsedConsent) {
+				p.ConsentedVendorsRange = []*iabconsent.RangeEntry{
+					{StartVendorID: 10, EndVendorID: 20},
+					{StartVendorID: 1, EndVendorID: 12},
+				}
+			},
+			expected: []iabconsent.LintFinding{
+				{
+					Rule:     iabconsent.LintRangeUnsorted,
+					Severity: iabconsent.SeverityWarning,
+					Message:  "consented_vendors_range is not sorted by vendor id",
+				},
+				{
+					Rule:     iabconsent.LintRangeOverlap,
+					Severity: iabconsent.SeverityWarning,
+					Message:  "consented_vendors_range has overlapping ranges 1-12 and 10-20",
+				},
+			},
+		},
+		{
+			desc: "Sorted, but overlapping ranges.",
+			mutate: func(p *iabconsent.V2ParsedConsent) {
+				p.ConsentedVendorsRange = []*iabconsent.RangeEntry{
+					{StartVendorID: 1, EndVendorID: 12},
+					{StartVendorID: 10, EndVendorID: 20},
+				}
+			},
+			expected: []iabconsent.LintFinding{{
+				Rule:     iabconsent.LintRangeOverlap,
+				Severity: iabconsent.SeverityWarning,
+				Message:  "consented_vendors_range has overlapping ranges 1-12 and 10-20",
+			}},
+		},
+		{
+			desc: "Unsorted, but not overlapping ranges.",
+			mutate: func(p *iabconsent.V2ParsedConsent) {
+				p.ConsentedVendorsRange[0], p.ConsentedVendorsRange[1] = p.ConsentedVendorsRange[1], p.ConsentedVendorsRange[0]
+			},
+			expected: []iabconsent.LintFinding{{
+				Rule:     iabconsent.LintRangeUnsorted,
+				Severity: iabconsent.SeverityWarning,
+				Message:  "consented_vendors_range is not sorted by vendor id",
+			}},
+		},
+		{
+			desc: "Invalid range.",
+			mutate: func(p *iabconsent.V2ParsedConsent) {
+				p.ConsentedVendorsRange = append(p.ConsentedVendorsRange, &iabconsent.RangeEntry{StartVendorID: 0, EndVendorID: 3})
+			},
+			expected: []iabconsent.LintFinding{{
+				Rule:     iabconsent.LintInvalidRange,
+				Severity: iabconsent.SeverityError,
+				Message:  "consented_vendors_range[2] 0-3 is not a valid range",
+			}},
+		},
+		{
+			desc: "Publisher restriction purpose out of range, with the Undefined restriction type.",
+			mutate: func(p *iabconsent.V2ParsedConsent) {
+				p.PubRestrictionEntries[0].PurposeID = 25
+				p.PubRestrictionEntries[0].RestrictionType = iabconsent.Undefined
+			},
+			expected: []iabconsent.LintFinding{
+				{
+					Rule:     iabconsent.LintPubRestrictionPurposeOutOfRange,
+					Severity: iabconsent.SeverityError,
+					Message:  "pub_restriction_entries[0] purpose_id 25 is outside 1-24",
+				},
+				{
+					Rule:     iabconsent.LintUndefinedRestrictionType,
+					Severity: iabconsent.SeverityError,
+					Message:  "pub_restriction_entries[0] restriction_type is undefined",
+				},
+			},
+		},
+		{
+			desc: "Nil entries.",
+			mutate: func(p *iabconsent.V2ParsedConsent) {
+				p.ConsentedVendorsRange = append(p.ConsentedVendorsRange, nil)
+				p.PubRestrictionEntries = append(p.PubRestrictionEntries, nil)
+				p.PubRestrictionEntries[0].RestrictionsRange = append([]*iabconsent.RangeEntry{nil},
+					p.PubRestrictionEntries[0].RestrictionsRange...)
+			},
+			expected: []iabconsent.LintFinding{
+				{
+					Rule:     iabconsent.LintNilEntry,
+					Severity: iabconsent.SeverityError,
+					Message:  "consented_vendors_range[2] is nil",
+				},
+				{
+					Rule:     iabconsent.LintNilEntry,
+					Severity: iabconsent.SeverityError,
+					Message:  "pub_restriction_entries[0].restrictions_range[0] is nil",
+				},
+				{
+					Rule:     iabconsent.LintNilEntry,
+					Severity: iabconsent.SeverityError,
+					Message:  "pub_restriction_entries[1] is nil",
+				},
+			},
+		},
+		{
+			desc: "Out-of-band vendors above MaxVendorID.",
+			mutate: func(p *iabconsent.V2ParsedConsent) {
+				p.OOBDisclosedVendors = &iabconsent.OOBVendorList{
+					SegmentType:     iabconsent.DisclosedVendors,
+					MaxVendorID:     5,
+					IsRangeEncoding: true,
+					NumEntries:      1,
+					VendorEntries:   []*iabconsent.RangeEntry{{StartVendorID: 4, EndVendorID: 6}},
+				}
+			},
+			expected: []iabconsent.LintFinding{{
+				Rule:     iabconsent.LintMaxVendorIDTooLow,
+				Severity: iabconsent.SeverityError,
+				Message:  "disclosed_vendors.vendor_entries includes vendor 6, above max vendor id 5",
+			}},
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		var p = lintBase()
+		tc.mutate(p)
+		c.Check(iabconsent.LintV2(p), check.DeepEquals, tc.expected)
+	}
+	c.Check(iabconsent.LintV2(nil), check.HasLen, 0)
+}
+
+func (s *LintSuite) TestLintV2Fixtures(c *check.C) {
+	var tcs = []struct {
+		s        string
+		expected []string
+	}{
+		{
+			s:        "COvzTO5OvzTO5BRAAAENAPCoALIAADgAAAAAAewAwABAAlAB6ABBFADBQAQA9hAAAcAA",
+			expected: []string{`warning: publisher_cc "AA" is not an ISO 3166-1 alpha-2 code`},
+		},
+		{
+			s:        "COvzTO5OvzTO5B7ABCENAPCYAKdAADkAAIqIFhwBAAGAAXAFGAsMAhYAgAMAAegBYAEKAAA.IFoEUQQgAIQwgIwQABAEAAAAOIAACAIAAAAQAIAgEAACEAAAAAgAQBAAAAAAAGBAAgAAAAAAAFAAECAAAgAAQARAEQAAAAAJAAIAAgAAAYQEAAAQmAgBC3ZAYzUw.QE5QAwCvgHyATkA",
+			expected: []string{"error: purpose_one_treatment is set on a global scope string"},
+		},
+	}
+	for _, tc := range tcs {
+		var p, err = iabconsent.ParseV2(tc.s)
+		c.Assert(err, check.IsNil)
+
+		var found []string
+		for _, f := range iabconsent.LintV2(p) {
+			found = append(found, f.String())
+		}
+		c.Check(found, check.DeepEquals, tc.expected)
+	}
+}
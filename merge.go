@@ -0,0 +1,96 @@
+package iabconsent
+
+import (
+	"github.com/pkg/errors"
+)
+
+// MergeV2 merges update, a globally-scoped TC String just saved by a CMP, into existing, the
+// globally-scoped TC String previously stored for the user, as the TCF v2 specification requires
+// of a CMP updating a global-scope string:
+//
+//   - the signals of update replace those of existing,
+//   - Created is retained from existing, and LastUpdated is taken from update,
+//   - the DisclosedVendors segment is the union of both, as a CMP must retain the vendors
+//     disclosed by other CMPs in prior interactions and only add new ones.
+//
+// The DisclosedVendors segment of the result is written with whichever of the bit field and range
+// encodings is smaller. The result shares every other field with update, so neither should be
+// modified while the result is in use. See MergeV2Strings to merge and re-encode TC Strings.
+func MergeV2(existing, update *V2ParsedConsent) (*V2ParsedConsent, error) {
+	switch {
+	case existing == nil || update == nil:
+		return nil, errors.New("merge v2: nil consent")
+	case existing.IsServiceSpecific || update.IsServiceSpecific:
+		return nil, errors.New("merge v2: only globally-scoped strings can be merged")
+	case update.LastUpdated.Before(existing.LastUpdated):
+		return nil, errors.New("merge v2: update was last updated before the existing string")
+	}
+
+	var merged = *update
+	if !existing.Created.IsZero() {
+		merged.Created = existing.Created
+	}
+
+	var disclosed = unionRanges(oobVendorRanges(existing.OOBDisclosedVendors), oobVendorRanges(update.OOBDisclosedVendors))
+	if len(disclosed) != 0 || existing.OOBDisclosedVendors != nil || update.OOBDisclosedVendors != nil {
+		merged.OOBDisclosedVendors = newOOBVendorList(DisclosedVendors, disclosed)
+	}
+	return &merged, nil
+}
+
+// MergeV2Strings parses existing and update, merges them with MergeV2, and returns the merged
+// TC String.
+func MergeV2Strings(existing, update string) (string, error) {
+	var e, err = ParseV2(existing)
+	if err != nil {
+		return "", errors.Wrap(err, "parse existing consent")
+	}
+	var u *V2ParsedConsent
+	if u, err = ParseV2(update); err != nil {
+		return "", errors.Wrap(err, "parse update consent")
+	}
+	var merged *V2ParsedConsent
+	if merged, err = MergeV2(e, u); err != nil {
+		return "", err
+	}
+	return EncodeV2(merged)
+}
+
+// newOOBVendorList returns an OOBVendorList of the vendors of the merged ranges, with whichever of the
+// bit field and range encodings is smaller.
+func newOOBVendorList(st SegmentType, ranges []*RangeEntry) *OOBVendorList {
+	var v = &OOBVendorList{SegmentType: st}
+	v.MaxVendorID, v.IsRangeEncoding = vendorEncoding(ranges)
+	if v.IsRangeEncoding {
+		v.NumEntries = len(ranges)
+		v.VendorEntries = ranges
+	} else {
+		v.Vendors = bitFieldOf(ranges)
+	}
+	return v
+}
+
+// vendorEncoding returns the highest vendor of the merged ranges, and whether their range encoding is
+// smaller than the bit field encoding. When it is not, the bit field holds at most as many vendors as
+// there are bits in the range encoding, so it can safely be built with bitFieldOf.
+func vendorEncoding(ranges []*RangeEntry) (max int, isRange bool) {
+	if len(ranges) == 0 {
+		return 0, false
+	}
+	max = ranges[len(ranges)-1].EndVendorID
+	// A range section holds a 12 bit count followed by its entries, and a bit field one bit per vendor.
+	return max, 12+rangeEntriesBits(ranges) < max
+}
+
+// rangeEntriesBits returns the number of bits used to encode entries: per entry a 1 bit flag and
+// one or two 16 bit IDs.
+func rangeEntriesBits(entries []*RangeEntry) int {
+	var n int
+	for _, re := range entries {
+		n += 17
+		if re.StartVendorID != re.EndVendorID {
+			n += 16
+		}
+	}
+	return n
+}
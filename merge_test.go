@@ -0,0 +1,163 @@
+package iabconsent_test
+
+import (
+	"time"
+
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type MergeSuite struct{}
+
+var _ = check.Suite(&MergeSuite{})
+
+// mergeConsent returns a globally-scoped consent last updated at updated, which consents to
+// vendors and discloses disclosed.
+func mergeConsent(updated time.Time, vendors map[int]bool, disclosed *iabconsent.OOBVendorList) *iabconsent.V2ParsedConsent {
+	var max int
+	for v := range vendors {
+		if v > max {
+			max = v
+		}
+	}
+	var p = v2BaseConsent()
+	p.Created, p.LastUpdated = updated, updated
+	p.MaxConsentVendorID = max
+	p.ConsentedVendors = vendors
+	p.OOBDisclosedVendors = disclosed
+	return p
+}
+
+func (s *MergeSuite) TestMergeV2(c *check.C) {
+	var t1 = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var t2 = t1.Add(24 * time.Hour)
+
+	var existing = mergeConsent(t1, map[int]bool{1: true, 2: true}, &iabconsent.OOBVendorList{
+		SegmentType: iabconsent.DisclosedVendors,
+		MaxVendorID: 3,
+		Vendors:     map[int]bool{1: true, 2: true, 3: true},
+	})
+	var update = mergeConsent(t2, map[int]bool{2: true, 4: true}, &iabconsent.OOBVendorList{
+		SegmentType:     iabconsent.DisclosedVendors,
+		MaxVendorID:     4,
+		IsRangeEncoding: true,
+		NumEntries:      1,
+		VendorEntries:   []*iabconsent.RangeEntry{{StartVendorID: 2, EndVendorID: 4}},
+	})
+
+	var merged, err = iabconsent.MergeV2(existing, update)
+	c.Assert(err, check.IsNil)
+	c.Check(merged.Created, check.Equals, t1)
+	c.Check(merged.LastUpdated, check.Equals, t2)
+	c.Check(merged.ConsentedVendors, check.DeepEquals, map[int]bool{2: true, 4: true})
+	c.Check(merged.OOBDisclosedVendors, check.DeepEquals, &iabconsent.OOBVendorList{
+		SegmentType: iabconsent.DisclosedVendors,
+		MaxVendorID: 4,
+		Vendors:     map[int]bool{1: true, 2: true, 3: true, 4: true},
+	})
+	// Neither input is modified.
+	c.Check(existing.OOBDisclosedVendors.Vendors, check.HasLen, 3)
+	c.Check(update.Created, check.Equals, t2)
+}
+
+func (s *MergeSuite) TestMergeV2RangeEncoding(c *check.C) {
+	var t1 = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var existing = mergeConsent(t1, map[int]bool{}, &iabconsent.OOBVendorList{
+		SegmentType:     iabconsent.DisclosedVendors,
+		MaxVendorID:     800,
+		IsRangeEncoding: true,
+		NumEntries:      1,
+		VendorEntries:   []*iabconsent.RangeEntry{{StartVendorID: 700, EndVendorID: 800}},
+	})
+	var update = mergeConsent(t1, map[int]bool{}, &iabconsent.OOBVendorList{
+		SegmentType: iabconsent.DisclosedVendors,
+		MaxVendorID: 801,
+		Vendors:     map[int]bool{801: true},
+	})
+
+	var merged, err = iabconsent.MergeV2(existing, update)
+	c.Assert(err, check.IsNil)
+	c.Check(merged.OOBDisclosedVendors, check.DeepEquals, &iabconsent.OOBVendorList{
+		SegmentType:     iabconsent.DisclosedVendors,
+		MaxVendorID:     801,
+		IsRangeEncoding: true,
+		NumEntries:      1,
+		VendorEntries:   []*iabconsent.RangeEntry{{StartVendorID: 700, EndVendorID: 801}},
+	})
+}
+
+func (s *MergeSuite) TestMergeV2Errors(c *check.C) {
+	var t1 = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var global = mergeConsent(t1, map[int]bool{1: true}, nil)
+	var specific = mergeConsent(t1, map[int]bool{1: true}, nil)
+	specific.IsServiceSpecific = true
+	var older = mergeConsent(t1.Add(-time.Hour), map[int]bool{1: true}, nil)
+
+	var tcs = []struct {
+		desc             string
+		existing, update *iabconsent.V2ParsedConsent
+		err              string
+	}{
+		{
+			desc:   "Nil existing.",
+			update: global,
+			err:    "merge v2: nil consent",
+		},
+		{
+			desc:     "Service specific update.",
+			existing: global,
+			update:   specific,
+			err:      "merge v2: only globally-scoped strings can be merged",
+		},
+		{
+			desc:     "Update older than existing.",
+			existing: global,
+			update:   older,
+			err:      "merge v2: update was last updated before the existing string",
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		var _, err = iabconsent.MergeV2(tc.existing, tc.update)
+		c.Check(err, check.ErrorMatches, tc.err)
+	}
+}
+
+func (s *MergeSuite) TestMergeV2Strings(c *check.C) {
+	var t1 = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	var t2 = t1.Add(24 * time.Hour)
+
+	var existing, err = iabconsent.EncodeV2(mergeConsent(t1, map[int]bool{1: true}, &iabconsent.OOBVendorList{
+		SegmentType: iabconsent.DisclosedVendors,
+		MaxVendorID: 1,
+		Vendors:     map[int]bool{1: true},
+	}))
+	c.Assert(err, check.IsNil)
+	var update string
+	update, err = iabconsent.EncodeV2(mergeConsent(t2, map[int]bool{2: true}, &iabconsent.OOBVendorList{
+		SegmentType: iabconsent.DisclosedVendors,
+		MaxVendorID: 2,
+		Vendors:     map[int]bool{2: true},
+	}))
+	c.Assert(err, check.IsNil)
+
+	var merged string
+	merged, err = iabconsent.MergeV2Strings(existing, update)
+	c.Assert(err, check.IsNil)
+
+	var p *iabconsent.V2ParsedConsent
+	p, err = iabconsent.ParseV2(merged)
+	c.Assert(err, check.IsNil)
+	c.Check(p.Created, check.Equals, t1)
+	c.Check(p.LastUpdated, check.Equals, t2)
+	c.Check(p.VendorAllowed(1), check.Equals, false)
+	c.Check(p.VendorAllowed(2), check.Equals, true)
+	c.Check(p.VendorDisclosed(1), check.Equals, true)
+	c.Check(p.VendorDisclosed(2), check.Equals, true)
+
+	_, err = iabconsent.MergeV2Strings("invalid", update)
+	c.Check(err, check.ErrorMatches, "parse existing consent: .*")
+	_, err = iabconsent.MergeV2Strings(existing, "invalid")
+	c.Check(err, check.ErrorMatches, "parse update consent: .*")
+}
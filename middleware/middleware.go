@@ -0,0 +1,191 @@
+// Package middleware provides net/http middleware which extracts and parses the privacy signals of a
+// request once, and stores them in the request's context.
+//
+// Signals are read from query parameters first, then from cookies:
+//
+//	gdpr, gdpr_consent / euconsent-v2    TCF v2
+//	gpp, gpp_sid / __gpp                 GPP
+//	us_privacy / usprivacy               US Privacy
+//	Sec-GPC header                       Global Privacy Control
+//
+// The names of the parameters and cookies can be changed with Config.
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+// Config holds the names of the query parameters and cookies that signals are read from.
+// A signal is not read from a source whose name is empty.
+type Config struct {
+	GDPRParam       string
+	ConsentParam    string
+	GPPParam        string
+	GPPSIDParam     string
+	USPrivacyParam  string
+	ConsentCookie   string
+	GPPCookie       string
+	USPrivacyCookie string
+}
+
+// DefaultConfig holds the parameter and cookie names used by the IAB specs and most CMPs.
+var DefaultConfig = Config{
+	GDPRParam:       "gdpr",
+	ConsentParam:    "gdpr_consent",
+	GPPParam:        "gpp",
+	GPPSIDParam:     "gpp_sid",
+	USPrivacyParam:  "us_privacy",
+	ConsentCookie:   "euconsent-v2",
+	GPPCookie:       "__gpp",
+	USPrivacyCookie: "usprivacy",
+}
+
+// PrivacySignals holds the privacy signals of a request. Each signal holds the raw string as received,
+// and, if it was present, its parsed value or the error from parsing it. A signal that was not sent
+// has an empty string, and a nil value and error.
+type PrivacySignals struct {
+	// GDPRApplies is the value of the gdpr parameter, or nil if it was not sent.
+	GDPRApplies *bool
+	GDPRErr     error
+
+	TCString string
+	TCF      *iabconsent.V2ParsedConsent
+	TCFErr   error
+
+	GPPString string
+	GPP       *iabconsent.GppConsent
+	GPPErr    error
+
+	// GPPSIDs are the Section IDs of the gpp_sid parameter, which apply to the request.
+	GPPSIDs   []int
+	GPPSIDErr error
+
+	USPrivacyString string
+	USPrivacy       *iabconsent.UsPrivacyParsedConsent
+	USPrivacyErr    error
+
+	// GPC is true if the request was sent with the Sec-GPC: 1 header.
+	GPC bool
+}
+
+// Err returns the first error from reading a signal, or nil if every signal that was sent is valid.
+func (s *PrivacySignals) Err() error {
+	for _, e := range []struct {
+		name string
+		err  error
+	}{
+		{"gdpr", s.GDPRErr},
+		{"tcf", s.TCFErr},
+		{"gpp", s.GPPErr},
+		{"gpp_sid", s.GPPSIDErr},
+		{"us_privacy", s.USPrivacyErr},
+	} {
+		if e.err != nil {
+			return errors.Wrap(e.err, "parse "+e.name)
+		}
+	}
+	return nil
+}
+
+// Extract reads and parses the privacy signals of r, using the names in c.
+func Extract(r *http.Request, c Config) *PrivacySignals {
+	var s = &PrivacySignals{}
+	var query = r.URL.Query()
+
+	if v := value(r, query, c.GDPRParam, ""); v != "" {
+		s.GDPRApplies, s.GDPRErr = parseGDPR(v)
+	}
+
+	if s.TCString = value(r, query, c.ConsentParam, c.ConsentCookie); s.TCString != "" {
+		s.TCF, s.TCFErr = iabconsent.ParseV2(s.TCString)
+	}
+
+	if s.GPPString = value(r, query, c.GPPParam, c.GPPCookie); s.GPPString != "" {
+		s.GPP, s.GPPErr = iabconsent.ParseGppConsent(s.GPPString)
+	}
+
+	if v := value(r, query, c.GPPSIDParam, ""); v != "" {
+		s.GPPSIDs, s.GPPSIDErr = ParseGPPSID(v)
+	}
+
+	if s.USPrivacyString = value(r, query, c.USPrivacyParam, c.USPrivacyCookie); s.USPrivacyString != "" {
+		s.USPrivacy, s.USPrivacyErr = iabconsent.ParseUsPrivacy(s.USPrivacyString)
+	}
+
+	s.GPC = strings.TrimSpace(r.Header.Get("Sec-GPC")) == "1"
+	return s
+}
+
+// value returns the query parameter param of r if it is set, and otherwise the value of cookie.
+func value(r *http.Request, query url.Values, param, cookie string) string {
+	if param != "" {
+		if v := strings.TrimSpace(query.Get(param)); v != "" {
+			return v
+		}
+	}
+	if cookie != "" {
+		if ck, err := r.Cookie(cookie); err == nil {
+			return strings.TrimSpace(ck.Value)
+		}
+	}
+	return ""
+}
+
+// parseGDPR parses the gdpr parameter, which is 1 if GDPR applies and 0 if not.
+func parseGDPR(v string) (*bool, error) {
+	var applies bool
+	switch v {
+	case "1":
+		applies = true
+	case "0":
+		applies = false
+	default:
+		return nil, errors.Errorf("invalid gdpr value %q", v)
+	}
+	return &applies, nil
+}
+
+// ParseGPPSID parses a comma separated list of GPP Section IDs, such as the gpp_sid parameter.
+func ParseGPPSID(v string) ([]int, error) {
+	var parts = strings.Split(v, ",")
+	var sids = make([]int, 0, len(parts))
+	for _, p := range parts {
+		var sid, err = strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || sid < 0 {
+			return nil, errors.Errorf("invalid gpp section id %q", p)
+		}
+		sids = append(sids, sid)
+	}
+	return sids, nil
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx holding s.
+func NewContext(ctx context.Context, s *PrivacySignals) context.Context {
+	return context.WithValue(ctx, contextKey{}, s)
+}
+
+// FromContext returns the PrivacySignals held by ctx, if any.
+func FromContext(ctx context.Context) (*PrivacySignals, bool) {
+	var s, ok = ctx.Value(contextKey{}).(*PrivacySignals)
+	return s, ok
+}
+
+// New returns middleware which extracts the PrivacySignals of each request using the names in c,
+// and stores them in the request's context for handlers to retrieve with FromContext.
+func New(c Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), Extract(r, c))))
+		})
+	}
+}
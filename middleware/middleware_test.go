@@ -0,0 +1,113 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+	"github.com/LiveRamp/iabconsent/middleware"
+)
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+type MiddlewareSuite struct{}
+
+var _ = check.Suite(&MiddlewareSuite{})
+
+const (
+	tcString = "COvzTO5OvzTO5BRAAAENAPCoALIAADgAAAAAAewAwABAAlAB6ABBFAAA"
+	gpp      = "DBABLA~BVVqAAEABCA.YA"
+)
+
+func (s *MiddlewareSuite) TestExtractQuery(c *check.C) {
+	var r = httptest.NewRequest("GET", "/pixel?gdpr=1&gdpr_consent="+tcString+"&gpp="+gpp+"&gpp_sid=7,2&us_privacy=1YNN", nil)
+	r.Header.Set("Sec-GPC", "1")
+
+	var ps = middleware.Extract(r, middleware.DefaultConfig)
+	c.Check(ps.Err(), check.IsNil)
+	c.Assert(ps.GDPRApplies, check.NotNil)
+	c.Check(*ps.GDPRApplies, check.Equals, true)
+	c.Check(ps.TCString, check.Equals, tcString)
+	c.Assert(ps.TCF, check.NotNil)
+	c.Check(ps.TCF.CMPID, check.Equals, 81)
+	c.Check(ps.GPPString, check.Equals, gpp)
+	c.Assert(ps.GPP.UsNational(), check.NotNil)
+	c.Check(ps.GPP.UsNational().Gpc, check.Equals, true)
+	c.Check(ps.GPPSIDs, check.DeepEquals, []int{7, 2})
+	c.Check(ps.USPrivacy, check.DeepEquals, &iabconsent.UsPrivacyParsedConsent{
+		Version: 1, Notice: iabconsent.MspaYes, OptOutSale: iabconsent.MspaNo, LspaCovered: iabconsent.MspaNo})
+	c.Check(ps.GPC, check.Equals, true)
+}
+
+func (s *MiddlewareSuite) TestExtractCookies(c *check.C) {
+	var r = httptest.NewRequest("GET", "/pixel?us_privacy=1YYN", nil)
+	r.AddCookie(&http.Cookie{Name: "euconsent-v2", Value: tcString})
+	r.AddCookie(&http.Cookie{Name: "__gpp", Value: gpp})
+	r.AddCookie(&http.Cookie{Name: "usprivacy", Value: "1NNN"})
+
+	var ps = middleware.Extract(r, middleware.DefaultConfig)
+	c.Check(ps.Err(), check.IsNil)
+	c.Check(ps.GDPRApplies, check.IsNil)
+	c.Check(ps.TCString, check.Equals, tcString)
+	c.Check(ps.GPPString, check.Equals, gpp)
+	c.Check(ps.GPPSIDs, check.IsNil)
+	// Query parameters take precedence over cookies.
+	c.Check(ps.USPrivacyString, check.Equals, "1YYN")
+	c.Check(ps.GPC, check.Equals, false)
+}
+
+func (s *MiddlewareSuite) TestExtractConfig(c *check.C) {
+	var r = httptest.NewRequest("GET", "/pixel?consent="+tcString+"&gdpr_consent=BONMj34ONMj34ABACDENALqAAAAAplY", nil)
+	r.AddCookie(&http.Cookie{Name: "usprivacy", Value: "1NNN"})
+	r.AddCookie(&http.Cookie{Name: "my_usp", Value: "1YNN"})
+
+	var ps = middleware.Extract(r, middleware.Config{ConsentParam: "consent", USPrivacyCookie: "my_usp"})
+	c.Check(ps.Err(), check.IsNil)
+	c.Check(ps.TCString, check.Equals, tcString)
+	c.Check(ps.USPrivacyString, check.Equals, "1YNN")
+}
+
+func (s *MiddlewareSuite) TestExtractErrors(c *check.C) {
+	var r = httptest.NewRequest("GET", "/pixel?gdpr=yes&gdpr_consent=BONMj34ONMj34ABACDENALqAAAAAplY&gpp=DBABL&gpp_sid=7,x&us_privacy=1YNX", nil)
+
+	var ps = middleware.Extract(r, middleware.DefaultConfig)
+	c.Check(ps.GDPRApplies, check.IsNil)
+	c.Check(ps.GDPRErr, check.ErrorMatches, `invalid gdpr value "yes"`)
+	c.Check(ps.TCString, check.Equals, "BONMj34ONMj34ABACDENALqAAAAAplY")
+	c.Check(ps.TCF, check.IsNil)
+	c.Check(ps.TCFErr, check.ErrorMatches, "non-v2 string passed to v2 parse method")
+	c.Check(ps.GPPErr, check.ErrorMatches, "not enough gpp segments")
+	c.Check(ps.GPPSIDErr, check.ErrorMatches, `invalid gpp section id "x"`)
+	c.Check(ps.USPrivacyErr, check.ErrorMatches, "parse lspa covered: .*")
+	c.Check(ps.Err(), check.ErrorMatches, `parse gdpr: invalid gdpr value "yes"`)
+}
+
+func (s *MiddlewareSuite) TestMiddleware(c *check.C) {
+	var got *middleware.PrivacySignals
+	var h = middleware.New(middleware.DefaultConfig)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ok bool
+		got, ok = middleware.FromContext(r.Context())
+		c.Check(ok, check.Equals, true)
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/pixel?us_privacy=1YNN", nil))
+	c.Assert(got, check.NotNil)
+	c.Check(got.USPrivacyString, check.Equals, "1YNN")
+
+	var _, ok = middleware.FromContext(httptest.NewRequest("GET", "/", nil).Context())
+	c.Check(ok, check.Equals, false)
+}
+
+func (s *MiddlewareSuite) TestParseGPPSID(c *check.C) {
+	var sids, err = middleware.ParseGPPSID("2, 7,8")
+	c.Check(err, check.IsNil)
+	c.Check(sids, check.DeepEquals, []int{2, 7, 8})
+
+	_, err = middleware.ParseGPPSID("2,,7")
+	c.Check(err, check.ErrorMatches, `invalid gpp section id ""`)
+	_, err = middleware.ParseGPPSID("-1")
+	c.Check(err, check.ErrorMatches, `invalid gpp section id "-1"`)
+}
@@ -0,0 +1,72 @@
+package iabconsent
+
+import (
+	"github.com/pkg/errors"
+)
+
+// MinimizeV2 returns a copy of p which only holds the signals of vendors, for forwarding consent to
+// those vendors alone:
+//
+//   - the consent and legitimate interest of every other vendor is cleared,
+//   - publisher restrictions only list vendors, and restrictions left without vendors are dropped,
+//   - the DisclosedVendors and AllowedVendors segments, if present, only list vendors,
+//   - the Publisher TC segment is dropped, as it holds the publisher's own purposes, which vendors
+//     do not act on.
+//
+// The core fields which apply to every vendor, such as the purposes, special features, PublisherCC and
+// timestamps, are kept as they are, so VendorAllowed, VendorLegitimateInterest, PublisherRestricted and
+// VendorDisclosed return the same for each of vendors. The result is in the smallest encoding, as
+// returned by CanonicalV2. MinimizeV2 returns nil if p is nil.
+func MinimizeV2(p *V2ParsedConsent, vendors []int) *V2ParsedConsent {
+	if p == nil {
+		return nil
+	}
+	var keep = make([]*RangeEntry, 0, len(vendors))
+	for _, v := range vendors {
+		keep = append(keep, &RangeEntry{StartVendorID: v, EndVendorID: v})
+	}
+	keep = mergeRanges(keep)
+
+	var m = *p
+	m.IsConsentRangeEncoding, m.ConsentedVendors = true, nil
+	m.ConsentedVendorsRange = intersectRanges(vendorRanges(p.IsConsentRangeEncoding, p.ConsentedVendors, p.ConsentedVendorsRange), keep)
+	m.IsInterestsRangeEncoding, m.InterestsVendors = true, nil
+	m.InterestsVendorsRange = intersectRanges(vendorRanges(p.IsInterestsRangeEncoding, p.InterestsVendors, p.InterestsVendorsRange), keep)
+
+	var restrictions []*PubRestrictionEntry
+	for _, pr := range p.PubRestrictionEntries {
+		if pr == nil {
+			continue
+		}
+		var ranges = intersectRanges(mergeRanges(pr.RestrictionsRange), keep)
+		if len(ranges) == 0 {
+			continue
+		}
+		restrictions = append(restrictions, &PubRestrictionEntry{
+			PurposeID:         pr.PurposeID,
+			RestrictionType:   pr.RestrictionType,
+			NumEntries:        len(ranges),
+			RestrictionsRange: ranges,
+		})
+	}
+	m.PubRestrictionEntries = restrictions
+
+	if p.OOBDisclosedVendors != nil {
+		m.OOBDisclosedVendors = newOOBVendorList(DisclosedVendors, intersectRanges(oobVendorRanges(p.OOBDisclosedVendors), keep))
+	}
+	if p.OOBAllowedVendors != nil {
+		m.OOBAllowedVendors = newOOBVendorList(AllowedVendors, intersectRanges(oobVendorRanges(p.OOBAllowedVendors), keep))
+	}
+	m.PublisherTCEntry = nil
+	return CanonicalV2(&m)
+}
+
+// MinimizeV2String parses TC String s, minimizes it to vendors with MinimizeV2, and returns the
+// minimized TC String.
+func MinimizeV2String(s string, vendors []int) (string, error) {
+	var p, err = ParseV2(s)
+	if err != nil {
+		return "", errors.Wrap(err, "parse consent")
+	}
+	return EncodeV2(MinimizeV2(p, vendors))
+}
@@ -0,0 +1,161 @@
+package iabconsent_test
+
+import (
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type MinimizeSuite struct{}
+
+var _ = check.Suite(&MinimizeSuite{})
+
+// minimizeConsent returns a globally-scoped TCF v2.3 consent with vendors, restrictions and every
+// optional segment set.
+func minimizeConsent() *iabconsent.V2ParsedConsent {
+	var p = v2BaseConsent()
+	p.TCFPolicyVersion = 5
+	p.PurposesConsent = map[int]bool{1: true, 2: true, 7: true}
+	p.PurposesLITransparency = map[int]bool{2: true}
+	p.MaxConsentVendorID = 700
+	p.ConsentedVendors = map[int]bool{1: true, 2: true, 10: true, 700: true}
+	p.MaxInterestsVendorID = 10
+	p.InterestsVendors = map[int]bool{2: true, 10: true}
+	p.NumPubRestrictions = 2
+	p.PubRestrictionEntries = []*iabconsent.PubRestrictionEntry{
+		{
+			PurposeID:         2,
+			RestrictionType:   iabconsent.PurposeFlatlyNotAllowed,
+			NumEntries:        1,
+			RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 1, EndVendorID: 5}},
+		},
+		{
+			PurposeID:         7,
+			RestrictionType:   iabconsent.RequireConsent,
+			NumEntries:        1,
+			RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 700, EndVendorID: 700}},
+		},
+	}
+	p.OOBDisclosedVendors = &iabconsent.OOBVendorList{
+		SegmentType:     iabconsent.DisclosedVendors,
+		MaxVendorID:     800,
+		IsRangeEncoding: true,
+		NumEntries:      1,
+		VendorEntries:   []*iabconsent.RangeEntry{{StartVendorID: 1, EndVendorID: 800}},
+	}
+	p.OOBAllowedVendors = &iabconsent.OOBVendorList{
+		SegmentType: iabconsent.AllowedVendors,
+		MaxVendorID: 2,
+		Vendors:     map[int]bool{1: true, 2: true},
+	}
+	p.PublisherTCEntry = &iabconsent.PublisherTCEntry{
+		SegmentType:        iabconsent.PublisherTC,
+		PubPurposesConsent: map[int]bool{1: true},
+	}
+	return p
+}
+
+func (s *MinimizeSuite) TestMinimizeV2(c *check.C) {
+	var p = minimizeConsent()
+	var m = iabconsent.MinimizeV2(p, []int{3, 2, 3})
+
+	// The signals of the kept vendors are unchanged.
+	for _, v := range []int{2, 3} {
+		c.Check(m.VendorAllowed(v), check.Equals, p.VendorAllowed(v))
+		c.Check(m.VendorLegitimateInterest(v), check.Equals, p.VendorLegitimateInterest(v))
+		c.Check(m.VendorDisclosed(v), check.Equals, p.VendorDisclosed(v))
+		c.Check(m.PublisherRestricted([]int{2, 7}, v), check.Equals, p.PublisherRestricted([]int{2, 7}, v))
+	}
+	c.Check(m.PurposesConsent, check.DeepEquals, p.PurposesConsent)
+	c.Check(m.Created, check.Equals, p.Created)
+
+	// Every other vendor is removed.
+	c.Check(m.ConsentedVendors, check.DeepEquals, map[int]bool{2: true})
+	c.Check(m.InterestsVendors, check.DeepEquals, map[int]bool{2: true})
+	c.Check(m.PubRestrictionEntries, check.DeepEquals, []*iabconsent.PubRestrictionEntry{
+		{
+			PurposeID:         2,
+			RestrictionType:   iabconsent.PurposeFlatlyNotAllowed,
+			NumEntries:        1,
+			RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 2, EndVendorID: 3}},
+		},
+	})
+	c.Check(m.OOBDisclosedVendors, check.DeepEquals, &iabconsent.OOBVendorList{
+		SegmentType: iabconsent.DisclosedVendors,
+		MaxVendorID: 3,
+		Vendors:     map[int]bool{2: true, 3: true},
+	})
+	c.Check(m.OOBAllowedVendors, check.DeepEquals, &iabconsent.OOBVendorList{
+		SegmentType: iabconsent.AllowedVendors,
+		MaxVendorID: 2,
+		Vendors:     map[int]bool{2: true},
+	})
+	c.Check(m.PublisherTCEntry, check.IsNil)
+
+	// p is not modified.
+	c.Check(p.ConsentedVendors, check.HasLen, 4)
+	c.Check(p.PublisherTCEntry, check.NotNil)
+}
+
+func (s *MinimizeSuite) TestMinimizeV2Ranges(c *check.C) {
+	var p = minimizeConsent()
+	p.IsConsentRangeEncoding = true
+	p.MaxConsentVendorID = 65535
+	p.ConsentedVendors = nil
+	p.ConsentedVendorsRange = []*iabconsent.RangeEntry{
+		{StartVendorID: 1, EndVendorID: 65535},
+		nil,
+		{StartVendorID: 1, EndVendorID: 65535},
+	}
+	p.PubRestrictionEntries = append(p.PubRestrictionEntries, nil, &iabconsent.PubRestrictionEntry{
+		PurposeID:         3,
+		RestrictionType:   iabconsent.RequireLegitimateInterest,
+		RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 100, EndVendorID: 65535}, nil},
+	})
+
+	var m = iabconsent.MinimizeV2(p, []int{65535, 3})
+	c.Check(m.ConsentedVendorsRange, check.DeepEquals, []*iabconsent.RangeEntry{
+		{StartVendorID: 3, EndVendorID: 3},
+		{StartVendorID: 65535, EndVendorID: 65535},
+	})
+	c.Check(m.VendorAllowed(3), check.Equals, p.VendorAllowed(3))
+	c.Check(m.PubRestrictionEntries, check.DeepEquals, []*iabconsent.PubRestrictionEntry{
+		{
+			PurposeID:         2,
+			RestrictionType:   iabconsent.PurposeFlatlyNotAllowed,
+			NumEntries:        1,
+			RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 3, EndVendorID: 3}},
+		},
+		{
+			PurposeID:         3,
+			RestrictionType:   iabconsent.RequireLegitimateInterest,
+			NumEntries:        1,
+			RestrictionsRange: []*iabconsent.RangeEntry{{StartVendorID: 65535, EndVendorID: 65535}},
+		},
+	})
+
+	c.Check(iabconsent.MinimizeV2(nil, []int{1}), check.IsNil)
+}
+
+func (s *MinimizeSuite) TestMinimizeV2String(c *check.C) {
+	var full, err = iabconsent.EncodeV2(minimizeConsent())
+	c.Assert(err, check.IsNil)
+
+	var minimized string
+	minimized, err = iabconsent.MinimizeV2String(full, []int{700})
+	c.Assert(err, check.IsNil)
+	c.Check(len(minimized) < len(full), check.Equals, true)
+
+	// The minimized string is a valid TCF v2.3 string, holding vendor 700's signals.
+	var p *iabconsent.V2ParsedConsent
+	p, err = iabconsent.ParseV2(minimized)
+	c.Assert(err, check.IsNil)
+	c.Check(p.VendorAllowed(700), check.Equals, true)
+	c.Check(p.VendorAllowed(1), check.Equals, false)
+	c.Check(p.SuitableToProcess([]int{7}, 700), check.Equals, true)
+	c.Check(p.PubRestrictionEntries, check.HasLen, 1)
+	c.Check(iabconsent.LintV2(p), check.HasLen, 0)
+
+	_, err = iabconsent.MinimizeV2String("invalid", []int{1})
+	c.Check(err, check.ErrorMatches, "parse consent: .*")
+}
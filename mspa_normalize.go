@@ -0,0 +1,349 @@
+package iabconsent
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// Categories of the usnat v2 SensitiveDataProcessing bitfield, zero-based.
+const (
+	usnatRacialOrEthnicOrigin = iota
+	usnatReligiousOrPhilosophicalBeliefs
+	usnatHealth
+	usnatSexLifeOrSexualOrientation
+	usnatCitizenshipOrImmigrationStatus
+	usnatGeneticData
+	usnatBiometricData
+	usnatPreciseGeolocation
+	usnatIdentificationDocuments
+	usnatFinancialAccount
+	usnatUnionMembership
+	usnatCommunicationContents
+	usnatTransgenderOrNonbinaryStatus
+	usnatNationalOrigin
+	usnatCrimeVictimStatus
+	usnatConsumerHealthData
+	usnatSensitiveDataCategories
+)
+
+// Categories of the usnat v2 KnownChildSensitiveDataConsents bitfield, zero-based.
+const (
+	usnatChild13To16 = iota
+	usnatChildUnder13
+	usnatChild16To17
+	usnatKnownChildCategories
+)
+
+// mspaCategoryMapping maps each sensitive data and known child category of a section, by
+// position, to the usnat v2 categories it corresponds to. A category may map to several
+// usnat categories, and a nil entry has no usnat equivalent.
+type mspaCategoryMapping struct {
+	sensitiveData [][]int
+	knownChild    [][]int
+}
+
+// virginiaSensitiveData is the sensitive data layout shared by most state sections.
+var virginiaSensitiveData = [][]int{
+	{usnatRacialOrEthnicOrigin},
+	{usnatReligiousOrPhilosophicalBeliefs},
+	{usnatHealth},
+	{usnatSexLifeOrSexualOrientation},
+	{usnatCitizenshipOrImmigrationStatus},
+	{usnatGeneticData},
+	{usnatBiometricData},
+	{usnatPreciseGeolocation},
+}
+
+// mspaCategoryMappings holds the category mapping of every supported section version, keyed
+// by Section ID and then Version, following the IAB guidance for mapping state sections to
+// usnat:
+// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States
+var mspaCategoryMappings = map[int]map[int]mspaCategoryMapping{
+	UsNationalSID: {
+		1: {
+			sensitiveData: identityCategories(12),
+			knownChild:    identityCategories(2),
+		},
+		2: {
+			sensitiveData: identityCategories(usnatSensitiveDataCategories),
+			knownChild:    identityCategories(usnatKnownChildCategories),
+		},
+	},
+	UsCaliforniaSID: {
+		1: {
+			sensitiveData: [][]int{
+				{usnatIdentificationDocuments},
+				{usnatFinancialAccount},
+				{usnatPreciseGeolocation},
+				{usnatRacialOrEthnicOrigin, usnatReligiousOrPhilosophicalBeliefs, usnatUnionMembership},
+				{usnatCommunicationContents},
+				{usnatGeneticData},
+				{usnatBiometricData},
+				{usnatHealth},
+				{usnatSexLifeOrSexualOrientation},
+			},
+			knownChild: [][]int{{usnatChild13To16}, {usnatChildUnder13}},
+		},
+	},
+	UsVirginiaSID: {
+		1: {sensitiveData: virginiaSensitiveData, knownChild: [][]int{{usnatChildUnder13}}},
+	},
+	UsColoradoSID: {
+		1: {sensitiveData: virginiaSensitiveData[:7], knownChild: [][]int{{usnatChildUnder13}}},
+	},
+	UsUtahSID: {
+		1: {
+			sensitiveData: [][]int{
+				{usnatRacialOrEthnicOrigin},
+				{usnatReligiousOrPhilosophicalBeliefs},
+				{usnatSexLifeOrSexualOrientation},
+				{usnatCitizenshipOrImmigrationStatus},
+				{usnatHealth},
+				{usnatGeneticData},
+				{usnatBiometricData},
+				{usnatPreciseGeolocation},
+			},
+			knownChild: [][]int{{usnatChildUnder13}},
+		},
+	},
+	UsConnecticutSID: {
+		1: {
+			sensitiveData: virginiaSensitiveData,
+			knownChild:    [][]int{{usnatChild13To16}, {usnatChildUnder13}, {usnatChild16To17}},
+		},
+	},
+	UsFloridaSID: {
+		1: {
+			sensitiveData: virginiaSensitiveData,
+			knownChild:    [][]int{{usnatChild13To16}, {usnatChildUnder13}, {usnatChild16To17}},
+		},
+	},
+	UsMontanaSID: {
+		1: {
+			sensitiveData: virginiaSensitiveData,
+			knownChild:    [][]int{{usnatChild13To16}, {usnatChildUnder13}, {usnatChild16To17}},
+		},
+	},
+	UsOregonSID: {
+		1: {
+			sensitiveData: [][]int{
+				{usnatRacialOrEthnicOrigin},
+				{usnatReligiousOrPhilosophicalBeliefs},
+				{usnatHealth},
+				{usnatSexLifeOrSexualOrientation},
+				{usnatTransgenderOrNonbinaryStatus},
+				{usnatCrimeVictimStatus},
+				{usnatCitizenshipOrImmigrationStatus},
+				{usnatGeneticData},
+				{usnatBiometricData},
+				{usnatPreciseGeolocation},
+				{usnatNationalOrigin},
+			},
+			knownChild: [][]int{{usnatChild13To16}, {usnatChildUnder13}, {usnatChild16To17}},
+		},
+	},
+	UsTexasSID: {
+		1: {sensitiveData: virginiaSensitiveData, knownChild: [][]int{{usnatChildUnder13}}},
+	},
+	UsDelawareSID: {
+		1: {
+			sensitiveData: [][]int{
+				{usnatRacialOrEthnicOrigin},
+				{usnatReligiousOrPhilosophicalBeliefs},
+				{usnatHealth},
+				{usnatSexLifeOrSexualOrientation},
+				{usnatTransgenderOrNonbinaryStatus},
+				{usnatCitizenshipOrImmigrationStatus, usnatNationalOrigin},
+				{usnatGeneticData},
+				{usnatBiometricData},
+				{usnatPreciseGeolocation},
+			},
+			knownChild: [][]int{{usnatChildUnder13}, {usnatChild13To16}, {usnatChild13To16}, {usnatChild16To17}, {usnatChild16To17}},
+		},
+	},
+	UsIowaSID: {
+		1: {sensitiveData: virginiaSensitiveData, knownChild: [][]int{{usnatChildUnder13}}},
+	},
+	UsNebraskaSID: {
+		1: {sensitiveData: virginiaSensitiveData, knownChild: [][]int{{usnatChildUnder13}}},
+	},
+	UsNewHampshireSID: {
+		1: {
+			sensitiveData: virginiaSensitiveData,
+			knownChild:    [][]int{{usnatChild13To16}, {usnatChildUnder13}, {usnatChild16To17}},
+		},
+	},
+	UsNewJerseySID: {
+		1: {
+			sensitiveData: append(virginiaSensitiveData[:8:8],
+				[]int{usnatFinancialAccount},
+				[]int{usnatTransgenderOrNonbinaryStatus}),
+			knownChild: [][]int{{usnatChildUnder13}, {usnatChild13To16}, {usnatChild13To16}, {usnatChild16To17}, {usnatChild16To17}},
+		},
+	},
+	UsTennesseeSID: {
+		1: {sensitiveData: virginiaSensitiveData, knownChild: [][]int{{usnatChildUnder13}}},
+	},
+}
+
+// identityCategories maps each of n categories to the usnat category at the same position.
+func identityCategories(n int) [][]int {
+	var m = make([][]int, n)
+	for i := range m {
+		m[i] = []int{i}
+	}
+	return m
+}
+
+// NormalizedMspaConsent is a projection of any MSPA section onto the fields of usnat v2, so that
+// consumers can evaluate every section the same way, regardless of its Section ID.
+type NormalizedMspaConsent struct {
+	// The Section ID of the section that was normalized.
+	SourceSID int `json:"source_sid"`
+	// The Version of the section that was normalized.
+	SourceVersion int `json:"source_version"`
+	// The values of the section, in the usnat v2 layout. Sensitive data opt-outs are converted to
+	// SensitiveDataProcessingConsents, where 1 (Opted Out) becomes 1 (No Consent), as the most
+	// restrictive value in both. The bitfields only hold the usnat categories that the section
+	// maps to; a missing key has no equivalent in the section.
+	Consent *MspaParsedConsent `json:"consent"`
+	// The usnat fields that have no equivalent in the section. Their value in Consent is the zero
+	// value, and must not be read as Not Applicable.
+	Unmapped map[MspaField]bool `json:"unmapped"`
+}
+
+// Mapped returns whether usnat field f has an equivalent in the normalized section.
+func (n *NormalizedMspaConsent) Mapped(f MspaField) bool {
+	return !n.Unmapped[f]
+}
+
+// normalizedScalarFields are the non-bitfield fields of usnat v2.
+var normalizedScalarFields = []MspaField{
+	MspaFieldSharingNotice,
+	MspaFieldSaleOptOutNotice,
+	MspaFieldSharingOptOutNotice,
+	MspaFieldTargetedAdvertisingOptOutNotice,
+	MspaFieldSensitiveDataProcessingOptOutNotice,
+	MspaFieldSensitiveDataLimitUseNotice,
+	MspaFieldSaleOptOut,
+	MspaFieldSharingOptOut,
+	MspaFieldTargetedAdvertisingOptOut,
+	MspaFieldPersonalDataConsents,
+	MspaFieldCoveredTransaction,
+	MspaFieldOptOutOptionMode,
+	MspaFieldServiceProviderMode,
+}
+
+// NormalizeMspa projects p, parsed from the MSPA section with Section ID sid, onto the usnat v2
+// layout. Fields and categories of the section are copied to their usnat equivalent, and usnat
+// fields without an equivalent are reported in Unmapped.
+//
+// Following the IAB guidance, California's opt-out of Sharing (cross-context behavioral
+// advertising) also populates the usnat Targeted Advertising fields.
+func NormalizeMspa(sid int, p *MspaParsedConsent) (*NormalizedMspaConsent, error) {
+	if p == nil {
+		return nil, errors.New("nil consent")
+	}
+	var format, ok = mspaSections[sid].versions[p.Version]
+	if !ok {
+		return nil, errors.New("unsupported section " + fmt.Sprint(sid) + " version " + fmt.Sprint(p.Version))
+	}
+	var categories = mspaCategoryMappings[sid][p.Version]
+
+	var n = &NormalizedMspaConsent{
+		SourceSID:     sid,
+		SourceVersion: p.Version,
+		Consent: &MspaParsedConsent{
+			SID:                             UsNationalSID,
+			Version:                         2,
+			SensitiveDataProcessingConsents: make(map[int]MspaConsent),
+			KnownChildSensitiveDataConsents: make(map[int]MspaConsent),
+			Gpc:                             p.Gpc,
+		},
+		Unmapped: make(map[MspaField]bool),
+	}
+
+	var present = make(map[MspaField]bool, len(format.fields))
+	for _, f := range format.fields {
+		present[f.field] = true
+	}
+	for _, f := range normalizedScalarFields {
+		if !present[f] {
+			n.Unmapped[f] = true
+			continue
+		}
+		copyMspaField(n.Consent, p, f)
+	}
+	if sid == UsCaliforniaSID {
+		n.Consent.TargetedAdvertisingOptOutNotice = p.SharingOptOutNotice
+		n.Consent.TargetedAdvertisingOptOut = p.SharingOptOut
+		delete(n.Unmapped, MspaFieldTargetedAdvertisingOptOutNotice)
+		delete(n.Unmapped, MspaFieldTargetedAdvertisingOptOut)
+	}
+
+	// Sections encode sensitive data as either consents or opt-outs, but never both.
+	for i, usnat := range categories.sensitiveData {
+		var v, found = p.SensitiveDataProcessingConsents[i]
+		if !found {
+			var o MspaOptout
+			if o, found = p.SensitiveDataProcessingOptOuts[i]; found {
+				v = MspaConsent(o)
+			}
+		}
+		if found {
+			mergeMspaConsent(n.Consent.SensitiveDataProcessingConsents, usnat, v)
+		}
+	}
+	for i, usnat := range categories.knownChild {
+		if v, found := p.KnownChildSensitiveDataConsents[i]; found {
+			mergeMspaConsent(n.Consent.KnownChildSensitiveDataConsents, usnat, v)
+		}
+	}
+	if len(n.Consent.SensitiveDataProcessingConsents) == 0 {
+		n.Unmapped[MspaFieldSensitiveDataProcessingConsents] = true
+	}
+	if len(n.Consent.KnownChildSensitiveDataConsents) == 0 {
+		n.Unmapped[MspaFieldKnownChildSensitiveDataConsents] = true
+	}
+	return n, nil
+}
+
+// copyMspaField copies the value of field f from src to dst.
+func copyMspaField(dst, src *MspaParsedConsent, f MspaField) {
+	switch d := dst.fieldPtr(f).(type) {
+	case *MspaNotice:
+		*d = *src.fieldPtr(f).(*MspaNotice)
+	case *MspaOptout:
+		*d = *src.fieldPtr(f).(*MspaOptout)
+	case *MspaConsent:
+		*d = *src.fieldPtr(f).(*MspaConsent)
+	case *MspaNaYesNo:
+		*d = *src.fieldPtr(f).(*MspaNaYesNo)
+	}
+}
+
+// mergeMspaConsent sets v for each of the usnat categories in m. When several categories of a
+// section map to the same usnat category, the most restrictive value is kept: No Consent, then
+// Consent, then Not Applicable.
+func mergeMspaConsent(m map[int]MspaConsent, categories []int, v MspaConsent) {
+	for _, c := range categories {
+		var existing, found = m[c]
+		if !found || mspaConsentRestrictiveness(v) > mspaConsentRestrictiveness(existing) {
+			m[c] = v
+		}
+	}
+}
+
+// mspaConsentRestrictiveness orders MspaConsent values from least to most restrictive.
+func mspaConsentRestrictiveness(v MspaConsent) int {
+	switch v {
+	case ConsentNotApplicable:
+		return 0
+	case Consent:
+		return 1
+	default:
+		// No Consent, and invalid values, are treated as the most restrictive.
+		return 2
+	}
+}
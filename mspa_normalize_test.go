@@ -0,0 +1,148 @@
+package iabconsent_test
+
+import (
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type MspaNormalizeSuite struct{}
+
+var _ = check.Suite(&MspaNormalizeSuite{})
+
+func (s *MspaNormalizeSuite) TestNormalizeMspa(c *check.C) {
+	var tcs = []struct {
+		desc     string
+		sid      int
+		consent  *iabconsent.MspaParsedConsent
+		expected *iabconsent.NormalizedMspaConsent
+	}{
+		{
+			desc:    "California opt-outs become usnat consents, Sharing populates Targeted Advertising.",
+			sid:     iabconsent.UsCaliforniaSID,
+			consent: mspaConsentFixtures[iabconsent.UsCaliforniaSID]["BVoYYZoI.YA"],
+			expected: &iabconsent.NormalizedMspaConsent{
+				SourceSID:     iabconsent.UsCaliforniaSID,
+				SourceVersion: 1,
+				Consent: &iabconsent.MspaParsedConsent{
+					SID:                             iabconsent.UsNationalSID,
+					Version:                         2,
+					SaleOptOutNotice:                iabconsent.NoticeProvided,
+					SharingOptOutNotice:             iabconsent.NoticeProvided,
+					TargetedAdvertisingOptOutNotice: iabconsent.NoticeProvided,
+					SensitiveDataLimitUseNotice:     iabconsent.NoticeProvided,
+					SaleOptOut:                      iabconsent.NotOptedOut,
+					SharingOptOut:                   iabconsent.NotOptedOut,
+					TargetedAdvertisingOptOut:       iabconsent.NotOptedOut,
+					SensitiveDataProcessingConsents: map[int]iabconsent.MspaConsent{
+						0:  iabconsent.ConsentNotApplicable,
+						1:  iabconsent.ConsentNotApplicable,
+						2:  iabconsent.NoConsent,
+						3:  iabconsent.Consent,
+						5:  iabconsent.Consent,
+						6:  iabconsent.ConsentNotApplicable,
+						7:  iabconsent.Consent,
+						8:  iabconsent.ConsentNotApplicable,
+						9:  iabconsent.NoConsent,
+						10: iabconsent.ConsentNotApplicable,
+						11: iabconsent.NoConsent,
+					},
+					KnownChildSensitiveDataConsents: map[int]iabconsent.MspaConsent{
+						0: iabconsent.NoConsent,
+						1: iabconsent.Consent,
+					},
+					PersonalDataConsents:    iabconsent.Consent,
+					MspaCoveredTransaction:  iabconsent.MspaNotApplicable,
+					MspaOptOutOptionMode:    iabconsent.MspaNotApplicable,
+					MspaServiceProviderMode: iabconsent.MspaNo,
+					Gpc:                     true,
+				},
+				Unmapped: map[iabconsent.MspaField]bool{
+					iabconsent.MspaFieldSharingNotice:                       true,
+					iabconsent.MspaFieldSensitiveDataProcessingOptOutNotice: true,
+				},
+			},
+		},
+		{
+			desc:    "Virginia has no Sharing or Personal Data fields.",
+			sid:     iabconsent.UsVirginiaSID,
+			consent: mspaConsentFixtures[iabconsent.UsVirginiaSID]["BVoYYYI"],
+			expected: &iabconsent.NormalizedMspaConsent{
+				SourceSID:     iabconsent.UsVirginiaSID,
+				SourceVersion: 1,
+				Consent: &iabconsent.MspaParsedConsent{
+					SID:                             iabconsent.UsNationalSID,
+					Version:                         2,
+					SharingNotice:                   iabconsent.NoticeProvided,
+					SaleOptOutNotice:                iabconsent.NoticeProvided,
+					TargetedAdvertisingOptOutNotice: iabconsent.NoticeProvided,
+					SaleOptOut:                      iabconsent.NotOptedOut,
+					TargetedAdvertisingOptOut:       iabconsent.NotOptedOut,
+					SensitiveDataProcessingConsents: map[int]iabconsent.MspaConsent{
+						0: iabconsent.ConsentNotApplicable,
+						1: iabconsent.NoConsent,
+						2: iabconsent.Consent,
+						3: iabconsent.ConsentNotApplicable,
+						4: iabconsent.NoConsent,
+						5: iabconsent.Consent,
+						6: iabconsent.ConsentNotApplicable,
+						7: iabconsent.NoConsent,
+					},
+					KnownChildSensitiveDataConsents: map[int]iabconsent.MspaConsent{
+						1: iabconsent.Consent,
+					},
+					MspaCoveredTransaction:  iabconsent.MspaNotApplicable,
+					MspaOptOutOptionMode:    iabconsent.MspaNotApplicable,
+					MspaServiceProviderMode: iabconsent.MspaNo,
+				},
+				Unmapped: map[iabconsent.MspaField]bool{
+					iabconsent.MspaFieldSharingOptOutNotice:                 true,
+					iabconsent.MspaFieldSensitiveDataProcessingOptOutNotice: true,
+					iabconsent.MspaFieldSensitiveDataLimitUseNotice:         true,
+					iabconsent.MspaFieldSharingOptOut:                       true,
+					iabconsent.MspaFieldPersonalDataConsents:                true,
+				},
+			},
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		var n, err = iabconsent.NormalizeMspa(tc.sid, tc.consent)
+		c.Check(err, check.IsNil)
+		c.Check(n, check.DeepEquals, tc.expected)
+	}
+}
+
+func (s *MspaNormalizeSuite) TestNormalizeMspaUsNational(c *check.C) {
+	var p = mspaConsentFixtures[iabconsent.UsNationalSID]["BVVqAAEABCA.QA"]
+	var n, err = iabconsent.NormalizeMspa(iabconsent.UsNationalSID, p)
+	c.Assert(err, check.IsNil)
+
+	c.Check(n.Unmapped, check.HasLen, 0)
+	c.Check(n.Mapped(iabconsent.MspaFieldSharingNotice), check.Equals, true)
+	c.Check(n.Consent.Version, check.Equals, 2)
+	c.Check(n.Consent.SensitiveDataProcessingConsents, check.DeepEquals, p.SensitiveDataProcessingConsents)
+	c.Check(n.Consent.KnownChildSensitiveDataConsents, check.DeepEquals, p.KnownChildSensitiveDataConsents)
+	c.Check(n.Consent.MspaServiceProviderMode, check.Equals, iabconsent.MspaNo)
+}
+
+func (s *MspaNormalizeSuite) TestNormalizeMspaTennessee(c *check.C) {
+	var n, err = iabconsent.NormalizeMspa(iabconsent.UsTennesseeSID,
+		mspaConsentFixtures[iabconsent.UsTennesseeSID]["Bqqqqqo"])
+	c.Assert(err, check.IsNil)
+
+	// Tennessee does not encode MspaCoveredTransaction, so its zero value is not Not Applicable.
+	c.Check(n.Mapped(iabconsent.MspaFieldCoveredTransaction), check.Equals, false)
+	c.Check(n.Mapped(iabconsent.MspaFieldOptOutOptionMode), check.Equals, true)
+}
+
+func (s *MspaNormalizeSuite) TestNormalizeMspaError(c *check.C) {
+	var _, err = iabconsent.NormalizeMspa(iabconsent.UsCaliforniaSID, &iabconsent.MspaParsedConsent{Version: 2})
+	c.Check(err, check.ErrorMatches, "unsupported section 8 version 2")
+
+	_, err = iabconsent.NormalizeMspa(2, &iabconsent.MspaParsedConsent{Version: 1})
+	c.Check(err, check.ErrorMatches, "unsupported section 2 version 1")
+
+	_, err = iabconsent.NormalizeMspa(iabconsent.UsCaliforniaSID, nil)
+	c.Check(err, check.ErrorMatches, "nil consent")
+}
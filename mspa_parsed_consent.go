@@ -1,67 +1,75 @@
 package iabconsent
 
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
 // MspaParsedConsent represents data extract from a Multi-State Privacy Agreement (mspa) consent string.
 // Format can be found here: https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/blob/main/Sections/US-National/IAB%20Privacy%E2%80%99s%20National%20Privacy%20Technical%20Specification.md#core-segment
 type MspaParsedConsent struct {
+	// The GPP Section ID of the section the consent was parsed from.
+	SID int `json:"sid"`
 	// The version of this section specification used to encode the string.
-	Version int
+	Version int `json:"version"`
 	// Notice of the Sharing of the Consumer’s Personal Data with Third Parties.
 	// 0 Not Applicable. The Business does not share Personal Data with Third Parties.
 	// 1 Yes, notice was provided
 	// 2 No, notice was not provided
 	// Note: ProcessingNotice and SharingNotice are the same
-	SharingNotice MspaNotice
+	SharingNotice MspaNotice `json:"sharing_notice"`
 	// Notice of the Opportunity to Opt Out of the Sale of the Consumer’s Personal Data.
 	// 0 Not Applicable. The Business does not Sell Personal Data.
 	// 1 Yes, notice was provided
 	// 2 No, notice was not provided
-	SaleOptOutNotice MspaNotice
+	SaleOptOutNotice MspaNotice `json:"sale_opt_out_notice"`
 	// Notice of the Opportunity to Opt Out of the Sharing of the Consumer’s Personal Data.
 	// 0 Not Applicable.The Business does not Share Personal Data.
 	// 1 Yes, notice was provided
 	// 2 No, notice was not provided
-	SharingOptOutNotice MspaNotice
+	SharingOptOutNotice MspaNotice `json:"sharing_opt_out_notice"`
 	// Notice of the Opportunity to Opt Out of Processing of the Consumer’s Personal Data for Targeted Advertising.
 	// 0 Not Applicable.The Business does not Process Personal Data for Targeted Advertising.
 	// 1 Yes, notice was provided
 	// 2 No, notice was not provided
-	TargetedAdvertisingOptOutNotice MspaNotice
+	TargetedAdvertisingOptOutNotice MspaNotice `json:"targeted_advertising_opt_out_notice"`
 	// Notice of the Opportunity to Opt Out of the Processing of the Consumer’s Sensitive Data.
 	// 0 Not Applicable. The Business does not Process Sensitive Data.
 	// 1 Yes, notice was provided
 	// 2 No, notice was not provided
 	// Note: SensitiveDataOptOutNotice and SensitiveDataProcessingOptOutNotice are the same
-	SensitiveDataProcessingOptOutNotice MspaNotice
+	SensitiveDataProcessingOptOutNotice MspaNotice `json:"sensitive_data_processing_opt_out_notice"`
 	// Notice of the Opportunity to Limit Use or Disclosure of the Consumer’s Sensitive Data.
 	// 0 Not Applicable. The Business does not use or disclose Sensitive Data.
 	// 1 Yes, notice was provided
 	// 2 No, notice was not provided
-	SensitiveDataLimitUseNotice MspaNotice
+	SensitiveDataLimitUseNotice MspaNotice `json:"sensitive_data_limit_use_notice"`
 	// Opt-Out of the Sale of the Consumer’s Personal Data.
 	// 0 Not Applicable. SaleOptOutNotice value was not applicable or no notice was provided
 	// 1 Opted Out
 	// 2 Did Not Opt Out
-	SaleOptOut MspaOptout
+	SaleOptOut MspaOptout `json:"sale_opt_out"`
 	// Opt-Out of the Sharing of the Consumer’s Personal Data.
 	// 0 Not Applicable. SharingOptOutNotice value was not applicable or no notice was provided.
 	// 1 Opted Out
 	// 2 Did Not Opt Out
-	SharingOptOut MspaOptout
+	SharingOptOut MspaOptout `json:"sharing_opt_out"`
 	// Opt-Out of Processing the Consumer’s Personal Data for Targeted Advertising.
 	// 0 Not Applicable. TargetedAdvertisingOptOutNotice value was not applicable or no notice was provided
 	// 1 Opted Out
 	// 2 Did Not Opt Out
-	TargetedAdvertisingOptOut MspaOptout
+	TargetedAdvertisingOptOut MspaOptout `json:"targeted_advertising_opt_out"`
 	// Two bits for each Data Activity:
 	// 0 Not Applicable. The Business does not Process the specific category of Sensitive Data.
 	// 1 No Consent
 	// 2 Consent
-	SensitiveDataProcessingConsents map[int]MspaConsent
+	SensitiveDataProcessingConsents map[int]MspaConsent `json:"sensitive_data_processing_consents"`
 	// Two bits for each Data Activity:
 	// 0 Not Applicable. SensitiveDataLimitUseNotice value was not applicable or no notice was provided.
 	// 1 Opted Out
 	// 2 Did Not Opt Out
-	SensitiveDataProcessingOptOuts map[int]MspaOptout
+	SensitiveDataProcessingOptOuts map[int]MspaOptout `json:"sensitive_data_processing_opt_outs"`
 	// Two bits for each Data Activity:
 	// 0 Not Applicable. The Business does not have actual knowledge that it Processes Personal Data or Sensitive Data of a Consumer who is a known child.
 	// 1 No Consent
@@ -69,30 +77,30 @@ type MspaParsedConsent struct {
 	// Fields:
 	// (1) Consent to Process the Consumer’s Personal Data or Sensitive Data for Consumers from Age 13 to 16.
 	// (2) Consent to Process the Consumer’s Personal Data or Sensitive Data for Consumers Younger Than 13 Years of Age.
-	KnownChildSensitiveDataConsents map[int]MspaConsent
+	KnownChildSensitiveDataConsents map[int]MspaConsent `json:"known_child_sensitive_data_consents"`
 	// Consent to Collection, Use, Retention, Sale, and/or Sharing of the Consumer’s Personal Data that Is Unrelated to or Incompatible with the Purpose(s) for which the Consumer’s Personal Data Was Collected or Processed.
 	// 0 Not Applicable. The Business does not use, retain, Sell, or Share the Consumer’s Personal Data for advertising purposes that are unrelated to or incompatible with the purpose(s) for which the Consumer’s Personal Data was collected or processed.
 	// 1 No Consent
 	// 2 Consent
 	// Note: AdditionalDataProcessingConsent and PersonalDataConsents are the same
-	PersonalDataConsents MspaConsent
+	PersonalDataConsents MspaConsent `json:"personal_data_consents"`
 	// Publisher or Advertiser, as applicable, is a signatory to the IAB Multistate Service Provider Agreement (MSPA), as may be amended from time to time, and declares that the transaction is a “Covered Transaction” as defined in the MSPA.
 	// 1 Yes
 	// 2 No
-	MspaCoveredTransaction MspaNaYesNo
+	MspaCoveredTransaction MspaNaYesNo `json:"covered_transaction"`
 	// Publisher or Advertiser, as applicable, has enabled “Opt-Out Option Mode” for the “Covered Transaction,” as such terms are defined in the MSPA.
 	// 0 Not Applicable.
 	// 1 Yes
 	// 2 No
-	MspaOptOutOptionMode MspaNaYesNo
+	MspaOptOutOptionMode MspaNaYesNo `json:"opt_out_option_mode"`
 	// Publisher or Advertiser, as applicable, has enabled “Service Provider Mode” for the “Covered Transaction,” as such terms are defined in the MSPA.
 	// 0 Not Applicable
 	// 1 Yes
 	// 2 No
-	MspaServiceProviderMode MspaNaYesNo
+	MspaServiceProviderMode MspaNaYesNo `json:"service_provider_mode"`
 	// Subsections added below:
 	// Global Privacy Control (GPC) is signaled and set.
-	Gpc bool
+	Gpc bool `json:"gpc"`
 }
 
 type MspaNotice int
@@ -133,6 +141,69 @@ const (
 	InvalidMspaValue
 )
 
+// MspaField identifies a single field of a MspaParsedConsent. Each MSPA section version
+// is described as the ordered list of MspaFields it encodes.
+type MspaField int
+
+const (
+	InvalidMspaField MspaField = iota
+	MspaFieldSharingNotice
+	MspaFieldSaleOptOutNotice
+	MspaFieldSharingOptOutNotice
+	MspaFieldTargetedAdvertisingOptOutNotice
+	MspaFieldSensitiveDataProcessingOptOutNotice
+	MspaFieldSensitiveDataLimitUseNotice
+	MspaFieldSaleOptOut
+	MspaFieldSharingOptOut
+	MspaFieldTargetedAdvertisingOptOut
+	MspaFieldSensitiveDataProcessingConsents
+	MspaFieldSensitiveDataProcessingOptOuts
+	MspaFieldKnownChildSensitiveDataConsents
+	MspaFieldPersonalDataConsents
+	MspaFieldCoveredTransaction
+	MspaFieldOptOutOptionMode
+	MspaFieldServiceProviderMode
+)
+
+// fieldPtr returns a pointer to the value of field f of p, or nil for an unknown field.
+func (p *MspaParsedConsent) fieldPtr(f MspaField) interface{} {
+	switch f {
+	case MspaFieldSharingNotice:
+		return &p.SharingNotice
+	case MspaFieldSaleOptOutNotice:
+		return &p.SaleOptOutNotice
+	case MspaFieldSharingOptOutNotice:
+		return &p.SharingOptOutNotice
+	case MspaFieldTargetedAdvertisingOptOutNotice:
+		return &p.TargetedAdvertisingOptOutNotice
+	case MspaFieldSensitiveDataProcessingOptOutNotice:
+		return &p.SensitiveDataProcessingOptOutNotice
+	case MspaFieldSensitiveDataLimitUseNotice:
+		return &p.SensitiveDataLimitUseNotice
+	case MspaFieldSaleOptOut:
+		return &p.SaleOptOut
+	case MspaFieldSharingOptOut:
+		return &p.SharingOptOut
+	case MspaFieldTargetedAdvertisingOptOut:
+		return &p.TargetedAdvertisingOptOut
+	case MspaFieldSensitiveDataProcessingConsents:
+		return &p.SensitiveDataProcessingConsents
+	case MspaFieldSensitiveDataProcessingOptOuts:
+		return &p.SensitiveDataProcessingOptOuts
+	case MspaFieldKnownChildSensitiveDataConsents:
+		return &p.KnownChildSensitiveDataConsents
+	case MspaFieldPersonalDataConsents:
+		return &p.PersonalDataConsents
+	case MspaFieldCoveredTransaction:
+		return &p.MspaCoveredTransaction
+	case MspaFieldOptOutOptionMode:
+		return &p.MspaOptOutOptionMode
+	case MspaFieldServiceProviderMode:
+		return &p.MspaServiceProviderMode
+	}
+	return nil
+}
+
 // ReadMspaNotice reads integers into standard MSPA Notice values of
 // 0: Not applicable, 1: Yes, notice was provided, 2: No, notice was not provided.
 func (r *ConsentReader) ReadMspaNotice() (MspaNotice, error) {
@@ -188,3 +259,47 @@ func (r *ConsentReader) ReadMspaNaYesNo() (MspaNaYesNo, error) {
 	var nyn, err = r.ReadInt(2)
 	return MspaNaYesNo(nyn), err
 }
+
+// ReadMspaField reads the next value of field f into p, using the reader matching the type of
+// the field. Bitfield fields read l values.
+func (r *ConsentReader) ReadMspaField(p *MspaParsedConsent, f MspaField, l uint) error {
+	var err error
+	switch v := p.fieldPtr(f).(type) {
+	case *MspaNotice:
+		*v, err = r.ReadMspaNotice()
+	case *MspaOptout:
+		*v, err = r.ReadMspaOptOut()
+	case *MspaConsent:
+		*v, err = r.ReadMspaConsent()
+	case *MspaNaYesNo:
+		*v, err = r.ReadMspaNaYesNo()
+	case *map[int]MspaConsent:
+		*v, err = r.ReadMspaBitfieldConsent(l)
+	case *map[int]MspaOptout:
+		*v, err = r.ReadMspaBitfieldOptOut(l)
+	default:
+		err = errors.New("unknown mspa field " + fmt.Sprint(int(f)))
+	}
+	return err
+}
+
+// SectionID returns the GPP Section ID the consent was parsed from.
+func (p *MspaParsedConsent) SectionID() int {
+	if p == nil {
+		return 0
+	}
+	return p.SID
+}
+
+// SectionName returns the API prefix of the section the consent was parsed from, e.g. "usnat".
+func (p *MspaParsedConsent) SectionName() string {
+	return GppSectionName(p.SectionID())
+}
+
+// SectionVersion returns the version of the section specification used to encode the string.
+func (p *MspaParsedConsent) SectionVersion() int {
+	if p == nil {
+		return 0
+	}
+	return p.Version
+}
@@ -13,6 +13,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 	iabconsent.UsNationalSID: {
 		// usnat v1 without false GPC subsection.
 		"BVVqAAEABCA.QA": {
+			SID:                                 iabconsent.UsNationalSID,
 			Version:                             1,
 			SharingNotice:                       iabconsent.NoticeProvided,
 			SaleOptOutNotice:                    iabconsent.NoticeProvided,
@@ -48,6 +49,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// usnat v1 with true GPC subsection.
 		"BVVqAAEABCA.YA": {
+			SID:                                 iabconsent.UsNationalSID,
 			Version:                             1,
 			SharingNotice:                       iabconsent.NoticeProvided,
 			SaleOptOutNotice:                    iabconsent.NoticeProvided,
@@ -84,6 +86,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// usnat v1 without subsection.
 		"BqqAqqqqqqA": {
+			SID:                                 iabconsent.UsNationalSID,
 			Version:                             1,
 			SharingNotice:                       iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                    iabconsent.NoticeNotProvided,
@@ -120,6 +123,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// usnat v2 with true GPC subsection.
 		"CVVVVVVVVVVW.YA": {
+			SID:                                 iabconsent.UsNationalSID,
 			Version:                             2,
 			SharingNotice:                       iabconsent.NoticeProvided,
 			SaleOptOutNotice:                    iabconsent.NoticeProvided,
@@ -165,6 +169,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 	iabconsent.UsCaliforniaSID: {
 		// usca with subsection of GPC True.
 		"BVoYYZoI.YA": {
+			SID:                         iabconsent.UsCaliforniaSID,
 			Version:                     1,
 			SaleOptOutNotice:            iabconsent.NoticeProvided,
 			SharingOptOutNotice:         iabconsent.NoticeProvided,
@@ -194,6 +199,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// usca without subsection.
 		"BVoYYZoI": {
+			SID:                         iabconsent.UsCaliforniaSID,
 			Version:                     1,
 			SaleOptOutNotice:            iabconsent.NoticeProvided,
 			SharingOptOutNotice:         iabconsent.NoticeProvided,
@@ -226,6 +232,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 	iabconsent.UsVirginiaSID: {
 		// usva with subsection of GPC True.
 		"BVoYYYI.YA": {
+			SID:                             iabconsent.UsVirginiaSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeProvided,
 			SaleOptOutNotice:                iabconsent.NoticeProvided,
@@ -252,6 +259,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// usva without subsection.
 		"BVoYYYI": {
+			SID:                             iabconsent.UsVirginiaSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeProvided,
 			SaleOptOutNotice:                iabconsent.NoticeProvided,
@@ -281,6 +289,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 	iabconsent.UsColoradoSID: {
 		// usco without GPC Subsection
 		"BVoYYQg": {
+			SID:                             iabconsent.UsColoradoSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeProvided,
 			SaleOptOutNotice:                iabconsent.NoticeProvided,
@@ -306,6 +315,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// usco with subsection of GPC True.
 		"BVoYYQg.YA": {
+			SID:                             iabconsent.UsColoradoSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeProvided,
 			SaleOptOutNotice:                iabconsent.NoticeProvided,
@@ -334,6 +344,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 	iabconsent.UsUtahSID: {
 		// usut with subsection of GPC False.
 		"BVaGGGCA.QA": {
+			SID:                                 iabconsent.UsUtahSID,
 			Version:                             1,
 			SharingNotice:                       iabconsent.NoticeProvided,
 			SaleOptOutNotice:                    iabconsent.NoticeProvided,
@@ -361,6 +372,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// usut with subsection of GPC True.
 		"BVaGGGCA.YA": {
+			SID:                                 iabconsent.UsUtahSID,
 			Version:                             1,
 			SharingNotice:                       iabconsent.NoticeProvided,
 			SaleOptOutNotice:                    iabconsent.NoticeProvided,
@@ -391,6 +403,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 	iabconsent.UsConnecticutSID: {
 		// usct with subsection of GPC False.
 		"BVoYYYQg": {
+			SID:                             iabconsent.UsConnecticutSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeProvided,
 			SaleOptOutNotice:                iabconsent.NoticeProvided,
@@ -419,6 +432,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// usct with subsection of GPC True.
 		"BVoYYYQg.YA": {
+			SID:                             iabconsent.UsConnecticutSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeProvided,
 			SaleOptOutNotice:                iabconsent.NoticeProvided,
@@ -450,6 +464,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 	iabconsent.UsFloridaSID: {
 		// usfl with subsection of GPC False.
 		"Bqqqqqqo": {
+			SID:                             iabconsent.UsFloridaSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeNotProvided,
@@ -479,6 +494,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// usfl with subsection of GPC True.
 		"Bqqqqqqo.YA": {
+			SID:                             iabconsent.UsFloridaSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeNotProvided,
@@ -511,6 +527,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 	iabconsent.UsMontanaSID: {
 		// usmt with subsection of GPC False.
 		"Bqqqqqqo": {
+			SID:                             iabconsent.UsMontanaSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeNotProvided,
@@ -540,6 +557,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// usmt with subsection of GPC True.
 		"Bqqqqqqo.YA": {
+			SID:                             iabconsent.UsMontanaSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeNotProvided,
@@ -572,6 +590,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 	iabconsent.UsOregonSID: {
 		// usor with subsection of GPC False.
 		"BqqqqqqqoA": {
+			SID:                             iabconsent.UsOregonSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeNotProvided,
@@ -604,6 +623,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// usor with subsection of GPC True.
 		"BqqqqqqqoA.YA": {
+			SID:                             iabconsent.UsOregonSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeNotProvided,
@@ -639,6 +659,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 	iabconsent.UsTexasSID: {
 		// ustx with subsection of GPC False.
 		"BqqqqqqA": {
+			SID:                             iabconsent.UsTexasSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeNotProvided,
@@ -666,6 +687,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// ustx with subsection of GPC True.
 		"BqqqqqqA.YA": {
+			SID:                             iabconsent.UsTexasSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeNotProvided,
@@ -696,6 +718,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 	iabconsent.UsDelawareSID: {
 		// usde with subsection of GPC False.
 		"BqqqqqqqoA": {
+			SID:                             iabconsent.UsDelawareSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeNotProvided,
@@ -728,6 +751,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// usde with subsection of GPC True.
 		"BqqqqqqqoA.YA": {
+			SID:                             iabconsent.UsDelawareSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeNotProvided,
@@ -763,6 +787,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 	iabconsent.UsIowaSID: {
 		// usia with subsection of GPC False
 		"BVVVVVVA": {
+			SID:                                 iabconsent.UsIowaSID,
 			Version:                             1,
 			SharingNotice:                       iabconsent.NoticeProvided,
 			SaleOptOutNotice:                    iabconsent.NoticeProvided,
@@ -790,6 +815,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// usia with subsection of GPC True
 		"BVVVVVVA.YA": {
+			SID:                                 iabconsent.UsIowaSID,
 			Version:                             1,
 			SharingNotice:                       iabconsent.NoticeProvided,
 			SaleOptOutNotice:                    iabconsent.NoticeProvided,
@@ -820,6 +846,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 	iabconsent.UsNebraskaSID: {
 		// usne with subsection of GPC False
 		"BmaqqqqA": {
+			SID:                             iabconsent.UsNebraskaSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeProvided,
@@ -847,6 +874,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// usne with subsection of GPC True
 		"BmaqqqqA.YA": {
+			SID:                             iabconsent.UsNebraskaSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeProvided,
@@ -877,6 +905,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 	iabconsent.UsNewHampshireSID: {
 		// usnh with subsection of GPC False
 		"Bpmqqqqo": {
+			SID:                             iabconsent.UsNewHampshireSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeNotProvided,
@@ -906,6 +935,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// usnh with subsection of GPC True
 		"Bpmqqqqo.YA": {
+			SID:                             iabconsent.UsNewHampshireSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeNotProvided,
@@ -938,6 +968,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 	iabconsent.UsNewJerseySID: {
 		// usnj with subsection of GPC False
 		"BlWqqqmaqA": {
+			SID:                             iabconsent.UsNewJerseySID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeProvided,
@@ -971,6 +1002,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// usnj with subsection of GPC True
 		"BlWqqqmaqA.YA": {
+			SID:                             iabconsent.UsNewJerseySID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeProvided,
@@ -1007,6 +1039,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 	iabconsent.UsTennesseeSID: {
 		// ustn with subsection of GPC False
 		"Bqqqqqo": {
+			SID:                             iabconsent.UsTennesseeSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeNotProvided,
@@ -1033,6 +1066,7 @@ var mspaConsentFixtures = map[int]map[string]*iabconsent.MspaParsedConsent{
 		},
 		// ustn with subsection of GPC True
 		"Bqqqqqo.YA": {
+			SID:                             iabconsent.UsTennesseeSID,
 			Version:                         1,
 			SharingNotice:                   iabconsent.NoticeNotProvided,
 			SaleOptOutNotice:                iabconsent.NoticeNotProvided,
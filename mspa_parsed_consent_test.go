@@ -247,3 +247,68 @@ func (s *MspaSuite) TestParseMSPAError(c *check.C) {
 		}
 	}
 }
+
+func (s *MspaSuite) TestReadMspaField(c *check.C) {
+	// Fields: 01 10 00 01 10 11
+	// Bytes: 01100001 10110000
+	var r = iabconsent.NewConsentReader([]byte{0b01100001, 0b10110000})
+	var p = &iabconsent.MspaParsedConsent{}
+
+	c.Check(r.ReadMspaField(p, iabconsent.MspaFieldSharingNotice, 0), check.IsNil)
+	c.Check(r.ReadMspaField(p, iabconsent.MspaFieldSaleOptOut, 0), check.IsNil)
+	c.Check(r.ReadMspaField(p, iabconsent.MspaFieldSensitiveDataProcessingConsents, 2), check.IsNil)
+	c.Check(r.ReadMspaField(p, iabconsent.MspaFieldCoveredTransaction, 0), check.IsNil)
+	c.Check(r.ReadMspaField(p, iabconsent.InvalidMspaField, 0), check.ErrorMatches, "unknown mspa field 0")
+
+	c.Check(p, check.DeepEquals, &iabconsent.MspaParsedConsent{
+		SharingNotice: iabconsent.NoticeProvided,
+		SaleOptOut:    iabconsent.NotOptedOut,
+		SensitiveDataProcessingConsents: map[int]iabconsent.MspaConsent{
+			0: iabconsent.ConsentNotApplicable,
+			1: iabconsent.NoConsent,
+		},
+		MspaCoveredTransaction: iabconsent.MspaNo,
+	})
+}
+
+func (s *MspaSuite) TestMspaSectionVersions(c *check.C) {
+	var tcs = []struct {
+		desc     string
+		sid      int
+		expected []int
+	}{
+		{
+			desc:     "US National supports v1 and v2.",
+			sid:      iabconsent.UsNationalSID,
+			expected: []int{1, 2},
+		},
+		{
+			desc:     "California supports v1.",
+			sid:      iabconsent.UsCaliforniaSID,
+			expected: []int{1},
+		},
+		{
+			desc:     "Tennessee supports v1.",
+			sid:      iabconsent.UsTennesseeSID,
+			expected: []int{1},
+		},
+		{
+			desc:     "Unsupported Section ID.",
+			sid:      2,
+			expected: []int{},
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		c.Check(iabconsent.MspaSectionVersions(tc.sid), check.DeepEquals, tc.expected)
+	}
+}
+
+func (s *MspaSuite) TestParseMSPAUnsupportedStateVersion(c *check.C) {
+	// Version 2 of the usca core segment, which is not yet supported.
+	var gppSection = iabconsent.NewMspa(iabconsent.UsCaliforniaSID, "CVoYYZoI")
+	var p, err = gppSection.ParseConsent()
+
+	c.Check(p, check.IsNil)
+	c.Check(err, check.ErrorMatches, "unsupported version: 2")
+}
@@ -3,6 +3,7 @@ package iabconsent
 import (
 	"encoding/base64"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -151,63 +152,389 @@ func NewMspa(sid int, section string) GppSectionParser {
 	return nil
 }
 
-func (m *MspaUsNational) ParseConsent() (GppParsedConsent, error) {
-	var segments = strings.Split(m.sectionValue, ".")
+// mspaSection describes every supported version of a single MSPA section.
+type mspaSection struct {
+	// The API prefix of the section, e.g. usnat.
+	name string
+	// The format of each supported version of the section, keyed by Version.
+	versions map[int]mspaSectionVersion
+}
+
+// mspaSectionVersion describes the core segment of a single version of an MSPA section.
+type mspaSectionVersion struct {
+	// The valid bit length of the core segment, including padding.
+	length int
+	// The fields following the Version field, in the order they are encoded.
+	fields []mspaFieldSpec
+}
+
+// mspaFieldSpec is a single field of an MSPA core segment. Bitfields hold n values.
+type mspaFieldSpec struct {
+	field MspaField
+	n     uint
+}
+
+// usNationalFields returns the usnat core segment fields, which only differ between
+// versions by the length of the sensitive data bitfields.
+func usNationalFields(sensitiveData, knownChild uint) []mspaFieldSpec {
+	return []mspaFieldSpec{
+		{field: MspaFieldSharingNotice},
+		{field: MspaFieldSaleOptOutNotice},
+		{field: MspaFieldSharingOptOutNotice},
+		{field: MspaFieldTargetedAdvertisingOptOutNotice},
+		{field: MspaFieldSensitiveDataProcessingOptOutNotice},
+		{field: MspaFieldSensitiveDataLimitUseNotice},
+		{field: MspaFieldSaleOptOut},
+		{field: MspaFieldSharingOptOut},
+		{field: MspaFieldTargetedAdvertisingOptOut},
+		{field: MspaFieldSensitiveDataProcessingConsents, n: sensitiveData},
+		{field: MspaFieldKnownChildSensitiveDataConsents, n: knownChild},
+		{field: MspaFieldPersonalDataConsents},
+		{field: MspaFieldCoveredTransaction},
+		{field: MspaFieldOptOutOptionMode},
+		{field: MspaFieldServiceProviderMode},
+	}
+}
+
+// mspaSections maps each supported Section ID to the formats of its versions. A new version
+// of a section is supported by adding its format here, next to the existing versions.
+// The specs for each section can be found here:
+// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections
+//
+// 0 is not a valid value according to the docs for MspaCoveredTransaction. Instead of erroring,
+// the value of the string is returned, and downstream processing handles if the value is 0.
+var mspaSections = map[int]mspaSection{
+	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/blob/main/Sections/US-National/IAB%20Privacy%E2%80%99s%20Multi-State%20Privacy%20Agreement%20(MSPA)%20US%20National%20Technical%20Specification.md
+	UsNationalSID: {
+		name: "usnat",
+		versions: map[int]mspaSectionVersion{
+			1: {length: MspaUsNationalV1StringLength, fields: usNationalFields(12, 2)},
+			2: {length: MspaUsNationalV2StringLength, fields: usNationalFields(16, 3)},
+		},
+	},
+	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/CA
+	UsCaliforniaSID: {
+		name: "usca",
+		versions: map[int]mspaSectionVersion{
+			1: {length: MspaUsCaV1StringLength, fields: []mspaFieldSpec{
+				{field: MspaFieldSaleOptOutNotice},
+				{field: MspaFieldSharingOptOutNotice},
+				{field: MspaFieldSensitiveDataLimitUseNotice},
+				{field: MspaFieldSaleOptOut},
+				{field: MspaFieldSharingOptOut},
+				// SensitiveDataProcessingOptOuts, as opposed to Consent.
+				{field: MspaFieldSensitiveDataProcessingOptOuts, n: 9},
+				{field: MspaFieldKnownChildSensitiveDataConsents, n: 2},
+				{field: MspaFieldPersonalDataConsents},
+				{field: MspaFieldCoveredTransaction},
+				{field: MspaFieldOptOutOptionMode},
+				{field: MspaFieldServiceProviderMode},
+			}},
+		},
+	},
+	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/VA
+	UsVirginiaSID: {
+		name: "usva",
+		versions: map[int]mspaSectionVersion{
+			1: {length: MspaUsVaV1StringLength, fields: []mspaFieldSpec{
+				{field: MspaFieldSharingNotice},
+				{field: MspaFieldSaleOptOutNotice},
+				{field: MspaFieldTargetedAdvertisingOptOutNotice},
+				{field: MspaFieldSaleOptOut},
+				{field: MspaFieldTargetedAdvertisingOptOut},
+				{field: MspaFieldSensitiveDataProcessingConsents, n: 8},
+				{field: MspaFieldKnownChildSensitiveDataConsents, n: 1},
+				{field: MspaFieldCoveredTransaction},
+				{field: MspaFieldOptOutOptionMode},
+				{field: MspaFieldServiceProviderMode},
+			}},
+		},
+	},
+	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/CO
+	UsColoradoSID: {
+		name: "usco",
+		versions: map[int]mspaSectionVersion{
+			1: {length: MspaUsCoV1StringLength, fields: []mspaFieldSpec{
+				{field: MspaFieldSharingNotice},
+				{field: MspaFieldSaleOptOutNotice},
+				{field: MspaFieldTargetedAdvertisingOptOutNotice},
+				{field: MspaFieldSaleOptOut},
+				{field: MspaFieldTargetedAdvertisingOptOut},
+				{field: MspaFieldSensitiveDataProcessingConsents, n: 7},
+				{field: MspaFieldKnownChildSensitiveDataConsents, n: 1},
+				{field: MspaFieldCoveredTransaction},
+				{field: MspaFieldOptOutOptionMode},
+				{field: MspaFieldServiceProviderMode},
+			}},
+		},
+	},
+	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/UT
+	UsUtahSID: {
+		name: "usut",
+		versions: map[int]mspaSectionVersion{
+			1: {length: MspaUsUtV1StringLength, fields: []mspaFieldSpec{
+				{field: MspaFieldSharingNotice},
+				{field: MspaFieldSaleOptOutNotice},
+				{field: MspaFieldTargetedAdvertisingOptOutNotice},
+				{field: MspaFieldSensitiveDataProcessingOptOutNotice},
+				{field: MspaFieldSaleOptOut},
+				{field: MspaFieldTargetedAdvertisingOptOut},
+				{field: MspaFieldSensitiveDataProcessingOptOuts, n: 8},
+				{field: MspaFieldKnownChildSensitiveDataConsents, n: 1},
+				{field: MspaFieldCoveredTransaction},
+				{field: MspaFieldOptOutOptionMode},
+				{field: MspaFieldServiceProviderMode},
+			}},
+		},
+	},
+	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/CT
+	UsConnecticutSID: {
+		name: "usct",
+		versions: map[int]mspaSectionVersion{
+			1: {length: MspaUsCtV1StringLength, fields: []mspaFieldSpec{
+				{field: MspaFieldSharingNotice},
+				{field: MspaFieldSaleOptOutNotice},
+				{field: MspaFieldTargetedAdvertisingOptOutNotice},
+				{field: MspaFieldSaleOptOut},
+				{field: MspaFieldTargetedAdvertisingOptOut},
+				{field: MspaFieldSensitiveDataProcessingConsents, n: 8},
+				{field: MspaFieldKnownChildSensitiveDataConsents, n: 3},
+				{field: MspaFieldCoveredTransaction},
+				{field: MspaFieldOptOutOptionMode},
+				{field: MspaFieldServiceProviderMode},
+			}},
+		},
+	},
+	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/FL
+	UsFloridaSID: {
+		name: "usfl",
+		versions: map[int]mspaSectionVersion{
+			1: {length: MspaUsFlV1StringLength, fields: []mspaFieldSpec{
+				{field: MspaFieldSharingNotice},
+				{field: MspaFieldSaleOptOutNotice},
+				{field: MspaFieldTargetedAdvertisingOptOutNotice},
+				{field: MspaFieldSaleOptOut},
+				{field: MspaFieldTargetedAdvertisingOptOut},
+				{field: MspaFieldSensitiveDataProcessingConsents, n: 8},
+				{field: MspaFieldKnownChildSensitiveDataConsents, n: 3},
+				{field: MspaFieldPersonalDataConsents},
+				{field: MspaFieldCoveredTransaction},
+				{field: MspaFieldOptOutOptionMode},
+				{field: MspaFieldServiceProviderMode},
+			}},
+		},
+	},
+	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/MT
+	UsMontanaSID: {
+		name: "usmt",
+		versions: map[int]mspaSectionVersion{
+			1: {length: MspaUsMtV1StringLength, fields: []mspaFieldSpec{
+				{field: MspaFieldSharingNotice},
+				{field: MspaFieldSaleOptOutNotice},
+				{field: MspaFieldTargetedAdvertisingOptOutNotice},
+				{field: MspaFieldSaleOptOut},
+				{field: MspaFieldTargetedAdvertisingOptOut},
+				{field: MspaFieldSensitiveDataProcessingConsents, n: 8},
+				{field: MspaFieldKnownChildSensitiveDataConsents, n: 3},
+				{field: MspaFieldPersonalDataConsents},
+				{field: MspaFieldCoveredTransaction},
+				{field: MspaFieldOptOutOptionMode},
+				{field: MspaFieldServiceProviderMode},
+			}},
+		},
+	},
+	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/OR
+	UsOregonSID: {
+		name: "usor",
+		versions: map[int]mspaSectionVersion{
+			1: {length: MspaUsOrV1StringLength, fields: []mspaFieldSpec{
+				{field: MspaFieldSharingNotice},
+				{field: MspaFieldSaleOptOutNotice},
+				{field: MspaFieldTargetedAdvertisingOptOutNotice},
+				{field: MspaFieldSaleOptOut},
+				{field: MspaFieldTargetedAdvertisingOptOut},
+				{field: MspaFieldSensitiveDataProcessingConsents, n: 11},
+				{field: MspaFieldKnownChildSensitiveDataConsents, n: 3},
+				{field: MspaFieldPersonalDataConsents},
+				{field: MspaFieldCoveredTransaction},
+				{field: MspaFieldOptOutOptionMode},
+				{field: MspaFieldServiceProviderMode},
+			}},
+		},
+	},
+	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/TX
+	UsTexasSID: {
+		name: "ustx",
+		versions: map[int]mspaSectionVersion{
+			1: {length: MspaUsTxV1StringLength, fields: []mspaFieldSpec{
+				{field: MspaFieldSharingNotice},
+				{field: MspaFieldSaleOptOutNotice},
+				{field: MspaFieldTargetedAdvertisingOptOutNotice},
+				{field: MspaFieldSaleOptOut},
+				{field: MspaFieldTargetedAdvertisingOptOut},
+				{field: MspaFieldSensitiveDataProcessingConsents, n: 8},
+				{field: MspaFieldKnownChildSensitiveDataConsents, n: 1},
+				{field: MspaFieldPersonalDataConsents},
+				{field: MspaFieldCoveredTransaction},
+				{field: MspaFieldOptOutOptionMode},
+				{field: MspaFieldServiceProviderMode},
+			}},
+		},
+	},
+	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/DE
+	UsDelawareSID: {
+		name: "usde",
+		versions: map[int]mspaSectionVersion{
+			1: {length: MspaUsDeV1StringLength, fields: []mspaFieldSpec{
+				{field: MspaFieldSharingNotice},
+				{field: MspaFieldSaleOptOutNotice},
+				{field: MspaFieldTargetedAdvertisingOptOutNotice},
+				{field: MspaFieldSaleOptOut},
+				{field: MspaFieldTargetedAdvertisingOptOut},
+				{field: MspaFieldSensitiveDataProcessingConsents, n: 9},
+				{field: MspaFieldKnownChildSensitiveDataConsents, n: 5},
+				{field: MspaFieldPersonalDataConsents},
+				{field: MspaFieldCoveredTransaction},
+				{field: MspaFieldOptOutOptionMode},
+				{field: MspaFieldServiceProviderMode},
+			}},
+		},
+	},
+	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/IA
+	UsIowaSID: {
+		name: "usia",
+		versions: map[int]mspaSectionVersion{
+			1: {length: MspaUsIaV1StringLength, fields: []mspaFieldSpec{
+				{field: MspaFieldSharingNotice},
+				{field: MspaFieldSaleOptOutNotice},
+				{field: MspaFieldTargetedAdvertisingOptOutNotice},
+				{field: MspaFieldSensitiveDataProcessingOptOutNotice},
+				{field: MspaFieldSaleOptOut},
+				{field: MspaFieldTargetedAdvertisingOptOut},
+				{field: MspaFieldSensitiveDataProcessingOptOuts, n: 8},
+				{field: MspaFieldKnownChildSensitiveDataConsents, n: 1},
+				{field: MspaFieldCoveredTransaction},
+				{field: MspaFieldOptOutOptionMode},
+				{field: MspaFieldServiceProviderMode},
+			}},
+		},
+	},
+	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/NE
+	UsNebraskaSID: {
+		name: "usne",
+		versions: map[int]mspaSectionVersion{
+			1: {length: MspaUsNeV1StringLength, fields: []mspaFieldSpec{
+				{field: MspaFieldSharingNotice},
+				{field: MspaFieldSaleOptOutNotice},
+				{field: MspaFieldTargetedAdvertisingOptOutNotice},
+				{field: MspaFieldSaleOptOut},
+				{field: MspaFieldTargetedAdvertisingOptOut},
+				{field: MspaFieldSensitiveDataProcessingConsents, n: 8},
+				{field: MspaFieldKnownChildSensitiveDataConsents, n: 1},
+				{field: MspaFieldPersonalDataConsents},
+				{field: MspaFieldCoveredTransaction},
+				{field: MspaFieldOptOutOptionMode},
+				{field: MspaFieldServiceProviderMode},
+			}},
+		},
+	},
+	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/NH
+	UsNewHampshireSID: {
+		name: "usnh",
+		versions: map[int]mspaSectionVersion{
+			1: {length: MspaUsNhV1StringLength, fields: []mspaFieldSpec{
+				{field: MspaFieldSharingNotice},
+				{field: MspaFieldSaleOptOutNotice},
+				{field: MspaFieldTargetedAdvertisingOptOutNotice},
+				{field: MspaFieldSaleOptOut},
+				{field: MspaFieldTargetedAdvertisingOptOut},
+				{field: MspaFieldSensitiveDataProcessingConsents, n: 8},
+				{field: MspaFieldKnownChildSensitiveDataConsents, n: 3},
+				{field: MspaFieldPersonalDataConsents},
+				{field: MspaFieldCoveredTransaction},
+				{field: MspaFieldOptOutOptionMode},
+				{field: MspaFieldServiceProviderMode},
+			}},
+		},
+	},
+	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/NJ
+	UsNewJerseySID: {
+		name: "usnj",
+		versions: map[int]mspaSectionVersion{
+			1: {length: MspaUsNjV1StringLength, fields: []mspaFieldSpec{
+				{field: MspaFieldSharingNotice},
+				{field: MspaFieldSaleOptOutNotice},
+				{field: MspaFieldTargetedAdvertisingOptOutNotice},
+				{field: MspaFieldSaleOptOut},
+				{field: MspaFieldTargetedAdvertisingOptOut},
+				{field: MspaFieldSensitiveDataProcessingConsents, n: 10},
+				{field: MspaFieldKnownChildSensitiveDataConsents, n: 5},
+				{field: MspaFieldPersonalDataConsents},
+				{field: MspaFieldCoveredTransaction},
+				{field: MspaFieldOptOutOptionMode},
+				{field: MspaFieldServiceProviderMode},
+			}},
+		},
+	},
+	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/TN
+	UsTennesseeSID: {
+		name: "ustn",
+		versions: map[int]mspaSectionVersion{
+			// Tennessee does not encode MspaCoveredTransaction.
+			1: {length: MspaUsTnV1StringLength, fields: []mspaFieldSpec{
+				{field: MspaFieldSharingNotice},
+				{field: MspaFieldSaleOptOutNotice},
+				{field: MspaFieldTargetedAdvertisingOptOutNotice},
+				{field: MspaFieldSaleOptOut},
+				{field: MspaFieldTargetedAdvertisingOptOut},
+				{field: MspaFieldSensitiveDataProcessingConsents, n: 8},
+				{field: MspaFieldKnownChildSensitiveDataConsents, n: 1},
+				{field: MspaFieldPersonalDataConsents},
+				{field: MspaFieldOptOutOptionMode},
+				{field: MspaFieldServiceProviderMode},
+			}},
+		},
+	},
+}
+
+// parseMspaSection parses the value of the MSPA section with Section ID sid. The Version of the
+// core segment selects which format of the section is used to read the remaining fields.
+func parseMspaSection(sid int, value string) (p GppParsedConsent, err error) {
+	defer recoverPanic(&err)
+	return readMspaSection(sid, value)
+}
+
+// readMspaSection reads the value of the MSPA section with Section ID sid.
+func readMspaSection(sid int, value string) (GppParsedConsent, error) {
+	var section = mspaSections[sid]
+	var segments = strings.Split(value, ".")
 
 	var b, err = base64.RawURLEncoding.DecodeString(segments[0])
 	if err != nil {
-		return nil, errors.Wrap(err, "parse usnat consent string")
+		return nil, errors.Wrap(err, "parse "+section.name+" consent string")
 	}
 
 	var r = NewConsentReader(b)
 
-	// This block of code directly describes the format of the payload.
-	// The spec for the consent string can be found here:
-	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/blob/main/Sections/US-National/IAB%20Privacy%E2%80%99s%20Multi-State%20Privacy%20Agreement%20(MSPA)%20US%20National%20Technical%20Specification.md
-	var p = &MspaParsedConsent{}
+	var p = &MspaParsedConsent{SID: sid}
 	p.Version, _ = r.ReadInt(6)
 
-	// Support both v1 and v2
-	if p.Version != 1 && p.Version != 2 {
+	var format, ok = section.versions[p.Version]
+	if !ok {
 		return nil, errors.New("unsupported version: " + fmt.Sprint(p.Version))
 	}
-
-	// validate the length of the bit string for v1 and v2
-
-	if p.Version == 1 && r.Size() != MspaUsNationalV1StringLength {
-		return nil, errors.New("invalid consent string length for v1")
-	} else if p.Version == 2 && r.Size() != MspaUsNationalV2StringLength {
-		return nil, errors.New("invalid consent string length for v2")
+	// validate the length of the bit string for the version.
+	if r.Size() != format.length {
+		return nil, errors.New("invalid consent string length for v" + fmt.Sprint(p.Version))
 	}
 
-	p.SharingNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOutNotice, _ = r.ReadMspaNotice()
-	p.SharingOptOutNotice, _ = r.ReadMspaNotice()
-	p.TargetedAdvertisingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SensitiveDataProcessingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SensitiveDataLimitUseNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOut, _ = r.ReadMspaOptOut()
-	p.SharingOptOut, _ = r.ReadMspaOptOut()
-	p.TargetedAdvertisingOptOut, _ = r.ReadMspaOptOut()
-
-	// see spec in IAB GPP repo for differences between v1 and v2
-	if p.Version == 1 {
-		p.SensitiveDataProcessingConsents, _ = r.ReadMspaBitfieldConsent(12)
-		p.KnownChildSensitiveDataConsents, _ = r.ReadMspaBitfieldConsent(2)
-	} else if p.Version == 2 {
-		p.SensitiveDataProcessingConsents, _ = r.ReadMspaBitfieldConsent(16)
-		p.KnownChildSensitiveDataConsents, _ = r.ReadMspaBitfieldConsent(3)
+	for _, f := range format.fields {
+		r.ReadMspaField(p, f.field, f.n)
 	}
-	p.PersonalDataConsents, _ = r.ReadMspaConsent()
-	p.MspaCoveredTransaction, _ = r.ReadMspaNaYesNo()
-	// 0 is not a valid value according to the docs for MspaCoveredTransaction. Instead of erroring,
-	// return the value of the string, and let downstream processing handle if the value is 0.
-	p.MspaOptOutOptionMode, _ = r.ReadMspaNaYesNo()
-	p.MspaServiceProviderMode, _ = r.ReadMspaNaYesNo()
 
 	if len(segments) > 1 {
 		var gppSubsectionConsent *GppSubSection
-		gppSubsectionConsent, err = ParseGppSubSections(segments[1:])
+		gppSubsectionConsent, err = parseGppSubSections(segments[1:])
 		if err != nil {
 			return p, err
 		}
@@ -217,755 +544,77 @@ func (m *MspaUsNational) ParseConsent() (GppParsedConsent, error) {
 	return p, r.Err
 }
 
-func (m *MspaUsCA) ParseConsent() (GppParsedConsent, error) {
-	var segments = strings.Split(m.sectionValue, ".")
-
-	var b, err = base64.RawURLEncoding.DecodeString(segments[0])
-	if err != nil {
-		return nil, errors.Wrap(err, "parse usca consent string")
-	}
-
-	var r = NewConsentReader(b)
-
-	// This block of code directly describes the format of the payload.
-	// The spec for the consent string can be found here:
-	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/CA
-	var p = &MspaParsedConsent{}
-	p.Version, _ = r.ReadInt(6)
-
-	if p.Version != 1 {
-		return nil, errors.New("unsupported version: " + fmt.Sprint(p.Version))
-	}
-
-	// validate the length of the bit string for v1
-	if p.Version == 1 && r.Size() != MspaUsCaV1StringLength {
-		return nil, errors.New("invalid consent string length for v1")
-	}
-
-	p.SaleOptOutNotice, _ = r.ReadMspaNotice()
-	p.SharingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SensitiveDataLimitUseNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOut, _ = r.ReadMspaOptOut()
-	p.SharingOptOut, _ = r.ReadMspaOptOut()
-	// SensitiveDataProcessingOptOuts, as opposed to Consent.
-	p.SensitiveDataProcessingOptOuts, _ = r.ReadMspaBitfieldOptOut(9)
-	p.KnownChildSensitiveDataConsents, _ = r.ReadMspaBitfieldConsent(2)
-	p.PersonalDataConsents, _ = r.ReadMspaConsent()
-	p.MspaCoveredTransaction, _ = r.ReadMspaNaYesNo()
-	// 0 is not a valid value according to the docs for MspaCoveredTransaction. Instead of erroring,
-	// return the value of the string, and let downstream processing handle if the value is 0.
-	p.MspaOptOutOptionMode, _ = r.ReadMspaNaYesNo()
-	p.MspaServiceProviderMode, _ = r.ReadMspaNaYesNo()
-
-	if len(segments) > 1 {
-		var gppSubsectionConsent *GppSubSection
-		gppSubsectionConsent, err = ParseGppSubSections(segments[1:])
-		if err != nil {
-			return p, err
-		}
-		p.Gpc = gppSubsectionConsent.Gpc
-	}
+func (m *MspaUsNational) ParseConsent() (GppParsedConsent, error) {
+	return parseMspaSection(UsNationalSID, m.sectionValue)
+}
 
-	return p, r.Err
+func (m *MspaUsCA) ParseConsent() (GppParsedConsent, error) {
+	return parseMspaSection(UsCaliforniaSID, m.sectionValue)
 }
 
 func (m *MspaUsVA) ParseConsent() (GppParsedConsent, error) {
-	var segments = strings.Split(m.sectionValue, ".")
-
-	var b, err = base64.RawURLEncoding.DecodeString(segments[0])
-	if err != nil {
-		return nil, errors.Wrap(err, "parse usva consent string")
-	}
-
-	var r = NewConsentReader(b)
-
-	// This block of code directly describes the format of the payload.
-	// The spec for the consent string can be found here:
-	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/VA
-	var p = &MspaParsedConsent{}
-	p.Version, _ = r.ReadInt(6)
-
-	if p.Version != 1 {
-		return nil, errors.New("unsupported version: " + fmt.Sprint(p.Version))
-	}
-
-	// validate the length of the bit string for v1
-	if p.Version == 1 && r.Size() != MspaUsVaV1StringLength {
-		return nil, errors.New("invalid consent string length for v1")
-	}
-
-	p.SharingNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOutNotice, _ = r.ReadMspaNotice()
-	p.TargetedAdvertisingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOut, _ = r.ReadMspaOptOut()
-	p.TargetedAdvertisingOptOut, _ = r.ReadMspaOptOut()
-	p.SensitiveDataProcessingConsents, _ = r.ReadMspaBitfieldConsent(8)
-	p.KnownChildSensitiveDataConsents, _ = r.ReadMspaBitfieldConsent(1)
-	// 0 is not a valid value according to the docs for MspaCoveredTransaction. Instead of erroring,
-	// return the value of the string, and let downstream processing handle if the value is 0.
-	p.MspaCoveredTransaction, _ = r.ReadMspaNaYesNo()
-	p.MspaOptOutOptionMode, _ = r.ReadMspaNaYesNo()
-	p.MspaServiceProviderMode, _ = r.ReadMspaNaYesNo()
-
-	if len(segments) > 1 {
-		var gppSubsectionConsent *GppSubSection
-		gppSubsectionConsent, err = ParseGppSubSections(segments[1:])
-		if err != nil {
-			return p, err
-		}
-		p.Gpc = gppSubsectionConsent.Gpc
-	}
-
-	return p, r.Err
+	return parseMspaSection(UsVirginiaSID, m.sectionValue)
 }
 
 func (m *MspaUsCO) ParseConsent() (GppParsedConsent, error) {
-	var segments = strings.Split(m.sectionValue, ".")
-
-	var b, err = base64.RawURLEncoding.DecodeString(segments[0])
-	if err != nil {
-		return nil, errors.Wrap(err, "parse usco consent string")
-	}
-
-	var r = NewConsentReader(b)
-
-	// This block of code directly describes the format of the payload.
-	// The spec for the consent string can be found here:
-	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/CO
-	var p = &MspaParsedConsent{}
-	p.Version, _ = r.ReadInt(6)
-
-	if p.Version != 1 {
-		return nil, errors.New("unsupported version: " + fmt.Sprint(p.Version))
-	}
-
-	// validate the length of the bit string for v1
-	if p.Version == 1 && r.Size() != MspaUsCoV1StringLength {
-		return nil, errors.New("invalid consent string length for v1")
-	}
-
-	p.SharingNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOutNotice, _ = r.ReadMspaNotice()
-	p.TargetedAdvertisingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOut, _ = r.ReadMspaOptOut()
-	p.TargetedAdvertisingOptOut, _ = r.ReadMspaOptOut()
-	p.SensitiveDataProcessingConsents, _ = r.ReadMspaBitfieldConsent(7)
-	p.KnownChildSensitiveDataConsents, _ = r.ReadMspaBitfieldConsent(1)
-	p.MspaCoveredTransaction, _ = r.ReadMspaNaYesNo()
-	// 0 is not a valid value according to the docs for MspaCoveredTransaction. Instead of erroring,
-	// return the value of the string, and let downstream processing handle if the value is 0.
-	p.MspaOptOutOptionMode, _ = r.ReadMspaNaYesNo()
-	p.MspaServiceProviderMode, _ = r.ReadMspaNaYesNo()
-
-	if len(segments) > 1 {
-		var gppSubsectionConsent *GppSubSection
-		gppSubsectionConsent, err = ParseGppSubSections(segments[1:])
-		if err != nil {
-			return p, err
-		}
-		p.Gpc = gppSubsectionConsent.Gpc
-	}
-
-	return p, r.Err
+	return parseMspaSection(UsColoradoSID, m.sectionValue)
 }
 
 func (m *MspaUsUT) ParseConsent() (GppParsedConsent, error) {
-	var segments = strings.Split(m.sectionValue, ".")
-
-	var b, err = base64.RawURLEncoding.DecodeString(segments[0])
-	if err != nil {
-		return nil, errors.Wrap(err, "parse usut consent string")
-	}
-
-	var r = NewConsentReader(b)
-
-	// This block of code directly describes the format of the payload.
-	// The spec for the consent string can be found here:
-	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/UT
-	var p = &MspaParsedConsent{}
-	p.Version, _ = r.ReadInt(6)
-
-	if p.Version != 1 {
-		return nil, errors.New("unsupported version: " + fmt.Sprint(p.Version))
-	}
-
-	// validate the length of the bit string for v1
-	if p.Version == 1 && r.Size() != MspaUsUtV1StringLength {
-		return nil, errors.New("invalid consent string length for v1")
-	}
-
-	p.SharingNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOutNotice, _ = r.ReadMspaNotice()
-	p.TargetedAdvertisingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SensitiveDataProcessingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOut, _ = r.ReadMspaOptOut()
-	p.TargetedAdvertisingOptOut, _ = r.ReadMspaOptOut()
-	p.SensitiveDataProcessingOptOuts, _ = r.ReadMspaBitfieldOptOut(8)
-	p.KnownChildSensitiveDataConsents, _ = r.ReadMspaBitfieldConsent(1)
-	p.MspaCoveredTransaction, _ = r.ReadMspaNaYesNo()
-	// 0 is not a valid value according to the docs for MspaCoveredTransaction. Instead of erroring,
-	// return the value of the string, and let downstream processing handle if the value is 0.
-	p.MspaOptOutOptionMode, _ = r.ReadMspaNaYesNo()
-	p.MspaServiceProviderMode, _ = r.ReadMspaNaYesNo()
-
-	if len(segments) > 1 {
-		var gppSubsectionConsent *GppSubSection
-		gppSubsectionConsent, err = ParseGppSubSections(segments[1:])
-		if err != nil {
-			return p, err
-		}
-		p.Gpc = gppSubsectionConsent.Gpc
-	}
-
-	return p, r.Err
+	return parseMspaSection(UsUtahSID, m.sectionValue)
 }
 
 func (m *MspaUsCT) ParseConsent() (GppParsedConsent, error) {
-	var segments = strings.Split(m.sectionValue, ".")
-
-	var b, err = base64.RawURLEncoding.DecodeString(segments[0])
-	if err != nil {
-		return nil, errors.Wrap(err, "parse usct consent string")
-	}
-
-	var r = NewConsentReader(b)
-
-	// This block of code directly describes the format of the payload.
-	// The spec for the consent string can be found here:
-	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/CT
-	var p = &MspaParsedConsent{}
-	p.Version, _ = r.ReadInt(6)
-
-	if p.Version != 1 {
-		return nil, errors.New("unsupported version: " + fmt.Sprint(p.Version))
-	}
-
-	// validate the length of the bit string for v1
-	if p.Version == 1 && r.Size() != MspaUsCtV1StringLength {
-		return nil, errors.New("invalid consent string length for v1")
-	}
-
-	p.SharingNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOutNotice, _ = r.ReadMspaNotice()
-	p.TargetedAdvertisingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOut, _ = r.ReadMspaOptOut()
-	p.TargetedAdvertisingOptOut, _ = r.ReadMspaOptOut()
-	p.SensitiveDataProcessingConsents, _ = r.ReadMspaBitfieldConsent(8)
-	p.KnownChildSensitiveDataConsents, _ = r.ReadMspaBitfieldConsent(3)
-	p.MspaCoveredTransaction, _ = r.ReadMspaNaYesNo()
-	// 0 is not a valid value according to the docs for MspaCoveredTransaction. Instead of erroring,
-	// return the value of the string, and let downstream processing handle if the value is 0.
-	p.MspaOptOutOptionMode, _ = r.ReadMspaNaYesNo()
-	p.MspaServiceProviderMode, _ = r.ReadMspaNaYesNo()
-
-	if len(segments) > 1 {
-		var gppSubsectionConsent *GppSubSection
-		gppSubsectionConsent, err = ParseGppSubSections(segments[1:])
-		if err != nil {
-			return p, err
-		}
-		p.Gpc = gppSubsectionConsent.Gpc
-	}
-
-	return p, r.Err
+	return parseMspaSection(UsConnecticutSID, m.sectionValue)
 }
 
 func (m *MspaUsFL) ParseConsent() (GppParsedConsent, error) {
-	var segments = strings.Split(m.sectionValue, ".")
-
-	var b, err = base64.RawURLEncoding.DecodeString(segments[0])
-	if err != nil {
-		return nil, errors.Wrap(err, "parse usfl consent string")
-	}
-
-	var r = NewConsentReader(b)
-
-	// This block of code directly describes the format of the payload.
-	// The spec for the consent string can be found here:
-	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/FL
-	var p = &MspaParsedConsent{}
-	p.Version, _ = r.ReadInt(6)
-
-	if p.Version != 1 {
-		return nil, errors.New("unsupported version: " + fmt.Sprint(p.Version))
-	}
-
-	// validate the length of the bit string for v1
-	if p.Version == 1 && r.Size() != MspaUsFlV1StringLength {
-		return nil, errors.New("invalid consent string length for v1")
-	}
-
-	p.SharingNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOutNotice, _ = r.ReadMspaNotice()
-	p.TargetedAdvertisingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOut, _ = r.ReadMspaOptOut()
-	p.TargetedAdvertisingOptOut, _ = r.ReadMspaOptOut()
-	p.SensitiveDataProcessingConsents, _ = r.ReadMspaBitfieldConsent(8)
-	p.KnownChildSensitiveDataConsents, _ = r.ReadMspaBitfieldConsent(3)
-	p.PersonalDataConsents, _ = r.ReadMspaConsent()
-	p.MspaCoveredTransaction, _ = r.ReadMspaNaYesNo()
-	// 0 is not a valid value according to the docs for MspaCoveredTransaction. Instead of erroring,
-	// return the value of the string, and let downstream processing handle if the value is 0.
-	p.MspaOptOutOptionMode, _ = r.ReadMspaNaYesNo()
-	p.MspaServiceProviderMode, _ = r.ReadMspaNaYesNo()
-
-	if len(segments) > 1 {
-		var gppSubsectionConsent *GppSubSection
-		gppSubsectionConsent, err = ParseGppSubSections(segments[1:])
-		if err != nil {
-			return p, err
-		}
-		p.Gpc = gppSubsectionConsent.Gpc
-	}
-
-	return p, r.Err
+	return parseMspaSection(UsFloridaSID, m.sectionValue)
 }
 
 func (m *MspaUsMT) ParseConsent() (GppParsedConsent, error) {
-	var segments = strings.Split(m.sectionValue, ".")
-
-	var b, err = base64.RawURLEncoding.DecodeString(segments[0])
-	if err != nil {
-		return nil, errors.Wrap(err, "parse usmt consent string")
-	}
-
-	var r = NewConsentReader(b)
-
-	// This block of code directly describes the format of the payload.
-	// The spec for the consent string can be found here:
-	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/MT
-	var p = &MspaParsedConsent{}
-	p.Version, _ = r.ReadInt(6)
-
-	if p.Version != 1 {
-		return nil, errors.New("unsupported version: " + fmt.Sprint(p.Version))
-	}
-
-	// validate the length of the bit string for v1
-	if p.Version == 1 && r.Size() != MspaUsMtV1StringLength {
-		return nil, errors.New("invalid consent string length for v1")
-	}
-
-	p.SharingNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOutNotice, _ = r.ReadMspaNotice()
-	p.TargetedAdvertisingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOut, _ = r.ReadMspaOptOut()
-	p.TargetedAdvertisingOptOut, _ = r.ReadMspaOptOut()
-	p.SensitiveDataProcessingConsents, _ = r.ReadMspaBitfieldConsent(8)
-	p.KnownChildSensitiveDataConsents, _ = r.ReadMspaBitfieldConsent(3)
-	p.PersonalDataConsents, _ = r.ReadMspaConsent()
-	p.MspaCoveredTransaction, _ = r.ReadMspaNaYesNo()
-	// 0 is not a valid value according to the docs for MspaCoveredTransaction. Instead of erroring,
-	// return the value of the string, and let downstream processing handle if the value is 0.
-	p.MspaOptOutOptionMode, _ = r.ReadMspaNaYesNo()
-	p.MspaServiceProviderMode, _ = r.ReadMspaNaYesNo()
-
-	if len(segments) > 1 {
-		var gppSubsectionConsent *GppSubSection
-		gppSubsectionConsent, err = ParseGppSubSections(segments[1:])
-		if err != nil {
-			return p, err
-		}
-		p.Gpc = gppSubsectionConsent.Gpc
-	}
-
-	return p, r.Err
+	return parseMspaSection(UsMontanaSID, m.sectionValue)
 }
 
 func (m *MspaUsOR) ParseConsent() (GppParsedConsent, error) {
-	var segments = strings.Split(m.sectionValue, ".")
-
-	var b, err = base64.RawURLEncoding.DecodeString(segments[0])
-	if err != nil {
-		return nil, errors.Wrap(err, "parse usor consent string")
-	}
-
-	var r = NewConsentReader(b)
-
-	// This block of code directly describes the format of the payload.
-	// The spec for the consent string can be found here:
-	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/OR
-	var p = &MspaParsedConsent{}
-	p.Version, _ = r.ReadInt(6)
-
-	if p.Version != 1 {
-		return nil, errors.New("unsupported version: " + fmt.Sprint(p.Version))
-	}
-
-	// validate the length of the bit string for v1
-	if p.Version == 1 && r.Size() != MspaUsOrV1StringLength {
-		return nil, errors.New("invalid consent string length for v1")
-	}
-
-	p.SharingNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOutNotice, _ = r.ReadMspaNotice()
-	p.TargetedAdvertisingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOut, _ = r.ReadMspaOptOut()
-	p.TargetedAdvertisingOptOut, _ = r.ReadMspaOptOut()
-	p.SensitiveDataProcessingConsents, _ = r.ReadMspaBitfieldConsent(11)
-	p.KnownChildSensitiveDataConsents, _ = r.ReadMspaBitfieldConsent(3)
-	p.PersonalDataConsents, _ = r.ReadMspaConsent()
-	p.MspaCoveredTransaction, _ = r.ReadMspaNaYesNo()
-	// 0 is not a valid value according to the docs for MspaCoveredTransaction. Instead of erroring,
-	// return the value of the string, and let downstream processing handle if the value is 0.
-	p.MspaOptOutOptionMode, _ = r.ReadMspaNaYesNo()
-	p.MspaServiceProviderMode, _ = r.ReadMspaNaYesNo()
-
-	if len(segments) > 1 {
-		var gppSubsectionConsent *GppSubSection
-		gppSubsectionConsent, err = ParseGppSubSections(segments[1:])
-		if err != nil {
-			return p, err
-		}
-		p.Gpc = gppSubsectionConsent.Gpc
-	}
-
-	return p, r.Err
+	return parseMspaSection(UsOregonSID, m.sectionValue)
 }
 
 func (m *MspaUsTX) ParseConsent() (GppParsedConsent, error) {
-	var segments = strings.Split(m.sectionValue, ".")
-
-	var b, err = base64.RawURLEncoding.DecodeString(segments[0])
-	if err != nil {
-		return nil, errors.Wrap(err, "parse ustx consent string")
-	}
-
-	var r = NewConsentReader(b)
-
-	// This block of code directly describes the format of the payload.
-	// The spec for the consent string can be found here:
-	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/TX
-	var p = &MspaParsedConsent{}
-	p.Version, _ = r.ReadInt(6)
-
-	if p.Version != 1 {
-		return nil, errors.New("unsupported version: " + fmt.Sprint(p.Version))
-	}
-
-	// validate the length of the bit string for v1
-	if p.Version == 1 && r.Size() != MspaUsTxV1StringLength {
-		return nil, errors.New("invalid consent string length for v1")
-	}
-
-	p.SharingNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOutNotice, _ = r.ReadMspaNotice()
-	p.TargetedAdvertisingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOut, _ = r.ReadMspaOptOut()
-	p.TargetedAdvertisingOptOut, _ = r.ReadMspaOptOut()
-	p.SensitiveDataProcessingConsents, _ = r.ReadMspaBitfieldConsent(8)
-	p.KnownChildSensitiveDataConsents, _ = r.ReadMspaBitfieldConsent(1)
-	p.PersonalDataConsents, _ = r.ReadMspaConsent()
-	p.MspaCoveredTransaction, _ = r.ReadMspaNaYesNo()
-	// 0 is not a valid value according to the docs for MspaCoveredTransaction. Instead of erroring,
-	// return the value of the string, and let downstream processing handle if the value is 0.
-	p.MspaOptOutOptionMode, _ = r.ReadMspaNaYesNo()
-	p.MspaServiceProviderMode, _ = r.ReadMspaNaYesNo()
-
-	if len(segments) > 1 {
-		var gppSubsectionConsent *GppSubSection
-		gppSubsectionConsent, err = ParseGppSubSections(segments[1:])
-		if err != nil {
-			return p, err
-		}
-		p.Gpc = gppSubsectionConsent.Gpc
-	}
-
-	return p, r.Err
+	return parseMspaSection(UsTexasSID, m.sectionValue)
 }
 
 func (m *MspaUsDE) ParseConsent() (GppParsedConsent, error) {
-	var segments = strings.Split(m.sectionValue, ".")
-
-	var b, err = base64.RawURLEncoding.DecodeString(segments[0])
-	if err != nil {
-		return nil, errors.Wrap(err, "parse usde consent string")
-	}
-
-	var r = NewConsentReader(b)
-
-	// This block of code directly describes the format of the payload.
-	// The spec for the consent string can be found here:
-	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/DE
-	var p = &MspaParsedConsent{}
-	p.Version, _ = r.ReadInt(6)
-
-	if p.Version != 1 {
-		return nil, errors.New("unsupported version: " + fmt.Sprint(p.Version))
-	}
-
-	// validate the length of the bit string for v1
-	if p.Version == 1 && r.Size() != MspaUsDeV1StringLength {
-		return nil, errors.New("invalid consent string length for v1")
-	}
-
-	p.SharingNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOutNotice, _ = r.ReadMspaNotice()
-	p.TargetedAdvertisingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOut, _ = r.ReadMspaOptOut()
-	p.TargetedAdvertisingOptOut, _ = r.ReadMspaOptOut()
-	p.SensitiveDataProcessingConsents, _ = r.ReadMspaBitfieldConsent(9)
-	p.KnownChildSensitiveDataConsents, _ = r.ReadMspaBitfieldConsent(5)
-	p.PersonalDataConsents, _ = r.ReadMspaConsent()
-	p.MspaCoveredTransaction, _ = r.ReadMspaNaYesNo()
-	// 0 is not a valid value according to the docs for MspaCoveredTransaction. Instead of erroring,
-	// return the value of the string, and let downstream processing handle if the value is 0.
-	p.MspaOptOutOptionMode, _ = r.ReadMspaNaYesNo()
-	p.MspaServiceProviderMode, _ = r.ReadMspaNaYesNo()
-
-	if len(segments) > 1 {
-		var gppSubsectionConsent *GppSubSection
-		gppSubsectionConsent, err = ParseGppSubSections(segments[1:])
-		if err != nil {
-			return p, err
-		}
-		p.Gpc = gppSubsectionConsent.Gpc
-	}
-
-	return p, r.Err
+	return parseMspaSection(UsDelawareSID, m.sectionValue)
 }
 
-// Fix Iowa implementation
 func (m *MspaUsIA) ParseConsent() (GppParsedConsent, error) {
-	var segments = strings.Split(m.sectionValue, ".")
-
-	var b, err = base64.RawURLEncoding.DecodeString(segments[0])
-	if err != nil {
-		return nil, errors.Wrap(err, "parse usia consent string")
-	}
-
-	var r = NewConsentReader(b)
-
-	// This block of code directly describes the format of the payload.
-	// The spec for the consent string can be found here:
-	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/IA
-	var p = &MspaParsedConsent{}
-	p.Version, _ = r.ReadInt(6)
-
-	if p.Version != 1 {
-		return nil, errors.New("unsupported version: " + fmt.Sprint(p.Version))
-	}
-
-	// validate the length of the bit string for v1
-	if p.Version == 1 && r.Size() != MspaUsIaV1StringLength {
-		return nil, errors.New("invalid consent string length for v1")
-	}
-
-	p.SharingNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOutNotice, _ = r.ReadMspaNotice()
-	p.TargetedAdvertisingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SensitiveDataProcessingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOut, _ = r.ReadMspaOptOut()
-	p.TargetedAdvertisingOptOut, _ = r.ReadMspaOptOut()
-	p.SensitiveDataProcessingOptOuts, _ = r.ReadMspaBitfieldOptOut(8)
-	p.KnownChildSensitiveDataConsents, _ = r.ReadMspaBitfieldConsent(1)
-	p.MspaCoveredTransaction, _ = r.ReadMspaNaYesNo()
-	p.MspaOptOutOptionMode, _ = r.ReadMspaNaYesNo()
-	p.MspaServiceProviderMode, _ = r.ReadMspaNaYesNo()
-
-	if len(segments) > 1 {
-		var gppSubsectionConsent *GppSubSection
-		gppSubsectionConsent, err = ParseGppSubSections(segments[1:])
-		if err != nil {
-			return p, err
-		}
-		p.Gpc = gppSubsectionConsent.Gpc
-	}
-
-	return p, r.Err
+	return parseMspaSection(UsIowaSID, m.sectionValue)
 }
 
 func (m *MspaUsNE) ParseConsent() (GppParsedConsent, error) {
-	var segments = strings.Split(m.sectionValue, ".")
-
-	var b, err = base64.RawURLEncoding.DecodeString(segments[0])
-	if err != nil {
-		return nil, errors.Wrap(err, "parse usne consent string")
-	}
-
-	var r = NewConsentReader(b)
-
-	// This block of code directly describes the format of the payload.
-	// The spec for the consent string can be found here:
-	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/NE
-	var p = &MspaParsedConsent{}
-	p.Version, _ = r.ReadInt(6)
-
-	if p.Version != 1 {
-		return nil, errors.New("unsupported version: " + fmt.Sprint(p.Version))
-	}
-
-	// validate the length of the bit string for v1
-	if p.Version == 1 && r.Size() != MspaUsNeV1StringLength {
-		return nil, errors.New("invalid consent string length for v1")
-	}
-
-	p.SharingNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOutNotice, _ = r.ReadMspaNotice()
-	p.TargetedAdvertisingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOut, _ = r.ReadMspaOptOut()
-	p.TargetedAdvertisingOptOut, _ = r.ReadMspaOptOut()
-	p.SensitiveDataProcessingConsents, _ = r.ReadMspaBitfieldConsent(8)
-	p.KnownChildSensitiveDataConsents, _ = r.ReadMspaBitfieldConsent(1)
-	p.PersonalDataConsents, _ = r.ReadMspaConsent()
-	p.MspaCoveredTransaction, _ = r.ReadMspaNaYesNo()
-	p.MspaOptOutOptionMode, _ = r.ReadMspaNaYesNo()
-	p.MspaServiceProviderMode, _ = r.ReadMspaNaYesNo()
-
-	if len(segments) > 1 {
-		var gppSubsectionConsent *GppSubSection
-		gppSubsectionConsent, err = ParseGppSubSections(segments[1:])
-		if err != nil {
-			return p, err
-		}
-		p.Gpc = gppSubsectionConsent.Gpc
-	}
-
-	return p, r.Err
+	return parseMspaSection(UsNebraskaSID, m.sectionValue)
 }
 
 func (m *MspaUsNH) ParseConsent() (GppParsedConsent, error) {
-	var segments = strings.Split(m.sectionValue, ".")
-
-	var b, err = base64.RawURLEncoding.DecodeString(segments[0])
-	if err != nil {
-		return nil, errors.Wrap(err, "parse usnh consent string")
-	}
-
-	var r = NewConsentReader(b)
-
-	// This block of code directly describes the format of the payload.
-	// The spec for the consent string can be found here:
-	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/NH
-	var p = &MspaParsedConsent{}
-	p.Version, _ = r.ReadInt(6)
-
-	if p.Version != 1 {
-		return nil, errors.New("unsupported version: " + fmt.Sprint(p.Version))
-	}
-
-	// validate the length of the bit string for v1
-	if p.Version == 1 && r.Size() != MspaUsNhV1StringLength {
-		return nil, errors.New("invalid consent string length for v1")
-	}
-
-	p.SharingNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOutNotice, _ = r.ReadMspaNotice()
-	p.TargetedAdvertisingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOut, _ = r.ReadMspaOptOut()
-	p.TargetedAdvertisingOptOut, _ = r.ReadMspaOptOut()
-	p.SensitiveDataProcessingConsents, _ = r.ReadMspaBitfieldConsent(8)
-	p.KnownChildSensitiveDataConsents, _ = r.ReadMspaBitfieldConsent(3)
-	p.PersonalDataConsents, _ = r.ReadMspaConsent()
-	p.MspaCoveredTransaction, _ = r.ReadMspaNaYesNo()
-	p.MspaOptOutOptionMode, _ = r.ReadMspaNaYesNo()
-	p.MspaServiceProviderMode, _ = r.ReadMspaNaYesNo()
-
-	if len(segments) > 1 {
-		var gppSubsectionConsent *GppSubSection
-		gppSubsectionConsent, err = ParseGppSubSections(segments[1:])
-		if err != nil {
-			return p, err
-		}
-		p.Gpc = gppSubsectionConsent.Gpc
-	}
-
-	return p, r.Err
+	return parseMspaSection(UsNewHampshireSID, m.sectionValue)
 }
 
 func (m *MspaUsNJ) ParseConsent() (GppParsedConsent, error) {
-	var segments = strings.Split(m.sectionValue, ".")
-
-	var b, err = base64.RawURLEncoding.DecodeString(segments[0])
-	if err != nil {
-		return nil, errors.Wrap(err, "parse usnj consent string")
-	}
-
-	var r = NewConsentReader(b)
-
-	// This block of code directly describes the format of the payload.
-	// The spec for the consent string can be found here:
-	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/NJ
-	var p = &MspaParsedConsent{}
-	p.Version, _ = r.ReadInt(6)
-
-	if p.Version != 1 {
-		return nil, errors.New("unsupported version: " + fmt.Sprint(p.Version))
-	}
-
-	// validate the length of the bit string for v1
-	if p.Version == 1 && r.Size() != MspaUsNjV1StringLength {
-		return nil, errors.New("invalid consent string length for v1")
-	}
-
-	p.SharingNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOutNotice, _ = r.ReadMspaNotice()
-	p.TargetedAdvertisingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOut, _ = r.ReadMspaOptOut()
-	p.TargetedAdvertisingOptOut, _ = r.ReadMspaOptOut()
-	p.SensitiveDataProcessingConsents, _ = r.ReadMspaBitfieldConsent(10)
-	p.KnownChildSensitiveDataConsents, _ = r.ReadMspaBitfieldConsent(5)
-	p.PersonalDataConsents, _ = r.ReadMspaConsent()
-	p.MspaCoveredTransaction, _ = r.ReadMspaNaYesNo()
-	p.MspaOptOutOptionMode, _ = r.ReadMspaNaYesNo()
-	p.MspaServiceProviderMode, _ = r.ReadMspaNaYesNo()
-
-	if len(segments) > 1 {
-		var gppSubsectionConsent *GppSubSection
-		gppSubsectionConsent, err = ParseGppSubSections(segments[1:])
-		if err != nil {
-			return p, err
-		}
-		p.Gpc = gppSubsectionConsent.Gpc
-	}
-
-	return p, r.Err
+	return parseMspaSection(UsNewJerseySID, m.sectionValue)
 }
 
 func (m *MspaUsTN) ParseConsent() (GppParsedConsent, error) {
-	var segments = strings.Split(m.sectionValue, ".")
-
-	var b, err = base64.RawURLEncoding.DecodeString(segments[0])
-	if err != nil {
-		return nil, errors.Wrap(err, "parse ustn consent string")
-	}
-
-	var r = NewConsentReader(b)
-
-	// This block of code directly describes the format of the payload.
-	// The spec for the consent string can be found here:
-	// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/tree/main/Sections/US-States/TN
-	var p = &MspaParsedConsent{}
-	p.Version, _ = r.ReadInt(6)
-
-	if p.Version != 1 {
-		return nil, errors.New("unsupported version: " + fmt.Sprint(p.Version))
-	}
-
-	// validate the length of the bit string for v1
-	if p.Version == 1 && r.Size() != MspaUsTnV1StringLength {
-		return nil, errors.New("invalid consent string length for v1")
-	}
-
-	p.SharingNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOutNotice, _ = r.ReadMspaNotice()
-	p.TargetedAdvertisingOptOutNotice, _ = r.ReadMspaNotice()
-	p.SaleOptOut, _ = r.ReadMspaOptOut()
-	p.TargetedAdvertisingOptOut, _ = r.ReadMspaOptOut()
-	p.SensitiveDataProcessingConsents, _ = r.ReadMspaBitfieldConsent(8)
-	p.KnownChildSensitiveDataConsents, _ = r.ReadMspaBitfieldConsent(1)
-	p.PersonalDataConsents, _ = r.ReadMspaConsent()
-	p.MspaOptOutOptionMode, _ = r.ReadMspaNaYesNo()
-	p.MspaServiceProviderMode, _ = r.ReadMspaNaYesNo()
+	return parseMspaSection(UsTennesseeSID, m.sectionValue)
+}
 
-	if len(segments) > 1 {
-		var gppSubsectionConsent *GppSubSection
-		gppSubsectionConsent, err = ParseGppSubSections(segments[1:])
-		if err != nil {
-			return p, err
-		}
-		p.Gpc = gppSubsectionConsent.Gpc
+// MspaSectionVersions returns the supported versions of the MSPA section with Section ID sid,
+// in ascending order. No versions are returned if the section is not supported.
+func MspaSectionVersions(sid int) []int {
+	var versions = make([]int, 0, len(mspaSections[sid].versions))
+	for v := range mspaSections[sid].versions {
+		versions = append(versions, v)
 	}
-
-	return p, r.Err
+	sort.Ints(versions)
+	return versions
 }
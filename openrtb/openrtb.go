@@ -0,0 +1,245 @@
+// Package openrtb extracts and validates the privacy signals of an OpenRTB 2.6 bid request.
+//
+// Only the fields which carry privacy signals are decoded, so no OpenRTB SDK is required:
+//
+//	regs.gdpr, user.consent                                      TCF v2
+//	regs.gpp, regs.gpp_sid                                       GPP
+//	regs.us_privacy                                              US Privacy
+//	user.ext.consented_providers_settings.consented_providers    Google Additional Consent
+package openrtb
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+// BidRequest is the subset of an OpenRTB 2.6 bid request which carries privacy signals.
+type BidRequest struct {
+	Regs *Regs `json:"regs,omitempty"`
+	User *User `json:"user,omitempty"`
+}
+
+// Regs is the subset of the OpenRTB 2.6 Regs object which carries privacy signals.
+type Regs struct {
+	GDPR      *int   `json:"gdpr,omitempty"`
+	USPrivacy string `json:"us_privacy,omitempty"`
+	GPP       string `json:"gpp,omitempty"`
+	GPPSID    []int  `json:"gpp_sid,omitempty"`
+}
+
+// User is the subset of the OpenRTB 2.6 User object which carries privacy signals.
+type User struct {
+	Consent string   `json:"consent,omitempty"`
+	Ext     *UserExt `json:"ext,omitempty"`
+}
+
+// UserExt is the subset of user.ext which carries privacy signals.
+type UserExt struct {
+	ConsentedProvidersSettings *ConsentedProvidersSettings `json:"consented_providers_settings,omitempty"`
+}
+
+// ConsentedProvidersSettings holds the Google Additional Consent providers the user consented to.
+type ConsentedProvidersSettings struct {
+	ConsentedProviders []int `json:"consented_providers,omitempty"`
+}
+
+// IssueCode is an enum type of the inconsistencies between the privacy signals of a bid request.
+type IssueCode int
+
+const (
+	UnknownIssue IssueCode = iota
+	// regs.gdpr is neither 0 nor 1.
+	IssueInvalidGDPR
+	// regs.gdpr is 1, but user.consent is empty.
+	IssueMissingConsent
+	// regs.gpp_sid is set, but regs.gpp is empty.
+	IssueGPPSIDWithoutGPP
+	// regs.gpp is set, but regs.gpp_sid is empty.
+	IssueGPPWithoutGPPSID
+	// regs.gpp_sid lists sections which are absent from the GPP header.
+	IssueGPPSIDNotInHeader
+	// A supported section listed in the GPP header could not be parsed.
+	IssueGPPSectionUnparsed
+)
+
+// Issue is a single inconsistency between the privacy signals of a bid request.
+type Issue struct {
+	Code IssueCode
+	// The GPP Section IDs the issue applies to, if any.
+	SIDs []int
+	// The value of regs.gdpr, set for IssueInvalidGDPR.
+	GDPR int
+}
+
+// String returns a human readable description of the issue, e.g. "regs.gdpr is 1 but user.consent is empty".
+func (i Issue) String() string {
+	switch i.Code {
+	case IssueInvalidGDPR:
+		return fmt.Sprintf("regs.gdpr is %d, not 0 or 1", i.GDPR)
+	case IssueMissingConsent:
+		return "regs.gdpr is 1 but user.consent is empty"
+	case IssueGPPSIDWithoutGPP:
+		return "regs.gpp_sid is set but regs.gpp is empty"
+	case IssueGPPWithoutGPPSID:
+		return "regs.gpp is set but regs.gpp_sid is empty"
+	case IssueGPPSIDNotInHeader:
+		return fmt.Sprintf("regs.gpp_sid sections %s are absent from the gpp header", formatSIDs(i.SIDs))
+	case IssueGPPSectionUnparsed:
+		return fmt.Sprintf("gpp sections %s could not be parsed", formatSIDs(i.SIDs))
+	default:
+		return fmt.Sprintf("unknown issue %d", i.Code)
+	}
+}
+
+func formatSIDs(sids []int) string {
+	var s = make([]string, len(sids))
+	for i, sid := range sids {
+		s[i] = fmt.Sprint(sid)
+	}
+	return "[" + strings.Join(s, ", ") + "]"
+}
+
+// PrivacyContext holds the privacy signals of a bid request. Each signal holds the raw string as received,
+// and, if it was present, its parsed value or the error from parsing it.
+type PrivacyContext struct {
+	// GDPRApplies is true if regs.gdpr is 1, and nil if regs.gdpr is absent or invalid.
+	GDPRApplies *bool
+
+	TCString string
+	TCF      *iabconsent.V2ParsedConsent
+	TCFErr   error
+
+	GPPString string
+	GPP       *iabconsent.GppConsent
+	GPPErr    error
+	// GPPSIDs are the sections of regs.gpp_sid, which apply to the request. If set, GPP only holds
+	// these sections.
+	GPPSIDs []int
+
+	USPrivacyString string
+	USPrivacy       *iabconsent.UsPrivacyParsedConsent
+	USPrivacyErr    error
+
+	// ConsentedProviders are the Google Additional Consent providers of user.ext.
+	ConsentedProviders []int
+
+	// Issues are the inconsistencies between the signals, in the order they were found.
+	Issues []Issue
+}
+
+// Err returns the first error from parsing a signal, or nil if every signal that was sent is valid.
+// Inconsistencies between signals are reported in Issues instead.
+func (p *PrivacyContext) Err() error {
+	switch {
+	case p.TCFErr != nil:
+		return errors.Wrap(p.TCFErr, "parse user.consent")
+	case p.GPPErr != nil:
+		return errors.Wrap(p.GPPErr, "parse regs.gpp")
+	case p.USPrivacyErr != nil:
+		return errors.Wrap(p.USPrivacyErr, "parse regs.us_privacy")
+	}
+	return nil
+}
+
+// ParseBidRequest decodes the privacy signals of the bid request JSON b, and returns its PrivacyContext.
+// An error is only returned if b is not a valid bid request.
+func ParseBidRequest(b []byte) (*PrivacyContext, error) {
+	var req BidRequest
+	if err := json.Unmarshal(b, &req); err != nil {
+		return nil, errors.Wrap(err, "unmarshal bid request")
+	}
+	return Extract(&req), nil
+}
+
+// Extract parses the privacy signals of req, and checks them for inconsistencies.
+func Extract(req *BidRequest) *PrivacyContext {
+	var p = &PrivacyContext{}
+	var regs = req.Regs
+	if regs == nil {
+		regs = &Regs{}
+	}
+	var user = req.User
+	if user == nil {
+		user = &User{}
+	}
+
+	if regs.GDPR != nil {
+		switch *regs.GDPR {
+		case 0, 1:
+			var applies = *regs.GDPR == 1
+			p.GDPRApplies = &applies
+		default:
+			p.Issues = append(p.Issues, Issue{Code: IssueInvalidGDPR, GDPR: *regs.GDPR})
+		}
+	}
+	if p.TCString = strings.TrimSpace(user.Consent); p.TCString != "" {
+		p.TCF, p.TCFErr = iabconsent.ParseV2(p.TCString)
+	} else if p.GDPRApplies != nil && *p.GDPRApplies {
+		p.Issues = append(p.Issues, Issue{Code: IssueMissingConsent})
+	}
+
+	p.GPPSIDs = regs.GPPSID
+	if p.GPPString = strings.TrimSpace(regs.GPP); p.GPPString != "" {
+		p.Issues = append(p.Issues, p.parseGPP()...)
+	} else if len(p.GPPSIDs) != 0 {
+		p.Issues = append(p.Issues, Issue{Code: IssueGPPSIDWithoutGPP})
+	}
+
+	if p.USPrivacyString = strings.TrimSpace(regs.USPrivacy); p.USPrivacyString != "" {
+		p.USPrivacy, p.USPrivacyErr = iabconsent.ParseUsPrivacy(p.USPrivacyString)
+	}
+
+	if user.Ext != nil && user.Ext.ConsentedProvidersSettings != nil {
+		p.ConsentedProviders = user.Ext.ConsentedProvidersSettings.ConsentedProviders
+	}
+	return p
+}
+
+// parseGPP parses p.GPPString. If p.GPPSIDs is set, only the sections it lists are parsed, as other
+// sections do not apply to the request. It returns the inconsistencies found between the two.
+func (p *PrivacyContext) parseGPP() []Issue {
+	if len(p.GPPSIDs) == 0 {
+		var issues = []Issue{{Code: IssueGPPWithoutGPPSID}}
+		if p.GPP, p.GPPErr = iabconsent.ParseGppConsent(p.GPPString); p.GPPErr != nil {
+			return issues
+		}
+		return append(issues, unparsedIssues(p.GPP, p.GPP.Header.Sections)...)
+	}
+
+	var missing []int
+	if p.GPP, missing, p.GPPErr = iabconsent.ParseApplicableGppConsent(p.GPPString, p.GPPSIDs); p.GPPErr != nil {
+		return nil
+	}
+	var issues []Issue
+	if len(missing) != 0 {
+		sort.Ints(missing)
+		issues = append(issues, Issue{Code: IssueGPPSIDNotInHeader, SIDs: missing})
+	}
+	return append(issues, unparsedIssues(p.GPP, p.GPPSIDs)...)
+}
+
+// unparsedIssues returns an issue listing the supported sections of sids which are in the header of g,
+// but could not be parsed.
+func unparsedIssues(g *iabconsent.GppConsent, sids []int) []Issue {
+	var inHeader = make(map[int]bool, len(g.Header.Sections))
+	for _, sid := range g.Header.Sections {
+		inHeader[sid] = true
+	}
+	var unparsed []int
+	for _, sid := range sids {
+		if inHeader[sid] && iabconsent.GppSectionName(sid) != "" && g.Section(sid) == nil {
+			unparsed = append(unparsed, sid)
+		}
+	}
+	if len(unparsed) == 0 {
+		return nil
+	}
+	sort.Ints(unparsed)
+	return []Issue{{Code: IssueGPPSectionUnparsed, SIDs: unparsed}}
+}
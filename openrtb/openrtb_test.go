@@ -0,0 +1,118 @@
+package openrtb_test
+
+import (
+	"testing"
+
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+	"github.com/LiveRamp/iabconsent/openrtb"
+)
+
+func Test(t *testing.T) { check.TestingT(t) }
+
+type OpenRTBSuite struct{}
+
+var _ = check.Suite(&OpenRTBSuite{})
+
+func (s *OpenRTBSuite) TestParseBidRequest(c *check.C) {
+	var p, err = openrtb.ParseBidRequest([]byte(`{
+		"id": "1",
+		"imp": [{"id": "1"}],
+		"regs": {"gdpr": 1, "gpp": "DBACLMA~BVVqAAEABCA.YA~BVoYYYI", "gpp_sid": [7], "us_privacy": "1YNN"},
+		"user": {
+			"consent": "COvzTO5OvzTO5BRAAAENAPCoALIAADgAAAAAAewAwABAAlAB6ABBFAAA",
+			"ext": {"consented_providers_settings": {"consented_providers": [39, 4631]}}
+		}
+	}`))
+	c.Assert(err, check.IsNil)
+	c.Check(p.Err(), check.IsNil)
+	c.Check(p.Issues, check.HasLen, 0)
+
+	c.Assert(p.GDPRApplies, check.NotNil)
+	c.Check(*p.GDPRApplies, check.Equals, true)
+	c.Assert(p.TCF, check.NotNil)
+	c.Check(p.TCF.CMPID, check.Equals, 81)
+	c.Check(p.GPP.UsNational().Gpc, check.Equals, true)
+	// Sections not listed in regs.gpp_sid do not apply, so are not parsed.
+	c.Check(p.GPP.UsVirginia(), check.IsNil)
+	c.Check(p.GPP.Header.Sections, check.DeepEquals, []int{7, 9})
+	c.Check(p.GPPSIDs, check.DeepEquals, []int{7})
+	c.Check(p.USPrivacy.OptOutSale, check.Equals, iabconsent.MspaNo)
+	c.Check(p.ConsentedProviders, check.DeepEquals, []int{39, 4631})
+}
+
+func (s *OpenRTBSuite) TestEmptyBidRequest(c *check.C) {
+	var p, err = openrtb.ParseBidRequest([]byte(`{"id": "1"}`))
+	c.Assert(err, check.IsNil)
+	c.Check(p, check.DeepEquals, &openrtb.PrivacyContext{})
+
+	_, err = openrtb.ParseBidRequest([]byte(`{"regs": {"gdpr": "1"}}`))
+	c.Check(err, check.ErrorMatches, "unmarshal bid request: .*")
+}
+
+func (s *OpenRTBSuite) TestIssues(c *check.C) {
+	var tcs = []struct {
+		req    string
+		issues []string
+	}{
+		{
+			req:    `{"regs": {"gdpr": 1}, "user": {"consent": ""}}`,
+			issues: []string{"regs.gdpr is 1 but user.consent is empty"},
+		},
+		{
+			req:    `{"regs": {"gdpr": 2}}`,
+			issues: []string{"regs.gdpr is 2, not 0 or 1"},
+		},
+		{
+			req:    `{"regs": {"gdpr": 0}}`,
+			issues: nil,
+		},
+		{
+			req:    `{"regs": {"gpp_sid": [7]}}`,
+			issues: []string{"regs.gpp_sid is set but regs.gpp is empty"},
+		},
+		{
+			req:    `{"regs": {"gpp": "DBABLA~BVVqAAEABCA"}}`,
+			issues: []string{"regs.gpp is set but regs.gpp_sid is empty"},
+		},
+		{
+			req:    `{"regs": {"gpp": "DBABLA~BVVqAAEABCA", "gpp_sid": [8, 7, 2]}}`,
+			issues: []string{"regs.gpp_sid sections [2, 8] are absent from the gpp header"},
+		},
+		{
+			// The usnat section is too short, so it is dropped by ParseGppConsent.
+			req:    `{"regs": {"gpp": "DBABLA~BVVqAAEABC", "gpp_sid": [7]}}`,
+			issues: []string{"gpp sections [7] could not be parsed"},
+		},
+		{
+			// Sections which do not apply are not parsed, so are not reported.
+			req:    `{"regs": {"gpp": "DBACLMA~BVVqAAEABC~BVoYYYI", "gpp_sid": [9]}}`,
+			issues: nil,
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.req)
+		var p, err = openrtb.ParseBidRequest([]byte(tc.req))
+		c.Assert(err, check.IsNil)
+		var issues []string
+		for _, i := range p.Issues {
+			issues = append(issues, i.String())
+		}
+		c.Check(issues, check.DeepEquals, tc.issues)
+	}
+}
+
+func (s *OpenRTBSuite) TestParseErrors(c *check.C) {
+	var p = openrtb.Extract(&openrtb.BidRequest{
+		Regs: &openrtb.Regs{GPP: "DBABL", GPPSID: []int{7}, USPrivacy: "1YNX"},
+		User: &openrtb.User{Consent: "BONMj34ONMj34ABACDENALqAAAAAplY"},
+	})
+	c.Check(p.TCFErr, check.ErrorMatches, "non-v2 string passed to v2 parse method")
+	c.Check(p.GPPErr, check.ErrorMatches, "not enough gpp segments")
+	c.Check(p.USPrivacyErr, check.NotNil)
+	c.Check(p.Err(), check.ErrorMatches, "parse user.consent: non-v2 string passed to v2 parse method")
+	c.Check(p.Issues, check.HasLen, 0)
+
+	c.Check(openrtb.Issue{Code: 100}.String(), check.Equals, "unknown issue 100")
+}
@@ -23,11 +23,21 @@ var PrecompiledFibonacci = map[int]int{0: 0, 1: 1, 2: 1, 3: 2, 4: 3, 5: 5, 6: 8,
 // functionality on top of bits.Reader.
 type ConsentReader struct {
 	*bits.Reader
+	// opts limits the values read, see ParseOptions.
+	opts ParseOptions
+	// rangeEntries counts the range entries read, to enforce opts.MaxRangeEntries.
+	rangeEntries int
 }
 
-// NewConsentReader returns a new ConsentReader backed by src.
+// NewConsentReader returns a new ConsentReader backed by src, without limits.
 func NewConsentReader(src []byte) *ConsentReader {
-	return &ConsentReader{bits.NewReader(bits.NewBitmap(src))}
+	return &ConsentReader{Reader: bits.NewReader(bits.NewBitmap(src))}
+}
+
+// NewConsentReaderWithOptions returns a new ConsentReader backed by src, which fails reads exceeding
+// the limits of opts with a *LimitExceededError.
+func NewConsentReaderWithOptions(src []byte, opts ParseOptions) *ConsentReader {
+	return &ConsentReader{Reader: bits.NewReader(bits.NewBitmap(src)), opts: opts}
 }
 
 // ReadInt reads the next n bits and converts them to an int.
@@ -48,7 +58,7 @@ func FibonacciIndexValue(index int) (int, error) {
 	if index > 92 {
 		return 0, errors.New("fibonacci: index greater than max of 92")
 	}
-	if index <= len(PrecompiledFibonacci) {
+	if index < len(PrecompiledFibonacci) {
 		return PrecompiledFibonacci[index], nil
 	} else {
 		return newFib(index), nil
@@ -159,7 +169,12 @@ func (r *ConsentReader) ReadNBitField(n, l uint) (map[int]int, error) {
 
 // ReadRangeEntries reads n range entries of 1 + 16 or 32 bits.
 func (r *ConsentReader) ReadRangeEntries(n uint) ([]*RangeEntry, error) {
-	var ret = make([]*RangeEntry, 0, n)
+	r.rangeEntries += int(n)
+	if err := r.checkLimit("range entries", r.rangeEntries, r.opts.MaxRangeEntries); err != nil {
+		return nil, err
+	}
+	// Each entry is at least 17 bits, so never allocate more entries than the unread bits can hold.
+	var ret = make([]*RangeEntry, 0, r.preallocated(n, 17))
 	var err error
 	for i := uint(0); i < n; i++ {
 		var isRange bool
@@ -177,6 +192,9 @@ func (r *ConsentReader) ReadRangeEntries(n uint) ([]*RangeEntry, error) {
 		} else {
 			end = start
 		}
+		if err = r.checkLimit("vendor id", end, r.opts.MaxVendorID); err != nil {
+			return nil, err
+		}
 		ret = append(ret, &RangeEntry{StartVendorID: start, EndVendorID: end})
 	}
 	return ret, nil
@@ -218,6 +236,9 @@ func (r *ConsentReader) ReadFibonacciRange() ([]int, error) {
 			if groupLength, err = r.ReadFibonacciInt(); err != nil {
 				return nil, errors.WithMessage(err, "fibonacci range length")
 			}
+			if err = r.checkLimit("gpp sections", len(ret)+groupLength+1, r.opts.MaxGppSections); err != nil {
+				return nil, err
+			}
 			// Add offset to last seen value as starting point of range.
 			lastSeen += offset
 			// Keep appending integers until we reach the group length.
@@ -226,6 +247,9 @@ func (r *ConsentReader) ReadFibonacciRange() ([]int, error) {
 				lastSeen++
 			}
 		} else {
+			if err = r.checkLimit("gpp sections", len(ret)+1, r.opts.MaxGppSections); err != nil {
+				return nil, err
+			}
 			// If a single ID, add value to last seen value.
 			ret = append(ret, lastSeen+offset)
 		}
@@ -235,7 +259,11 @@ func (r *ConsentReader) ReadFibonacciRange() ([]int, error) {
 
 // ReadPubRestrictionEntries reads n publisher restriction entries.
 func (r *ConsentReader) ReadPubRestrictionEntries(n uint) ([]*PubRestrictionEntry, error) {
-	var ret = make([]*PubRestrictionEntry, 0, n)
+	if err := r.checkLimit("pub restrictions", int(n), r.opts.MaxPubRestrictions); err != nil {
+		return nil, err
+	}
+	// Each entry is at least 20 bits, so never allocate more entries than the unread bits can hold.
+	var ret = make([]*PubRestrictionEntry, 0, r.preallocated(n, 20))
 	var err error
 
 	for i := uint(0); i < n; i++ {
@@ -289,6 +317,9 @@ func (r *ConsentReader) ReadVendors(t SegmentType) (*OOBVendorList, error) {
 	if v.MaxVendorID, err = r.ReadInt(16); err != nil {
 		return nil, errors.WithMessage(err, "reading vendor ID")
 	}
+	if err = r.checkLimit("vendor id", v.MaxVendorID, r.opts.MaxVendorID); err != nil {
+		return nil, err
+	}
 	if v.IsRangeEncoding, err = r.ReadBool(); err != nil {
 		return nil, errors.WithMessage(err, "reading is range flag")
 	}
@@ -350,16 +381,35 @@ func Parse(s string) (*ParsedConsent, error) {
 // string and returns a ParsedConsent with its fields populated with
 // the values stored in the string.
 //
+// ParseV1 enforces the limits of DefaultParseOptions, and fails with a
+// *LimitExceededError for strings which exceed them. Earlier releases parsed
+// strings of any size; use ParseV1WithOptions with ParseOptions{} to parse
+// trusted strings without limits.
+//
 // Example Usage:
 //
 //   var pc, err = iabconsent.ParseV1("BONJ5bvONJ5bvAMAPyFRAL7AAAAMhuqKklS-gAAAAAAAAAAAAAAAAAAAAAAAAAA")
 func ParseV1(s string) (*ParsedConsent, error) {
+	return ParseV1WithOptions(s, DefaultParseOptions)
+}
+
+// ParseV1WithOptions is ParseV1, but fails with a *LimitExceededError if s exceeds the limits of opts.
+func ParseV1WithOptions(s string, opts ParseOptions) (p *ParsedConsent, err error) {
+	defer recoverPanic(&err)
+	return parseV1(s, opts)
+}
+
+// parseV1 parses TCF v1.1 string s, within the limits of opts.
+func parseV1(s string, opts ParseOptions) (*ParsedConsent, error) {
+	if err := checkLimit("length", len(s), opts.MaxLength); err != nil {
+		return nil, err
+	}
 	var b, err = base64.RawURLEncoding.DecodeString(s)
 	if err != nil {
 		return nil, errors.Wrap(err, "parse v1 consent string")
 	}
 
-	var r = NewConsentReader(b)
+	var r = NewConsentReaderWithOptions(b, opts)
 
 	// This block of code directly describes the format of the payload.
 	var p = &ParsedConsent{}
@@ -376,6 +426,7 @@ func ParseV1(s string) (*ParsedConsent, error) {
 	p.VendorListVersion, _ = r.ReadInt(12)
 	p.PurposesAllowed, _ = r.ReadBitField(24)
 	p.MaxVendorID, _ = r.ReadInt(16)
+	r.checkLimit("vendor id", p.MaxVendorID, opts.MaxVendorID)
 
 	p.IsRangeEncoding, _ = r.ReadBool()
 	if p.IsRangeEncoding {
@@ -386,6 +437,10 @@ func ParseV1(s string) (*ParsedConsent, error) {
 		p.ConsentedVendors, _ = r.ReadBitField(uint(p.MaxVendorID))
 	}
 
+	// A consent which exceeds a limit was only partly read, so is not returned.
+	if _, ok := r.Err.(*LimitExceededError); ok {
+		return nil, r.Err
+	}
 	return p, r.Err
 }
 
@@ -393,10 +448,29 @@ func ParseV1(s string) (*ParsedConsent, error) {
 // string and returns a ParsedConsent with its fields populated with
 // the values stored in the string.
 //
+// ParseV2 enforces the limits of DefaultParseOptions, and fails with a
+// *LimitExceededError for strings which exceed them. Earlier releases parsed
+// strings of any size; use ParseV2WithOptions with ParseOptions{} to parse
+// trusted strings without limits.
+//
 // Example Usage:
 //
 //   var pc, err = iabconsent.ParseV2("COvzTO5OvzTO5BRAAAENAPCoALIAADgAAAAAAewAwABAAlAB6ABBFAAA")
 func ParseV2(s string) (*V2ParsedConsent, error) {
+	return ParseV2WithOptions(s, DefaultParseOptions)
+}
+
+// ParseV2WithOptions is ParseV2, but fails with a *LimitExceededError if s exceeds the limits of opts.
+func ParseV2WithOptions(s string, opts ParseOptions) (p *V2ParsedConsent, err error) {
+	defer recoverPanic(&err)
+	return parseV2(s, opts)
+}
+
+// parseV2 parses TCF v2 string s, within the limits of opts.
+func parseV2(s string, opts ParseOptions) (*V2ParsedConsent, error) {
+	if err := checkLimit("length", len(s), opts.MaxLength); err != nil {
+		return nil, err
+	}
 	var segments = strings.Split(s, ".")
 
 	var b, err = base64.RawURLEncoding.DecodeString(segments[0])
@@ -404,7 +478,7 @@ func ParseV2(s string) (*V2ParsedConsent, error) {
 		return nil, errors.Wrap(err, "parse v2 consent string")
 	}
 
-	var r = NewConsentReader(b)
+	var r = NewConsentReaderWithOptions(b, opts)
 
 	// This block of code directly describes the format of the payload.
 	// The spec for the consent string can be found here:
@@ -443,6 +517,7 @@ func ParseV2(s string) (*V2ParsedConsent, error) {
 	p.PublisherCC, _ = r.ReadString(2)
 
 	p.MaxConsentVendorID, _ = r.ReadInt(16)
+	r.checkLimit("vendor id", p.MaxConsentVendorID, opts.MaxVendorID)
 	p.IsConsentRangeEncoding, _ = r.ReadBool()
 	if p.IsConsentRangeEncoding {
 		p.NumConsentEntries, _ = r.ReadInt(12)
@@ -452,6 +527,7 @@ func ParseV2(s string) (*V2ParsedConsent, error) {
 	}
 
 	p.MaxInterestsVendorID, _ = r.ReadInt(16)
+	r.checkLimit("vendor id", p.MaxInterestsVendorID, opts.MaxVendorID)
 	p.IsInterestsRangeEncoding, _ = r.ReadBool()
 	if p.IsInterestsRangeEncoding {
 		p.NumInterestsEntries, _ = r.ReadInt(12)
@@ -465,12 +541,16 @@ func ParseV2(s string) (*V2ParsedConsent, error) {
 
 	// Parse remaining non-core string segments if they exist.
 	for i, segment := range segments[1:] {
+		// Stop at the first exceeded limit, as the reader of the previous segment is replaced.
+		if _, ok := r.Err.(*LimitExceededError); ok {
+			return nil, r.Err
+		}
 		b, err = base64.RawURLEncoding.DecodeString(segment)
 		if err != nil {
 			return p, errors.Wrap(err, "parsing segment "+strconv.Itoa(i+1))
 		}
 
-		r = NewConsentReader(b)
+		r = NewConsentReaderWithOptions(b, opts)
 		var st, _ = r.ReadSegmentType()
 		switch st {
 		case DisclosedVendors:
@@ -492,6 +572,15 @@ func ParseV2(s string) (*V2ParsedConsent, error) {
 			return p, errors.New("unrecognized segment type")
 		}
 	}
+	// A consent which exceeds a limit in its last segment was only partly read, so is not returned.
+	if _, ok := r.Err.(*LimitExceededError); ok {
+		return nil, r.Err
+	}
+	// From TCF v2.3 (Policy Version 5), the DisclosedVendors segment is mandatory for every TC String,
+	// as the vendor consent and legitimate interest signals only apply to disclosed vendors.
+	if p.requiresDisclosure() && p.OOBDisclosedVendors == nil {
+		return nil, errors.Errorf("TCF Policy Version %d string is missing the DisclosedVendors segment.", p.TCFPolicyVersion)
+	}
 
 	return p, r.Err
 }
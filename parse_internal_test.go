@@ -0,0 +1,58 @@
+package iabconsent
+
+import (
+	"github.com/go-check/check"
+)
+
+type ParseInternalSuite struct{}
+
+var _ = check.Suite(&ParseInternalSuite{})
+
+func (s *ParseInternalSuite) TestPreallocated(c *check.C) {
+	var tcs = []struct {
+		desc      string
+		size      int
+		n         uint
+		entryBits int
+		expected  int
+	}{
+		{
+			desc:      "Declared entries fit.",
+			size:      8,
+			n:         2,
+			entryBits: 17,
+			expected:  2,
+		},
+		{
+			desc:      "Range entries beyond the unread bits.",
+			size:      8,
+			n:         4095,
+			entryBits: 17,
+			expected:  3,
+		},
+		{
+			desc:      "Publisher restrictions beyond the unread bits.",
+			size:      4,
+			n:         4095,
+			entryBits: 20,
+			expected:  1,
+		},
+		{
+			desc:      "No unread bits.",
+			n:         4095,
+			entryBits: 17,
+			expected:  0,
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		var r = NewConsentReader(make([]byte, tc.size))
+		c.Check(r.preallocated(tc.n, tc.entryBits), check.Equals, tc.expected)
+	}
+
+	// The capacity shrinks as bits are read.
+	var r = NewConsentReader(make([]byte, 8))
+	var _, err = r.ReadInt(30)
+	c.Assert(err, check.IsNil)
+	c.Check(r.preallocated(4095, 17), check.Equals, 2)
+}
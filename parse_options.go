@@ -0,0 +1,76 @@
+package iabconsent
+
+import (
+	"fmt"
+)
+
+// ParseOptions limits the resources used to parse a consent string, so strings from untrusted
+// sources cannot force large allocations. A limit of 0 disables it, so ParseOptions{} parses any
+// well-formed string and should only be used for trusted input.
+type ParseOptions struct {
+	// MaxLength is the maximum length of the consent string, in characters.
+	MaxLength int
+	// MaxVendorID is the maximum vendor ID of a vendor list, bit field or range entry.
+	MaxVendorID int
+	// MaxRangeEntries is the maximum number of range entries read from a segment of the string,
+	// including those of every publisher restriction.
+	MaxRangeEntries int
+	// MaxPubRestrictions is the maximum number of publisher restriction entries.
+	MaxPubRestrictions int
+	// MaxGppSections is the maximum number of sections listed in a GPP header.
+	MaxGppSections int
+}
+
+// DefaultParseOptions are the limits used by ParseV1, ParseV2, ParseGppHeader and ParseGppConsent.
+// They are well above those of any consent string produced by a CMP for the current Global Vendor
+// List. Earlier releases did not limit parsing, so strings exceeding them, which those releases
+// parsed, now fail with a *LimitExceededError.
+var DefaultParseOptions = ParseOptions{
+	MaxLength:          16384,
+	MaxVendorID:        10000,
+	MaxRangeEntries:    4096,
+	MaxPubRestrictions: 256,
+	MaxGppSections:     64,
+}
+
+// LimitExceededError is returned when a consent string exceeds one of the limits of its
+// ParseOptions. Use errors.Cause to retrieve it from the returned error.
+type LimitExceededError struct {
+	// Limit is the name of the exceeded limit, e.g. "range entries".
+	Limit string
+	// Value is the value read from the string.
+	Value int
+	// Max is the limit that was exceeded.
+	Max int
+}
+
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("%s %d exceeds limit %d", e.Limit, e.Value, e.Max)
+}
+
+// checkLimit returns a *LimitExceededError if v exceeds max, unless max is 0.
+func checkLimit(limit string, v, max int) error {
+	if max > 0 && v > max {
+		return &LimitExceededError{Limit: limit, Value: v, Max: max}
+	}
+	return nil
+}
+
+// checkLimit sets the reader's error if v exceeds max, which stops any further reads.
+func (r *ConsentReader) checkLimit(limit string, v, max int) error {
+	var err = checkLimit(limit, v, max)
+	if err != nil && r.Err == nil {
+		r.Err = err
+	}
+	return err
+}
+
+// preallocated returns the capacity to allocate for n entries of at least entryBits bits each,
+// which is never more than the unread bits of r can hold, whatever n a string declares.
+func (r *ConsentReader) preallocated(n uint, entryBits int) int {
+	var fit = r.NumUnread() / entryBits
+	if int(n) < fit {
+		return int(n)
+	}
+	return fit
+}
@@ -0,0 +1,185 @@
+package iabconsent_test
+
+import (
+	"strings"
+
+	"github.com/go-check/check"
+	"github.com/pkg/errors"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type ParseOptionsSuite struct{}
+
+var _ = check.Suite(&ParseOptionsSuite{})
+
+// Regression fixtures for strings that declare far more entries or vendors than they hold, each
+// forcing large allocations before the limits were added.
+var (
+	// 4095 publisher restrictions, the first claiming 4095 range entries.
+	nestedRestrictionsConsent = "CAAAAAAAAAAAAAAAAAAAAACAAAAAAAAAAAAAAAAAAf_gn_4A"
+	// 4095 consented vendor range entries, and no entries.
+	rangeEntriesConsent = "CAAAAAAAAAAAAAAAAAAAAACAAAAAAAAAAAAAAAf_wAAA"
+	// A consented vendor bit field of 65535 vendors, holding 16.
+	maxVendorIDConsent = "CAAAAAAAAAAAAAAAAAAAAACAAAAAAAAAAAAH__v__AAA"
+	// A DisclosedVendors segment bit field of 65535 vendors, holding none.
+	disclosedVendorIDConsent = "CAAAAAAAAAAAAAAAAAAAAACAAAAAAAAAAAAAAAAAAAAA.P__g"
+	// A GPP header listing a group of 1000001 sections.
+	gppSectionsHeader = "DBABsAoAKM"
+)
+
+func (s *ParseOptionsSuite) TestLimitExceeded(c *check.C) {
+	var tcs = []struct {
+		desc    string
+		parse   func() error
+		limit   string
+		value   int
+		max     int
+		message string
+	}{
+		{
+			desc: "Nested publisher restrictions.",
+			parse: func() error {
+				var p, err = iabconsent.ParseV2(nestedRestrictionsConsent)
+				c.Check(p, check.IsNil)
+				return err
+			},
+			limit:   "pub restrictions",
+			value:   4095,
+			max:     256,
+			message: "pub restrictions 4095 exceeds limit 256",
+		},
+		{
+			desc: "Range entries over a lowered limit.",
+			parse: func() error {
+				var opts = iabconsent.DefaultParseOptions
+				opts.MaxRangeEntries = 100
+				var p, err = iabconsent.ParseV2WithOptions(rangeEntriesConsent, opts)
+				c.Check(p, check.IsNil)
+				return err
+			},
+			limit:   "range entries",
+			value:   4095,
+			max:     100,
+			message: "range entries 4095 exceeds limit 100",
+		},
+		{
+			desc: "Consented vendors max vendor id.",
+			parse: func() error {
+				var p, err = iabconsent.ParseV2(maxVendorIDConsent)
+				c.Check(p, check.IsNil)
+				return err
+			},
+			limit:   "vendor id",
+			value:   65535,
+			max:     10000,
+			message: "vendor id 65535 exceeds limit 10000",
+		},
+		{
+			desc: "DisclosedVendors segment max vendor id.",
+			parse: func() error {
+				var p, err = iabconsent.ParseV2(disclosedVendorIDConsent)
+				c.Check(p, check.IsNil)
+				return err
+			},
+			limit:   "vendor id",
+			value:   65535,
+			max:     10000,
+			message: "vendor id 65535 exceeds limit 10000",
+		},
+		{
+			desc: "TCF v1 max vendor id.",
+			parse: func() error {
+				var p, err = iabconsent.ParseV1WithOptions("BONJ5bvONJ5bvAMAPyFRAL7AAAAMhuqKklS-gAAAAAAAAAAAAAAAAAAAAAAAAAA", iabconsent.ParseOptions{MaxVendorID: 100})
+				c.Check(p, check.IsNil)
+				return err
+			},
+			limit:   "vendor id",
+			value:   200,
+			max:     100,
+			message: "vendor id 200 exceeds limit 100",
+		},
+		{
+			desc: "TCF v2 length.",
+			parse: func() error {
+				var _, err = iabconsent.ParseV2("C" + strings.Repeat("A", 20000))
+				return err
+			},
+			limit:   "length",
+			value:   20001,
+			max:     16384,
+			message: "length 20001 exceeds limit 16384",
+		},
+		{
+			desc: "TCF v1 length.",
+			parse: func() error {
+				var _, err = iabconsent.ParseV1WithOptions("BONJ5bvONJ5bvAMAPyFRAL7AAAAMhuqKklS-gAAAAAAAAAAAAAAAAAAAAAAAAAA", iabconsent.ParseOptions{MaxLength: 20})
+				return err
+			},
+			limit:   "length",
+			value:   63,
+			max:     20,
+			message: "length 63 exceeds limit 20",
+		},
+		{
+			desc: "GPP header sections.",
+			parse: func() error {
+				var _, err = iabconsent.ParseGppConsent(gppSectionsHeader + "~BVVqAAEABCA")
+				return err
+			},
+			limit:   "gpp sections",
+			value:   1000001,
+			max:     64,
+			message: "read gpp header: gpp sections 1000001 exceeds limit 64",
+		},
+		{
+			desc: "GPP TCF section.",
+			parse: func() error {
+				var _, err = iabconsent.ParseGppConsent("DBABMA~" + nestedRestrictionsConsent)
+				return err
+			},
+			limit:   "pub restrictions",
+			value:   4095,
+			max:     256,
+			message: "parse gpp section 2: pub restrictions 4095 exceeds limit 256",
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		var err = tc.parse()
+		c.Check(err, check.ErrorMatches, tc.message)
+		c.Check(errors.Cause(err), check.DeepEquals, &iabconsent.LimitExceededError{
+			Limit: tc.limit,
+			Value: tc.value,
+			Max:   tc.max,
+		})
+	}
+}
+
+func (s *ParseOptionsSuite) TestTruncatedWithoutLimits(c *check.C) {
+	// Without limits, strings declaring more entries or vendors than they hold fail when they run
+	// out of bits, rather than by exceeding a limit.
+	for _, consent := range []string{
+		nestedRestrictionsConsent,
+		rangeEntriesConsent,
+		maxVendorIDConsent,
+		disclosedVendorIDConsent,
+	} {
+		c.Log(consent)
+		var _, err = iabconsent.ParseV2WithOptions(consent, iabconsent.ParseOptions{})
+		c.Check(err, check.NotNil)
+		var _, limited = errors.Cause(err).(*iabconsent.LimitExceededError)
+		c.Check(limited, check.Equals, false)
+	}
+}
+
+func (s *ParseOptionsSuite) TestNoLimits(c *check.C) {
+	var h, err = iabconsent.ParseGppHeaderWithOptions(gppSectionsHeader, iabconsent.ParseOptions{})
+	c.Assert(err, check.IsNil)
+	c.Check(h.Sections, check.HasLen, 1000001)
+
+	var p *iabconsent.V2ParsedConsent
+	p, err = iabconsent.ParseV2WithOptions("COvzTO5OvzTO5BRAAAENAPCoALIAADgAAAAAAewAwABAAlAB6ABBFAAA", iabconsent.ParseOptions{})
+	c.Assert(err, check.IsNil)
+	c.Check(p.VendorAllowed(2), check.Equals, true)
+}
@@ -355,6 +355,9 @@ func (s *ParseSuite) TestFibonacciIndexValue(c *check.C) {
 		// Test last value in pre-compiled.
 		{index: 13,
 			expected: 233},
+		// Test first value after pre-compiled.
+		{index: 14,
+			expected: 377},
 		{index: 52,
 			expected: 32951280099},
 		{index: 62,
@@ -26,6 +26,9 @@ type ParsedConsent struct {
 // EveryPurposeAllowed returns true iff every purpose number in ps exists in
 // the ParsedConsent, otherwise false.
 func (p *ParsedConsent) EveryPurposeAllowed(ps []int) bool {
+	if p == nil {
+		return false
+	}
 	for _, rp := range ps {
 		if !p.PurposesAllowed[rp] {
 			return false
@@ -37,7 +40,7 @@ func (p *ParsedConsent) EveryPurposeAllowed(ps []int) bool {
 // PurposeAllowed returns true if the passed purpose number exists in
 // the ParsedConsent, otherwise false.
 func (p *ParsedConsent) PurposeAllowed(ps int) bool {
-	if !p.PurposesAllowed[ps] {
+	if p == nil || !p.PurposesAllowed[ps] {
 		return false
 	}
 	return true
@@ -46,9 +49,12 @@ func (p *ParsedConsent) PurposeAllowed(ps int) bool {
 // VendorAllowed returns true if the ParsedConsent contains affirmative consent
 // for VendorID v.
 func (p *ParsedConsent) VendorAllowed(v int) bool {
+	if p == nil {
+		return false
+	}
 	if p.IsRangeEncoding {
 		for _, re := range p.RangeEntries {
-			if re.StartVendorID <= v && v <= re.EndVendorID {
+			if re != nil && re.StartVendorID <= v && v <= re.EndVendorID {
 				return !p.DefaultConsent
 			}
 		}
@@ -68,6 +74,6 @@ func (p *ParsedConsent) SuitableToProcess(ps []int, v int) bool {
 // RangeEntry defines an inclusive range of vendor IDs from StartVendorID to
 // EndVendorID.
 type RangeEntry struct {
-	StartVendorID int
-	EndVendorID   int
+	StartVendorID int `json:"start_vendor_id"`
+	EndVendorID   int `json:"end_vendor_id"`
 }
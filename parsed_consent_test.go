@@ -1,8 +1,6 @@
 package iabconsent_test
 
 import (
-	"sort"
-
 	"github.com/go-check/check"
 
 	"github.com/LiveRamp/iabconsent"
@@ -45,20 +43,10 @@ func (p *ParsedConsentSuite) TestParseConsentStrings(c *check.C) {
 		c.Log(tc)
 		pc, err := iabconsent.ParseV1(tc.EncodedString)
 		c.Check(err, check.IsNil)
-
-		normalizeParsedConsent(pc)
-		normalizeParsedConsent(v1ConsentFixtures[tc.Type])
-
-		c.Assert(pc, check.DeepEquals, v1ConsentFixtures[tc.Type])
+		c.Check(pc.Equal(v1ConsentFixtures[tc.Type]), check.Equals, true)
 	}
 }
 
-func normalizeParsedConsent(p *iabconsent.ParsedConsent) {
-	sort.Slice(p.RangeEntries, func(i, j int) bool {
-		return p.RangeEntries[i].StartVendorID < p.RangeEntries[j].StartVendorID
-	})
-}
-
 func (p *ParsedConsentSuite) TestEveryPurposeAllowed(c *check.C) {
 	var tcs = []struct {
 		purposes []int
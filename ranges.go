@@ -0,0 +1,174 @@
+package iabconsent
+
+import (
+	"sort"
+)
+
+// The helpers below operate on sets of vendors held as sorted, non-overlapping and non-adjacent
+// range entries, as returned by mergeRanges. Unlike sets of IDs, their size is bounded by the number
+// of entries in the consent string rather than by the vendor IDs it holds, so they are safe to build
+// from untrusted strings. They never modify the entries passed to them.
+
+// mergeRanges returns the vendors of entries as sorted, merged range entries. Nil entries, and
+// entries ending before they start, hold no vendors.
+func mergeRanges(entries []*RangeEntry) []*RangeEntry {
+	var sorted = make([]*RangeEntry, 0, len(entries))
+	for _, re := range entries {
+		if re != nil && re.StartVendorID <= re.EndVendorID {
+			sorted = append(sorted, re)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].StartVendorID < sorted[j].StartVendorID
+	})
+
+	var merged []*RangeEntry
+	for _, re := range sorted {
+		if n := len(merged); n != 0 && re.StartVendorID <= merged[n-1].EndVendorID+1 {
+			if re.EndVendorID > merged[n-1].EndVendorID {
+				merged[n-1].EndVendorID = re.EndVendorID
+			}
+			continue
+		}
+		merged = append(merged, &RangeEntry{StartVendorID: re.StartVendorID, EndVendorID: re.EndVendorID})
+	}
+	return merged
+}
+
+// rangeEntriesOf returns the IDs set in ids as the fewest range entries, in ascending order.
+func rangeEntriesOf(ids map[int]bool) []*RangeEntry {
+	var sorted = make([]int, 0, len(ids))
+	for v, ok := range ids {
+		if ok {
+			sorted = append(sorted, v)
+		}
+	}
+	sort.Ints(sorted)
+
+	var entries []*RangeEntry
+	for _, id := range sorted {
+		if n := len(entries); n != 0 && entries[n-1].EndVendorID == id-1 {
+			entries[n-1].EndVendorID = id
+		} else {
+			entries = append(entries, &RangeEntry{StartVendorID: id, EndVendorID: id})
+		}
+	}
+	return entries
+}
+
+// bitFieldOf returns the vendors of ranges as a set of IDs. As the set holds every vendor of ranges,
+// it must only be built from ranges known to be small, e.g. when encoding them as a bit field.
+func bitFieldOf(ranges []*RangeEntry) map[int]bool {
+	var ids = make(map[int]bool)
+	for _, re := range ranges {
+		for v := re.StartVendorID; v <= re.EndVendorID; v++ {
+			ids[v] = true
+		}
+	}
+	return ids
+}
+
+// vendorRanges returns the merged vendors of a section encoded either as a bit field or as ranges.
+func vendorRanges(isRange bool, bitField map[int]bool, ranges []*RangeEntry) []*RangeEntry {
+	if isRange {
+		return mergeRanges(ranges)
+	}
+	return rangeEntriesOf(bitField)
+}
+
+// oobVendorRanges returns the merged vendors of v, which may be nil.
+func oobVendorRanges(v *OOBVendorList) []*RangeEntry {
+	if v == nil {
+		return nil
+	}
+	return vendorRanges(v.IsRangeEncoding, v.Vendors, v.VendorEntries)
+}
+
+// unionRanges returns the vendors in either a or b.
+func unionRanges(a, b []*RangeEntry) []*RangeEntry {
+	var all = make([]*RangeEntry, 0, len(a)+len(b))
+	return mergeRanges(append(append(all, a...), b...))
+}
+
+// intersectRanges returns the vendors in both of the merged ranges a and b.
+func intersectRanges(a, b []*RangeEntry) []*RangeEntry {
+	var ranges []*RangeEntry
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		var start, end = a[i].StartVendorID, a[i].EndVendorID
+		if b[j].StartVendorID > start {
+			start = b[j].StartVendorID
+		}
+		if b[j].EndVendorID < end {
+			end = b[j].EndVendorID
+		}
+		if start <= end {
+			ranges = append(ranges, &RangeEntry{StartVendorID: start, EndVendorID: end})
+		}
+		if a[i].EndVendorID < b[j].EndVendorID {
+			i++
+		} else {
+			j++
+		}
+	}
+	return ranges
+}
+
+// subtractRanges returns the vendors of the merged ranges a which are not in the merged ranges b.
+func subtractRanges(a, b []*RangeEntry) []*RangeEntry {
+	var ranges []*RangeEntry
+	var j int
+	for _, re := range a {
+		var start = re.StartVendorID
+		for ; j < len(b) && b[j].EndVendorID < start; j++ {
+		}
+		for k := j; k < len(b) && b[k].StartVendorID <= re.EndVendorID; k++ {
+			if b[k].StartVendorID > start {
+				ranges = append(ranges, &RangeEntry{StartVendorID: start, EndVendorID: b[k].StartVendorID - 1})
+			}
+			start = b[k].EndVendorID + 1
+		}
+		if start <= re.EndVendorID {
+			ranges = append(ranges, &RangeEntry{StartVendorID: start, EndVendorID: re.EndVendorID})
+		}
+	}
+	return ranges
+}
+
+// equalRanges reports whether the merged ranges a and b hold the same vendors.
+func equalRanges(a, b []*RangeEntry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if *a[i] != *b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// restrictionGroup identifies the publisher restrictions of a type under a purpose.
+type restrictionGroup struct {
+	purpose int
+	t       RestrictionType
+}
+
+// restrictionRanges returns the merged vendors restricted by entries, grouped by purpose and
+// restriction type. Nil entries are skipped, and groups without vendors are omitted.
+func restrictionRanges(entries []*PubRestrictionEntry) map[restrictionGroup][]*RangeEntry {
+	var all = make(map[restrictionGroup][]*RangeEntry)
+	for _, pr := range entries {
+		if pr == nil {
+			continue
+		}
+		var g = restrictionGroup{purpose: pr.PurposeID, t: pr.RestrictionType}
+		all[g] = append(all[g], pr.RestrictionsRange...)
+	}
+	var groups = make(map[restrictionGroup][]*RangeEntry, len(all))
+	for g, ranges := range all {
+		if merged := mergeRanges(ranges); len(merged) != 0 {
+			groups[g] = merged
+		}
+	}
+	return groups
+}
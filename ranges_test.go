@@ -0,0 +1,91 @@
+package iabconsent
+
+import (
+	"github.com/go-check/check"
+)
+
+type RangesSuite struct{}
+
+var _ = check.Suite(&RangesSuite{})
+
+// ranges returns the range entries of pairs of start and end vendors.
+func ranges(ids ...int) []*RangeEntry {
+	var entries []*RangeEntry
+	for i := 0; i+1 < len(ids); i += 2 {
+		entries = append(entries, &RangeEntry{StartVendorID: ids[i], EndVendorID: ids[i+1]})
+	}
+	return entries
+}
+
+func (s *RangesSuite) TestMergeRanges(c *check.C) {
+	var tcs = []struct {
+		desc     string
+		entries  []*RangeEntry
+		expected []*RangeEntry
+	}{
+		{
+			desc:     "Unsorted, overlapping and adjacent.",
+			entries:  ranges(10, 20, 1, 3, 4, 5, 15, 30, 40, 40),
+			expected: ranges(1, 5, 10, 30, 40, 40),
+		},
+		{
+			desc:     "Nil and reversed entries.",
+			entries:  append(ranges(5, 1, 2, 2), nil),
+			expected: ranges(2, 2),
+		},
+		{
+			desc: "Empty.",
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		c.Check(mergeRanges(tc.entries), check.DeepEquals, tc.expected)
+	}
+}
+
+func (s *RangesSuite) TestSetOperations(c *check.C) {
+	var tcs = []struct {
+		desc      string
+		a, b      []*RangeEntry
+		intersect []*RangeEntry
+		subtract  []*RangeEntry
+		union     []*RangeEntry
+	}{
+		{
+			desc:      "Overlapping.",
+			a:         ranges(1, 10, 20, 30),
+			b:         ranges(5, 25),
+			intersect: ranges(5, 10, 20, 25),
+			subtract:  ranges(1, 4, 26, 30),
+			union:     ranges(1, 30),
+		},
+		{
+			desc:      "Contained.",
+			a:         ranges(1, 100),
+			b:         ranges(2, 2, 50, 60),
+			intersect: ranges(2, 2, 50, 60),
+			subtract:  ranges(1, 1, 3, 49, 61, 100),
+			union:     ranges(1, 100),
+		},
+		{
+			desc:     "Disjoint.",
+			a:        ranges(1, 2),
+			b:        ranges(4, 5),
+			subtract: ranges(1, 2),
+			union:    ranges(1, 2, 4, 5),
+		},
+		{
+			desc:  "Empty a.",
+			b:     ranges(4, 5),
+			union: ranges(4, 5),
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		c.Check(intersectRanges(tc.a, tc.b), check.DeepEquals, tc.intersect)
+		c.Check(subtractRanges(tc.a, tc.b), check.DeepEquals, tc.subtract)
+		c.Check(unionRanges(tc.a, tc.b), check.DeepEquals, tc.union)
+		c.Check(equalRanges(tc.a, tc.b), check.Equals, false)
+		c.Check(equalRanges(tc.a, mergeRanges(tc.a)), check.Equals, true)
+	}
+}
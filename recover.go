@@ -0,0 +1,28 @@
+package iabconsent
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned by a parser when parsing a string panicked, which is always a bug in this
+// package. The parsers recover from such panics, so a malformed string can never crash the caller.
+// Use errors.Cause to retrieve it from the returned error.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack trace of the goroutine when it panicked.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("internal error: %v", e.Value)
+}
+
+// recoverPanic recovers from a panic, and sets err to a *PanicError holding it. It must be deferred
+// directly, e.g. defer recoverPanic(&err).
+func recoverPanic(err *error) {
+	if v := recover(); v != nil {
+		*err = &PanicError{Value: v, Stack: debug.Stack()}
+	}
+}
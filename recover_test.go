@@ -0,0 +1,30 @@
+package iabconsent
+
+import (
+	"github.com/go-check/check"
+)
+
+type RecoverSuite struct{}
+
+var _ = check.Suite(&RecoverSuite{})
+
+func (s *RecoverSuite) TestRecoverPanic(c *check.C) {
+	var parse = func() (p *V2ParsedConsent, err error) {
+		defer recoverPanic(&err)
+		var entries []*RangeEntry
+		return nil, checkLimit("range entries", entries[1].EndVendorID, 0)
+	}
+	var p, err = parse()
+	c.Check(p, check.IsNil)
+	c.Check(err, check.ErrorMatches, "internal error: runtime error: index out of range .*")
+	var pe, ok = err.(*PanicError)
+	c.Assert(ok, check.Equals, true)
+	c.Check(string(pe.Stack), check.Matches, "(?s).*TestRecoverPanic.*")
+
+	// Without a panic, the error is unchanged.
+	var recovered = func() (err error) {
+		defer recoverPanic(&err)
+		return nil
+	}
+	c.Check(recovered(), check.IsNil)
+}
@@ -0,0 +1,166 @@
+package iabconsent
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock returns the current time. It can be replaced in tests, or to evaluate consents as of another time.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to a Clock.
+type ClockFunc func() time.Time
+
+// Now returns f().
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock is the Clock of the system, i.e. time.Now.
+var SystemClock Clock = ClockFunc(time.Now)
+
+// StalenessVerdict is an enum type of the outcome of checking the timestamps of a consent against a
+// StalenessPolicy.
+type StalenessVerdict int
+
+const (
+	// The consent was last updated within the policy's maximum age.
+	VerdictFresh StalenessVerdict = iota
+	// The consent was last updated longer ago than the policy's maximum age, and must be refreshed.
+	VerdictStale
+	// The consent was created or last updated in the future, beyond the policy's allowed clock skew.
+	VerdictFuture
+)
+
+// String returns the name of the verdict, e.g. "stale".
+func (v StalenessVerdict) String() string {
+	switch v {
+	case VerdictFresh:
+		return "fresh"
+	case VerdictStale:
+		return "stale"
+	case VerdictFuture:
+		return "future"
+	default:
+		return fmt.Sprintf("unknown verdict %d", int(v))
+	}
+}
+
+// StalenessPolicy defines when the timestamps of a TCF consent are too old, or too far in the future,
+// for the consent to be relied upon. A zero field disables its check.
+//
+// TCF v2.2 CMPs round Created and LastUpdated down to the day, so a consent may appear up to a day
+// older than it is. With DayPrecision set, ages are measured from the start of the current UTC day,
+// so that a consent is not found stale a day early.
+type StalenessPolicy struct {
+	// MaxAgeMonths is the number of calendar months after LastUpdated that a consent is stale.
+	MaxAgeMonths int
+	// MaxAge is the duration after LastUpdated that a consent is stale. It is added to MaxAgeMonths.
+	MaxAge time.Duration
+	// MaxSkew is how far past the current time Created and LastUpdated may be, to allow for clock
+	// skew between the CMP and the caller.
+	MaxSkew time.Duration
+	// DayPrecision measures ages from the start of the current UTC day.
+	DayPrecision bool
+	// Clock is the source of the current time. SystemClock is used if it is nil.
+	Clock Clock
+}
+
+// DefaultStalenessPolicy treats consents last updated more than 13 months ago as stale, in line with
+// the TCF policy of refreshing consent at least every 13 months, and allows a day of skew for CMPs
+// which round timestamps to the day in a time zone ahead of UTC.
+var DefaultStalenessPolicy = StalenessPolicy{
+	MaxAgeMonths: 13,
+	MaxSkew:      24 * time.Hour,
+	DayPrecision: true,
+}
+
+// Check returns the verdict of a consent created and last updated at the given times.
+func (sp StalenessPolicy) Check(created, lastUpdated time.Time) StalenessVerdict {
+	var clock = sp.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	var now = clock.Now().UTC()
+
+	if sp.MaxSkew != 0 {
+		var limit = now.Add(sp.MaxSkew)
+		if created.After(limit) || lastUpdated.After(limit) {
+			return VerdictFuture
+		}
+	}
+	if sp.MaxAgeMonths != 0 || sp.MaxAge != 0 {
+		if sp.DayPrecision {
+			now = now.Truncate(24 * time.Hour)
+		}
+		var expires = lastUpdated.UTC().AddDate(0, sp.MaxAgeMonths, 0).Add(sp.MaxAge)
+		if now.After(expires) {
+			return VerdictStale
+		}
+	}
+	return VerdictFresh
+}
+
+// CheckV1 returns the verdict of the timestamps of p. A nil p has no timestamps, and is stale.
+func (sp StalenessPolicy) CheckV1(p *ParsedConsent) StalenessVerdict {
+	if p == nil {
+		return VerdictStale
+	}
+	return sp.Check(p.Created, p.LastUpdated)
+}
+
+// CheckV2 returns the verdict of the timestamps of p. A nil p has no timestamps, and is stale.
+func (sp StalenessPolicy) CheckV2(p *V2ParsedConsent) StalenessVerdict {
+	if p == nil {
+		return VerdictStale
+	}
+	return sp.Check(p.Created, p.LastUpdated)
+}
+
+// Evaluator returns e, unless it is a TCF consent whose verdict is not VerdictFresh, in which case it
+// returns a ConsentEvaluator which denies every request, treating the consent as no consent. Consents
+// of other frameworks carry no timestamps, and are returned unchanged. A nil e, or a nil TCF consent,
+// is treated as no consent.
+func (sp StalenessPolicy) Evaluator(e ConsentEvaluator) ConsentEvaluator {
+	var v = VerdictFresh
+	switch p := e.(type) {
+	case nil:
+		v = VerdictStale
+	case *ParsedConsent:
+		v = sp.CheckV1(p)
+	case *V2ParsedConsent:
+		v = sp.CheckV2(p)
+	}
+	if v == VerdictFresh {
+		return e
+	}
+	return &unfreshConsent{e}
+}
+
+// unfreshConsent is a consent which failed a StalenessPolicy, and so permits no processing.
+type unfreshConsent struct {
+	ConsentEvaluator
+}
+
+// Decide returns DecisionDeny.
+func (u *unfreshConsent) Decide(ProcessingRequest) Decision {
+	return DecisionDeny
+}
+
+// Framework returns the framework of the consent, or UnknownFramework if there is none.
+func (u *unfreshConsent) Framework() Framework {
+	if u.ConsentEvaluator == nil {
+		return UnknownFramework
+	}
+	return u.ConsentEvaluator.Framework()
+}
+
+// FrameworkVersion returns the framework version of the consent, or 0 if there is none.
+func (u *unfreshConsent) FrameworkVersion() int {
+	if u.ConsentEvaluator == nil {
+		return 0
+	}
+	return u.ConsentEvaluator.FrameworkVersion()
+}
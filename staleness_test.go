@@ -0,0 +1,154 @@
+package iabconsent_test
+
+import (
+	"time"
+
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type StalenessSuite struct{}
+
+var _ = check.Suite(&StalenessSuite{})
+
+// fixedClock returns a Clock which always returns the time t, given in RFC 3339.
+func fixedClock(c *check.C, t string) iabconsent.Clock {
+	var now, err = time.Parse(time.RFC3339, t)
+	c.Assert(err, check.IsNil)
+	return iabconsent.ClockFunc(func() time.Time { return now })
+}
+
+func (s *StalenessSuite) TestCheck(c *check.C) {
+	var day = func(d string) time.Time {
+		var t, err = time.Parse("2006-01-02", d)
+		c.Assert(err, check.IsNil)
+		return t
+	}
+	var tcs = []struct {
+		desc     string
+		policy   iabconsent.StalenessPolicy
+		now      string
+		updated  time.Time
+		expected iabconsent.StalenessVerdict
+	}{
+		{
+			desc:     "Within 13 months.",
+			policy:   iabconsent.DefaultStalenessPolicy,
+			now:      "2021-02-01T12:00:00Z",
+			updated:  day("2020-01-01"),
+			expected: iabconsent.VerdictFresh,
+		},
+		{
+			desc:     "Older than 13 months.",
+			policy:   iabconsent.DefaultStalenessPolicy,
+			now:      "2021-02-02T12:00:00Z",
+			updated:  day("2020-01-01"),
+			expected: iabconsent.VerdictStale,
+		},
+		{
+			desc:     "Exactly 13 months, to the day.",
+			policy:   iabconsent.StalenessPolicy{MaxAgeMonths: 13},
+			now:      "2021-02-01T00:00:00Z",
+			updated:  day("2020-01-01"),
+			expected: iabconsent.VerdictFresh,
+		},
+		{
+			desc:     "Later on the last day is stale without day precision.",
+			policy:   iabconsent.StalenessPolicy{MaxAgeMonths: 13},
+			now:      "2021-02-01T12:00:00Z",
+			updated:  day("2020-01-01"),
+			expected: iabconsent.VerdictStale,
+		},
+		{
+			desc:     "Later on the last day is fresh with day precision.",
+			policy:   iabconsent.StalenessPolicy{MaxAgeMonths: 13, DayPrecision: true},
+			now:      "2021-02-01T12:00:00Z",
+			updated:  day("2020-01-01"),
+			expected: iabconsent.VerdictFresh,
+		},
+		{
+			desc:     "Duration based maximum age.",
+			policy:   iabconsent.StalenessPolicy{MaxAge: 24 * time.Hour},
+			now:      "2020-01-02T00:00:01Z",
+			updated:  day("2020-01-01"),
+			expected: iabconsent.VerdictStale,
+		},
+		{
+			desc:     "Rounded to tomorrow's day, within skew.",
+			policy:   iabconsent.DefaultStalenessPolicy,
+			now:      "2020-01-01T18:00:00Z",
+			updated:  day("2020-01-02"),
+			expected: iabconsent.VerdictFresh,
+		},
+		{
+			desc:     "In the future beyond skew.",
+			policy:   iabconsent.DefaultStalenessPolicy,
+			now:      "2020-01-01T18:00:00Z",
+			updated:  day("2020-01-03"),
+			expected: iabconsent.VerdictFuture,
+		},
+		{
+			desc:     "No checks.",
+			policy:   iabconsent.StalenessPolicy{},
+			now:      "2030-01-01T00:00:00Z",
+			updated:  day("2040-01-01"),
+			expected: iabconsent.VerdictFresh,
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		tc.policy.Clock = fixedClock(c, tc.now)
+		c.Check(tc.policy.Check(tc.updated, tc.updated), check.Equals, tc.expected)
+	}
+}
+
+func (s *StalenessSuite) TestCheckCreatedInFuture(c *check.C) {
+	var policy = iabconsent.StalenessPolicy{MaxSkew: time.Hour, Clock: fixedClock(c, "2020-01-01T00:00:00Z")}
+	var past = time.Date(2019, 12, 31, 0, 0, 0, 0, time.UTC)
+	var future = time.Date(2020, 1, 1, 2, 0, 0, 0, time.UTC)
+	c.Check(policy.Check(future, past), check.Equals, iabconsent.VerdictFuture)
+	c.Check(policy.Check(past, past), check.Equals, iabconsent.VerdictFresh)
+}
+
+func (s *StalenessSuite) TestEvaluator(c *check.C) {
+	// Last updated 2020-03-05.
+	var p, err = iabconsent.ParseV2("COvzTO5OvzTO5BRAAAENAPCoALIAADgAAAAAAewAwABAAlAB6ABBFADBQAQA9hAAAcAA")
+	c.Assert(err, check.IsNil)
+	var req = iabconsent.ProcessingRequest{Purposes: []int{1}, VendorID: 2}
+	c.Assert(p.Decide(req), check.Equals, iabconsent.DecisionAllow)
+
+	var policy = iabconsent.DefaultStalenessPolicy
+	policy.Clock = fixedClock(c, "2020-06-01T00:00:00Z")
+	c.Check(policy.CheckV2(p), check.Equals, iabconsent.VerdictFresh)
+	c.Check(policy.Evaluator(p), check.Equals, iabconsent.ConsentEvaluator(p))
+
+	policy.Clock = fixedClock(c, "2021-06-01T00:00:00Z")
+	c.Check(policy.CheckV2(p), check.Equals, iabconsent.VerdictStale)
+	var e = policy.Evaluator(p)
+	c.Check(e.Decide(req), check.Equals, iabconsent.DecisionDeny)
+	c.Check(e.Framework(), check.Equals, iabconsent.FrameworkTcfV2)
+
+	// US Privacy strings carry no timestamps.
+	var u, _ = iabconsent.ParseUsPrivacy("1YNN")
+	c.Check(policy.Evaluator(u), check.Equals, iabconsent.ConsentEvaluator(u))
+}
+
+func (s *StalenessSuite) TestNil(c *check.C) {
+	var policy = iabconsent.DefaultStalenessPolicy
+	policy.Clock = fixedClock(c, "2020-06-01T00:00:00Z")
+	c.Check(policy.CheckV1(nil), check.Equals, iabconsent.VerdictStale)
+	c.Check(policy.CheckV2(nil), check.Equals, iabconsent.VerdictStale)
+
+	var req = iabconsent.ProcessingRequest{Purposes: []int{1}, VendorID: 2}
+	var e = policy.Evaluator(nil)
+	c.Check(e.Decide(req), check.Equals, iabconsent.DecisionDeny)
+	c.Check(e.Framework(), check.Equals, iabconsent.UnknownFramework)
+	c.Check(e.FrameworkVersion(), check.Equals, 0)
+
+	e = policy.Evaluator((*iabconsent.V2ParsedConsent)(nil))
+	c.Check(e.Decide(req), check.Equals, iabconsent.DecisionDeny)
+	c.Check(e.Framework(), check.Equals, iabconsent.FrameworkTcfV2)
+	e = policy.Evaluator((*iabconsent.ParsedConsent)(nil))
+	c.Check(e.Decide(req), check.Equals, iabconsent.DecisionDeny)
+}
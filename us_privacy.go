@@ -0,0 +1,172 @@
+package iabconsent
+
+import (
+	"github.com/pkg/errors"
+)
+
+// UsPrivacyStringLength is the length of a version 1 US Privacy (CCPA) string.
+const UsPrivacyStringLength = 4
+
+// UsPrivacyParsedConsent represents data extracted from a US Privacy (CCPA) string, e.g. "1YNN".
+// Format can be found here: https://github.com/InteractiveAdvertisingBureau/USPrivacy/blob/master/CCPA/US%20Privacy%20String.md
+// Each of the flags is one of "Y", "N" or "-", which is read as MspaYes, MspaNo or MspaNotApplicable respectively.
+type UsPrivacyParsedConsent struct {
+	// The version of the US Privacy specification used to encode the string.
+	Version int `json:"version"`
+	// Explicit Notice/Opportunity to Opt Out was provided.
+	Notice MspaNaYesNo `json:"notice"`
+	// The user has opted out of the Sale of their Personal Information.
+	OptOutSale MspaNaYesNo `json:"opt_out_sale"`
+	// The publisher is a signatory to the IAB Limited Service Provider Agreement (LSPA).
+	LspaCovered MspaNaYesNo `json:"lspa_covered"`
+}
+
+// ParseUsPrivacy parses a version 1 US Privacy string.
+func ParseUsPrivacy(s string) (*UsPrivacyParsedConsent, error) {
+	if len(s) != UsPrivacyStringLength {
+		return nil, errors.Errorf("invalid us privacy string length: %d", len(s))
+	}
+	if s[0] != '1' {
+		return nil, errors.Errorf("unsupported us privacy version: %c", s[0])
+	}
+	var p = &UsPrivacyParsedConsent{Version: 1}
+	var err error
+	if p.Notice, err = usPrivacyFlag(s[1]); err != nil {
+		return nil, errors.Wrap(err, "parse notice")
+	}
+	if p.OptOutSale, err = usPrivacyFlag(s[2]); err != nil {
+		return nil, errors.Wrap(err, "parse opt out sale")
+	}
+	if p.LspaCovered, err = usPrivacyFlag(s[3]); err != nil {
+		return nil, errors.Wrap(err, "parse lspa covered")
+	}
+	return p, nil
+}
+
+// Encode returns the US Privacy string representation of the consent.
+func (p *UsPrivacyParsedConsent) Encode() (string, error) {
+	if p.Version != 1 {
+		return "", errors.Errorf("unsupported us privacy version: %d", p.Version)
+	}
+	var b = []byte{'1', 0, 0, 0}
+	for i, v := range []MspaNaYesNo{p.Notice, p.OptOutSale, p.LspaCovered} {
+		var c, err = usPrivacyChar(v)
+		if err != nil {
+			return "", err
+		}
+		b[i+1] = c
+	}
+	return string(b), nil
+}
+
+// UsPrivacyToUsNational converts a US Privacy string into an equivalent version 2 US National section, following the
+// IAB mapping guidance:
+//
+//	Notice      -> SharingNotice and SaleOptOutNotice
+//	OptOutSale  -> SaleOptOut
+//	LspaCovered -> MspaCoveredTransaction
+//
+// MspaCoveredTransaction cannot be Not Applicable, so a "-" LSPA flag is mapped to MspaNo. All other fields,
+// which a US Privacy string does not carry, are left Not Applicable.
+func UsPrivacyToUsNational(s string) (*MspaParsedConsent, error) {
+	var u, err = ParseUsPrivacy(s)
+	if err != nil {
+		return nil, err
+	}
+	var p = &MspaParsedConsent{
+		SID:                             UsNationalSID,
+		Version:                         2,
+		SensitiveDataProcessingConsents: make(map[int]MspaConsent, usnatSensitiveDataCategories),
+		KnownChildSensitiveDataConsents: make(map[int]MspaConsent, usnatKnownChildCategories),
+		MspaCoveredTransaction:          MspaNo,
+	}
+	for i := 0; i < usnatSensitiveDataCategories; i++ {
+		p.SensitiveDataProcessingConsents[i] = ConsentNotApplicable
+	}
+	for i := 0; i < usnatKnownChildCategories; i++ {
+		p.KnownChildSensitiveDataConsents[i] = ConsentNotApplicable
+	}
+
+	switch u.Notice {
+	case MspaYes:
+		p.SharingNotice, p.SaleOptOutNotice = NoticeProvided, NoticeProvided
+	case MspaNo:
+		p.SharingNotice, p.SaleOptOutNotice = NoticeNotProvided, NoticeNotProvided
+	}
+	switch u.OptOutSale {
+	case MspaYes:
+		p.SaleOptOut = OptedOut
+	case MspaNo:
+		p.SaleOptOut = NotOptedOut
+	}
+	if u.LspaCovered == MspaYes {
+		p.MspaCoveredTransaction = MspaYes
+	}
+	return p, nil
+}
+
+// UsNationalToUsPrivacy converts a US National section into a US Privacy string, so the signal can be passed to
+// partners that only understand US Privacy. Notice is taken from SaleOptOutNotice, OptOutSale from SaleOptOut and
+// LspaCovered from MspaCoveredTransaction.
+func UsNationalToUsPrivacy(p *MspaParsedConsent) (string, error) {
+	if p == nil {
+		return "", errors.New("nil us national consent")
+	}
+	var u = &UsPrivacyParsedConsent{Version: 1}
+
+	switch p.SaleOptOutNotice {
+	case NoticeNotApplicable:
+		u.Notice = MspaNotApplicable
+	case NoticeProvided:
+		u.Notice = MspaYes
+	case NoticeNotProvided:
+		u.Notice = MspaNo
+	default:
+		return "", errors.Errorf("invalid sale opt out notice value: %d", p.SaleOptOutNotice)
+	}
+	switch p.SaleOptOut {
+	case OptOutNotApplicable:
+		u.OptOutSale = MspaNotApplicable
+	case OptedOut:
+		u.OptOutSale = MspaYes
+	case NotOptedOut:
+		u.OptOutSale = MspaNo
+	default:
+		return "", errors.Errorf("invalid sale opt out value: %d", p.SaleOptOut)
+	}
+	switch p.MspaCoveredTransaction {
+	case MspaNotApplicable, MspaYes, MspaNo:
+		u.LspaCovered = p.MspaCoveredTransaction
+	default:
+		return "", errors.Errorf("invalid covered transaction value: %d", p.MspaCoveredTransaction)
+	}
+	return u.Encode()
+}
+
+// usPrivacyFlag reads a single US Privacy flag character.
+func usPrivacyFlag(c byte) (MspaNaYesNo, error) {
+	switch c {
+	case '-':
+		return MspaNotApplicable, nil
+	case 'Y', 'y':
+		return MspaYes, nil
+	case 'N', 'n':
+		return MspaNo, nil
+	default:
+		return InvalidMspaValue, errors.Errorf("invalid us privacy flag: %c", c)
+	}
+}
+
+// usPrivacyChar is the inverse of usPrivacyFlag.
+func usPrivacyChar(v MspaNaYesNo) (byte, error) {
+	switch v {
+	case MspaNotApplicable:
+		return '-', nil
+	case MspaYes:
+		return 'Y', nil
+	case MspaNo:
+		return 'N', nil
+	default:
+		return 0, errors.Errorf("invalid us privacy value: %d", v)
+	}
+}
@@ -0,0 +1,164 @@
+package iabconsent_test
+
+import (
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type UsPrivacySuite struct{}
+
+var _ = check.Suite(&UsPrivacySuite{})
+
+func (s *UsPrivacySuite) TestParseUsPrivacy(c *check.C) {
+	var tcs = []struct {
+		desc     string
+		s        string
+		expected *iabconsent.UsPrivacyParsedConsent
+	}{
+		{
+			desc: "Notice given, not opted out, not covered by LSPA.",
+			s:    "1YNN",
+			expected: &iabconsent.UsPrivacyParsedConsent{
+				Version:     1,
+				Notice:      iabconsent.MspaYes,
+				OptOutSale:  iabconsent.MspaNo,
+				LspaCovered: iabconsent.MspaNo,
+			},
+		},
+		{
+			desc: "Opted out, covered by LSPA.",
+			s:    "1YYY",
+			expected: &iabconsent.UsPrivacyParsedConsent{
+				Version:     1,
+				Notice:      iabconsent.MspaYes,
+				OptOutSale:  iabconsent.MspaYes,
+				LspaCovered: iabconsent.MspaYes,
+			},
+		},
+		{
+			desc: "CCPA does not apply.",
+			s:    "1---",
+			expected: &iabconsent.UsPrivacyParsedConsent{
+				Version:     1,
+				Notice:      iabconsent.MspaNotApplicable,
+				OptOutSale:  iabconsent.MspaNotApplicable,
+				LspaCovered: iabconsent.MspaNotApplicable,
+			},
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		var p, err = iabconsent.ParseUsPrivacy(tc.s)
+		c.Check(err, check.IsNil)
+		c.Check(p, check.DeepEquals, tc.expected)
+
+		e, err := p.Encode()
+		c.Check(err, check.IsNil)
+		c.Check(e, check.Equals, tc.s)
+	}
+}
+
+func (s *UsPrivacySuite) TestParseUsPrivacyError(c *check.C) {
+	var tcs = []struct {
+		s        string
+		expected string
+	}{
+		{s: "", expected: "invalid us privacy string length: 0"},
+		{s: "1YNNN", expected: "invalid us privacy string length: 5"},
+		{s: "2YNN", expected: "unsupported us privacy version: 2"},
+		{s: "1XNN", expected: "parse notice: invalid us privacy flag: X"},
+		{s: "1Y?N", expected: "parse opt out sale: invalid us privacy flag: \\?"},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.s)
+		var p, err = iabconsent.ParseUsPrivacy(tc.s)
+		c.Check(p, check.IsNil)
+		c.Check(err, check.ErrorMatches, tc.expected)
+	}
+}
+
+func (s *UsPrivacySuite) TestUsPrivacyToUsNational(c *check.C) {
+	var tcs = []struct {
+		s          string
+		notice     iabconsent.MspaNotice
+		saleOptOut iabconsent.MspaOptout
+		covered    iabconsent.MspaNaYesNo
+	}{
+		{s: "1YNN", notice: iabconsent.NoticeProvided, saleOptOut: iabconsent.NotOptedOut, covered: iabconsent.MspaNo},
+		{s: "1YYY", notice: iabconsent.NoticeProvided, saleOptOut: iabconsent.OptedOut, covered: iabconsent.MspaYes},
+		{s: "1NN-", notice: iabconsent.NoticeNotProvided, saleOptOut: iabconsent.NotOptedOut, covered: iabconsent.MspaNo},
+		{s: "1---", notice: iabconsent.NoticeNotApplicable, saleOptOut: iabconsent.OptOutNotApplicable, covered: iabconsent.MspaNo},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.s)
+		var p, err = iabconsent.UsPrivacyToUsNational(tc.s)
+		c.Assert(err, check.IsNil)
+		c.Check(p.Version, check.Equals, 2)
+		c.Check(p.SharingNotice, check.Equals, tc.notice)
+		c.Check(p.SaleOptOutNotice, check.Equals, tc.notice)
+		c.Check(p.SaleOptOut, check.Equals, tc.saleOptOut)
+		c.Check(p.MspaCoveredTransaction, check.Equals, tc.covered)
+		c.Check(p.SharingOptOut, check.Equals, iabconsent.OptOutNotApplicable)
+		c.Check(p.SensitiveDataProcessingConsents, check.HasLen, 16)
+		c.Check(p.KnownChildSensitiveDataConsents, check.HasLen, 3)
+	}
+
+	var _, err = iabconsent.UsPrivacyToUsNational("1YN")
+	c.Check(err, check.ErrorMatches, "invalid us privacy string length: 3")
+}
+
+func (s *UsPrivacySuite) TestUsNationalToUsPrivacy(c *check.C) {
+	var tcs = []struct {
+		desc     string
+		consent  *iabconsent.MspaParsedConsent
+		expected string
+	}{
+		{
+			desc:     "US National v1 fixture, which leaves Covered Transaction Not Applicable.",
+			consent:  mspaConsentFixtures[iabconsent.UsNationalSID]["BVVqAAEABCA.QA"],
+			expected: "1YN-",
+		},
+		{
+			desc: "Opted out, covered transaction.",
+			consent: &iabconsent.MspaParsedConsent{
+				Version:                2,
+				SaleOptOutNotice:       iabconsent.NoticeProvided,
+				SaleOptOut:             iabconsent.OptedOut,
+				MspaCoveredTransaction: iabconsent.MspaYes,
+			},
+			expected: "1YYY",
+		},
+		{
+			desc:     "Not applicable.",
+			consent:  &iabconsent.MspaParsedConsent{Version: 2},
+			expected: "1---",
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		var u, err = iabconsent.UsNationalToUsPrivacy(tc.consent)
+		c.Check(err, check.IsNil)
+		c.Check(u, check.Equals, tc.expected)
+	}
+
+	for _, u := range []string{"1YNN", "1YYY", "1NY-"} {
+		var p, err = iabconsent.UsPrivacyToUsNational(u)
+		c.Assert(err, check.IsNil)
+		back, err := iabconsent.UsNationalToUsPrivacy(p)
+		c.Check(err, check.IsNil)
+		// A Not Applicable LSPA flag cannot be represented in US National and comes back as "N".
+		if u[3] == '-' {
+			u = u[:3] + "N"
+		}
+		c.Check(back, check.Equals, u)
+	}
+}
+
+func (s *UsPrivacySuite) TestUsNationalToUsPrivacyError(c *check.C) {
+	var _, err = iabconsent.UsNationalToUsPrivacy(nil)
+	c.Check(err, check.ErrorMatches, "nil us national consent")
+
+	_, err = iabconsent.UsNationalToUsPrivacy(&iabconsent.MspaParsedConsent{SaleOptOut: iabconsent.InvalidOptOutValue})
+	c.Check(err, check.ErrorMatches, "invalid sale opt out value: 3")
+}
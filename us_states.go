@@ -0,0 +1,159 @@
+package iabconsent
+
+import (
+	"strings"
+	"time"
+)
+
+// usStateSIDs maps two-letter US state codes to the GPP Section ID of their state-specific
+// MSPA section.
+var usStateSIDs = map[string]int{
+	"CA": UsCaliforniaSID,
+	"VA": UsVirginiaSID,
+	"CO": UsColoradoSID,
+	"UT": UsUtahSID,
+	"CT": UsConnecticutSID,
+	"FL": UsFloridaSID,
+	"MT": UsMontanaSID,
+	"OR": UsOregonSID,
+	"TX": UsTexasSID,
+	"DE": UsDelawareSID,
+	"IA": UsIowaSID,
+	"NE": UsNebraskaSID,
+	"NH": UsNewHampshireSID,
+	"NJ": UsNewJerseySID,
+	"TN": UsTennesseeSID,
+}
+
+// sectionEffectiveDates maps the GPP Section ID of each state-specific MSPA section to the
+// date the state's privacy law took effect, at midnight UTC.
+var sectionEffectiveDates = map[int]time.Time{
+	// California Privacy Rights Act (CPRA).
+	UsCaliforniaSID: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC),
+	// Virginia Consumer Data Protection Act (VCDPA).
+	UsVirginiaSID: time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC),
+	// Colorado Privacy Act (CPA).
+	UsColoradoSID: time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC),
+	// Utah Consumer Privacy Act (UCPA).
+	UsUtahSID: time.Date(2023, time.December, 31, 0, 0, 0, 0, time.UTC),
+	// Connecticut Data Privacy Act (CTDPA).
+	UsConnecticutSID: time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC),
+	// Florida Digital Bill of Rights (FDBR).
+	UsFloridaSID: time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC),
+	// Montana Consumer Data Privacy Act (MCDPA).
+	UsMontanaSID: time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC),
+	// Oregon Consumer Privacy Act (OCPA).
+	UsOregonSID: time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC),
+	// Texas Data Privacy and Security Act (TDPSA).
+	UsTexasSID: time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC),
+	// Delaware Personal Data Privacy Act (DPDPA).
+	UsDelawareSID: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+	// Iowa Consumer Data Protection Act (ICDPA).
+	UsIowaSID: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+	// Nebraska Data Privacy Act (NDPA).
+	UsNebraskaSID: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+	// New Hampshire Privacy Act (SB 255).
+	UsNewHampshireSID: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+	// New Jersey Data Privacy Act (NJDPA).
+	UsNewJerseySID: time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC),
+	// Tennessee Information Protection Act (TIPA).
+	UsTennesseeSID: time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC),
+}
+
+// SectionEffectiveDate returns the date the privacy law of a state-specific MSPA section took
+// effect. False is returned for sections that are not tied to a single law, such as usnat.
+func SectionEffectiveDate(sid int) (time.Time, bool) {
+	var d, ok = sectionEffectiveDates[sid]
+	return d, ok
+}
+
+// SectionInEffect returns whether the privacy law of section sid was legally in force at t.
+// Sections without an effective date, such as usnat, are always considered in force.
+// MspaParsedConsent.Decide consults it for requests with ProcessingRequest.At set.
+func SectionInEffect(sid int, t time.Time) bool {
+	var d, ok = sectionEffectiveDates[sid]
+	return !ok || !t.Before(d)
+}
+
+// UsStateSID returns the GPP Section ID of the state-specific MSPA section for a two-letter
+// US state code, such as "CA" or "us-ca". False is returned if the state has no section.
+func UsStateSID(stateCode string) (int, bool) {
+	var code = strings.ToUpper(strings.TrimSpace(stateCode))
+	code = strings.TrimPrefix(code, "US-")
+	var sid, ok = usStateSIDs[code]
+	return sid, ok
+}
+
+// ApplicableMspaSection returns the Section ID and consent of the MSPA section that governs a
+// user in the US state stateCode. Following IAB guidance, the state-specific section is used
+// if present, otherwise the section falls back to US National (usnat).
+//
+// gppSIDs is the list of sections applicable to the transaction (gpp_sid), and parsed holds the
+// parsed sections by Section ID, such as the Sections of the result of ParseGppConsent. An empty
+// gppSIDs treats every parsed section as applicable. False is returned if neither the state
+// section nor usnat is applicable and present. See GppConsent.ApplicableSection to pick the
+// section of a parsed GPP string.
+func ApplicableMspaSection(stateCode string, gppSIDs []int, parsed map[int]GppParsedConsent) (int, GppParsedConsent, bool) {
+	return applicableMspaSection(stateCode, gppSIDs, parsed, func(int) bool { return true })
+}
+
+// ApplicableMspaSectionAt is ApplicableMspaSection for a transaction that happened at t. A
+// state-specific section whose law was not yet in force at t is skipped in favour of usnat,
+// which is useful when reprocessing historic traffic.
+func ApplicableMspaSectionAt(stateCode string, gppSIDs []int, parsed map[int]GppParsedConsent, t time.Time) (int, GppParsedConsent, bool) {
+	return applicableMspaSection(stateCode, gppSIDs, parsed, func(sid int) bool { return SectionInEffect(sid, t) })
+}
+
+// ApplicableSection returns the Section ID and consent of the MSPA section of g that governs a user
+// in the US state stateCode, as ApplicableMspaSection does for the sections of g. False is returned
+// if g is nil.
+func (g *GppConsent) ApplicableSection(stateCode string, gppSIDs []int) (int, GppParsedConsent, bool) {
+	if g == nil {
+		return 0, nil, false
+	}
+	return ApplicableMspaSection(stateCode, gppSIDs, g.Sections)
+}
+
+// ApplicableSectionAt is ApplicableSection for a transaction that happened at t, as
+// ApplicableMspaSectionAt does for the sections of g.
+func (g *GppConsent) ApplicableSectionAt(stateCode string, gppSIDs []int, t time.Time) (int, GppParsedConsent, bool) {
+	if g == nil {
+		return 0, nil, false
+	}
+	return ApplicableMspaSectionAt(stateCode, gppSIDs, g.Sections, t)
+}
+
+// applicableMspaSection implements ApplicableMspaSection, only considering the state-specific
+// section if inEffect returns true for it.
+func applicableMspaSection(stateCode string, gppSIDs []int, parsed map[int]GppParsedConsent,
+	inEffect func(sid int) bool) (int, GppParsedConsent, bool) {
+	if sid, ok := UsStateSID(stateCode); ok && inEffect(sid) {
+		if consent, found := applicableSection(sid, gppSIDs, parsed); found {
+			return sid, consent, true
+		}
+	}
+	if consent, found := applicableSection(UsNationalSID, gppSIDs, parsed); found {
+		return UsNationalSID, consent, true
+	}
+	return 0, nil, false
+}
+
+// applicableSection returns the parsed consent for sid, if sid is listed in gppSIDs (or
+// gppSIDs is empty) and was successfully parsed.
+func applicableSection(sid int, gppSIDs []int, parsed map[int]GppParsedConsent) (GppParsedConsent, bool) {
+	if len(gppSIDs) > 0 && !containsInt(gppSIDs, sid) {
+		return nil, false
+	}
+	var consent, ok = parsed[sid]
+	return consent, ok
+}
+
+// containsInt returns whether |v| is found within |vs|.
+func containsInt(vs []int, v int) bool {
+	for _, i := range vs {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
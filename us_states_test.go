@@ -0,0 +1,224 @@
+package iabconsent_test
+
+import (
+	"time"
+
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type UsStatesSuite struct{}
+
+var _ = check.Suite(&UsStatesSuite{})
+
+func (s *UsStatesSuite) TestUsStateSID(c *check.C) {
+	var tcs = []struct {
+		stateCode string
+		sid       int
+		found     bool
+	}{
+		{stateCode: "CA", sid: iabconsent.UsCaliforniaSID, found: true},
+		{stateCode: "ca", sid: iabconsent.UsCaliforniaSID, found: true},
+		{stateCode: " US-TN ", sid: iabconsent.UsTennesseeSID, found: true},
+		{stateCode: "NJ", sid: iabconsent.UsNewJerseySID, found: true},
+		{stateCode: "NY", sid: 0, found: false},
+		{stateCode: "", sid: 0, found: false},
+	}
+	for _, tc := range tcs {
+		c.Log(tc)
+		var sid, found = iabconsent.UsStateSID(tc.stateCode)
+		c.Check(sid, check.Equals, tc.sid)
+		c.Check(found, check.Equals, tc.found)
+	}
+}
+
+func (s *UsStatesSuite) TestApplicableMspaSection(c *check.C) {
+	var usnat = mspaConsentFixtures[iabconsent.UsNationalSID]["BVVqAAEABCA.QA"]
+	var usca = mspaConsentFixtures[iabconsent.UsCaliforniaSID]["BVoYYZoI"]
+	var parsed = map[int]iabconsent.GppParsedConsent{
+		iabconsent.UsNationalSID:   usnat,
+		iabconsent.UsCaliforniaSID: usca,
+	}
+
+	var tcs = []struct {
+		desc      string
+		stateCode string
+		gppSIDs   []int
+		parsed    map[int]iabconsent.GppParsedConsent
+		sid       int
+		consent   iabconsent.GppParsedConsent
+		found     bool
+	}{
+		{
+			desc:      "State section present and applicable.",
+			stateCode: "CA",
+			gppSIDs:   []int{iabconsent.UsCaliforniaSID},
+			parsed:    parsed,
+			sid:       iabconsent.UsCaliforniaSID,
+			consent:   usca,
+			found:     true,
+		},
+		{
+			desc:      "Empty gpp_sid treats all sections as applicable.",
+			stateCode: "CA",
+			parsed:    parsed,
+			sid:       iabconsent.UsCaliforniaSID,
+			consent:   usca,
+			found:     true,
+		},
+		{
+			desc:      "State section not applicable, fall back to usnat.",
+			stateCode: "CA",
+			gppSIDs:   []int{iabconsent.UsNationalSID},
+			parsed:    parsed,
+			sid:       iabconsent.UsNationalSID,
+			consent:   usnat,
+			found:     true,
+		},
+		{
+			desc:      "State without a section, fall back to usnat.",
+			stateCode: "NY",
+			gppSIDs:   []int{iabconsent.UsNationalSID, iabconsent.UsCaliforniaSID},
+			parsed:    parsed,
+			sid:       iabconsent.UsNationalSID,
+			consent:   usnat,
+			found:     true,
+		},
+		{
+			desc:      "State section applicable but not parsed, fall back to usnat.",
+			stateCode: "VA",
+			gppSIDs:   []int{iabconsent.UsNationalSID, iabconsent.UsVirginiaSID},
+			parsed:    parsed,
+			sid:       iabconsent.UsNationalSID,
+			consent:   usnat,
+			found:     true,
+		},
+		{
+			desc:      "Neither state nor usnat applicable.",
+			stateCode: "VA",
+			gppSIDs:   []int{iabconsent.UsCaliforniaSID},
+			parsed:    parsed,
+			found:     false,
+		},
+		{
+			desc:      "Nothing parsed.",
+			stateCode: "CA",
+			parsed:    map[int]iabconsent.GppParsedConsent{},
+			found:     false,
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		var sid, consent, found = iabconsent.ApplicableMspaSection(tc.stateCode, tc.gppSIDs, tc.parsed)
+		c.Check(sid, check.Equals, tc.sid)
+		c.Check(consent, check.DeepEquals, tc.consent)
+		c.Check(found, check.Equals, tc.found)
+	}
+}
+
+func (s *UsStatesSuite) TestGppConsentApplicableSection(c *check.C) {
+	var g, err = iabconsent.ParseGppConsent("DBABLA~BVVqAAEABCA.QA")
+	c.Assert(err, check.IsNil)
+
+	// Without a California section, California traffic is governed by usnat.
+	var sid, consent, found = g.ApplicableSection("us-ca", []int{iabconsent.UsNationalSID})
+	c.Check(found, check.Equals, true)
+	c.Check(sid, check.Equals, iabconsent.UsNationalSID)
+	c.Check(consent, check.DeepEquals, g.Section(iabconsent.UsNationalSID))
+
+	_, _, found = g.ApplicableSection("CA", []int{iabconsent.UsCaliforniaSID})
+	c.Check(found, check.Equals, false)
+
+	g = &iabconsent.GppConsent{Sections: map[int]iabconsent.GppParsedConsent{
+		iabconsent.UsNationalSID:  mspaConsentFixtures[iabconsent.UsNationalSID]["BVVqAAEABCA.QA"],
+		iabconsent.UsTennesseeSID: mspaConsentFixtures[iabconsent.UsTennesseeSID]["Bqqqqqo"],
+	}}
+	sid, _, found = g.ApplicableSectionAt("TN", nil, time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	c.Check(found, check.Equals, true)
+	c.Check(sid, check.Equals, iabconsent.UsNationalSID)
+	sid, _, found = g.ApplicableSectionAt("TN", nil, time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC))
+	c.Check(found, check.Equals, true)
+	c.Check(sid, check.Equals, iabconsent.UsTennesseeSID)
+
+	g = nil
+	_, _, found = g.ApplicableSection("CA", nil)
+	c.Check(found, check.Equals, false)
+	_, _, found = g.ApplicableSectionAt("CA", nil, time.Now())
+	c.Check(found, check.Equals, false)
+}
+
+func (s *UsStatesSuite) TestSectionInEffect(c *check.C) {
+	var tcs = []struct {
+		desc     string
+		sid      int
+		at       time.Time
+		expected bool
+	}{
+		{
+			desc:     "Tennessee before TIPA took effect.",
+			sid:      iabconsent.UsTennesseeSID,
+			at:       time.Date(2025, time.June, 30, 23, 59, 59, 0, time.UTC),
+			expected: false,
+		},
+		{
+			desc:     "Tennessee on the day TIPA took effect.",
+			sid:      iabconsent.UsTennesseeSID,
+			at:       time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			desc:     "New Jersey before NJDPA took effect.",
+			sid:      iabconsent.UsNewJerseySID,
+			at:       time.Date(2025, time.January, 14, 12, 0, 0, 0, time.UTC),
+			expected: false,
+		},
+		{
+			desc:     "California after CPRA took effect.",
+			sid:      iabconsent.UsCaliforniaSID,
+			at:       time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+		{
+			desc:     "US National has no effective date.",
+			sid:      iabconsent.UsNationalSID,
+			at:       time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+			expected: true,
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		c.Check(iabconsent.SectionInEffect(tc.sid, tc.at), check.Equals, tc.expected)
+	}
+}
+
+func (s *UsStatesSuite) TestSectionEffectiveDate(c *check.C) {
+	var d, ok = iabconsent.SectionEffectiveDate(iabconsent.UsNewJerseySID)
+	c.Check(ok, check.Equals, true)
+	c.Check(d, check.DeepEquals, time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC))
+
+	_, ok = iabconsent.SectionEffectiveDate(iabconsent.UsNationalSID)
+	c.Check(ok, check.Equals, false)
+}
+
+func (s *UsStatesSuite) TestApplicableMspaSectionAt(c *check.C) {
+	var usnat = mspaConsentFixtures[iabconsent.UsNationalSID]["BVVqAAEABCA.QA"]
+	var ustn = mspaConsentFixtures[iabconsent.UsTennesseeSID]["Bqqqqqo"]
+	var parsed = map[int]iabconsent.GppParsedConsent{
+		iabconsent.UsNationalSID:  usnat,
+		iabconsent.UsTennesseeSID: ustn,
+	}
+
+	// Before TIPA took effect, Tennessee traffic is governed by usnat.
+	var sid, consent, found = iabconsent.ApplicableMspaSectionAt("TN", nil, parsed,
+		time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	c.Check(found, check.Equals, true)
+	c.Check(sid, check.Equals, iabconsent.UsNationalSID)
+	c.Check(consent, check.DeepEquals, usnat)
+
+	sid, consent, found = iabconsent.ApplicableMspaSectionAt("TN", nil, parsed,
+		time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC))
+	c.Check(found, check.Equals, true)
+	c.Check(sid, check.Equals, iabconsent.UsTennesseeSID)
+	c.Check(consent, check.DeepEquals, ustn)
+}
@@ -7,6 +7,10 @@ import (
 )
 
 // V2ParsedConsent represents data extracted from an v2 TCF Consent String.
+//
+// The optional segments are nil when absent from the string, so reading the fields of the embedded
+// PublisherTCEntry panics for strings without a Publisher TC segment. Use the accessor methods, e.g.
+// PublisherPurposeConsent, which are safe for absent segments and for a nil *V2ParsedConsent.
 type V2ParsedConsent struct {
 	// Version number of the encoding format.
 	Version int
@@ -143,14 +147,14 @@ const (
 // of Vendor IDs under that Purpose restriction.
 type PubRestrictionEntry struct {
 	// The Vendor’s declared Purpose ID that the publisher has indicated that they are overriding.
-	PurposeID int
+	PurposeID int `json:"purpose_id"`
 	// The restriction type.
-	RestrictionType RestrictionType
+	RestrictionType RestrictionType `json:"restriction_type"`
 	// Number of RangeEntry sections to follow.
-	NumEntries int
+	NumEntries int `json:"num_entries"`
 	// A single or range of Vendor ID(s) who the publisher has designated as restricted under the
 	// Purpose ID in this PubRestrictionsEntry.
-	RestrictionsRange []*RangeEntry
+	RestrictionsRange []*RangeEntry `json:"restrictions_range"`
 }
 
 // PublisherTCEntry represents Publisher Purposes Transparency and Consent.
@@ -219,6 +223,19 @@ type OOBVendorList struct {
 	VendorEntries []*RangeEntry
 }
 
+// Contains returns true if VendorID |v| is in the vendor list. It returns false for
+// a nil vendor list, i.e. a segment absent from the string.
+func (o *OOBVendorList) Contains(v int) bool {
+	if o == nil {
+		return false
+	}
+	if o.IsRangeEncoding {
+		return inRangeEntries(v, o.VendorEntries)
+	}
+
+	return o.Vendors[v]
+}
+
 // SpecialFeature is an enum type for special features. The TCF Policies designates certain Features as “special” which
 // means a CMP must afford the user a means to opt in to their use. These “Special Features” are published and
 // numerically identified in the Global Vendor List separately from normal Features.
@@ -270,6 +287,9 @@ const (
 // there are any Publisher Restrictions for a given vendor or vendors
 // (which can be done with a call of p.PublisherRestricted).
 func (p *V2ParsedConsent) EveryPurposeAllowed(ps []int) bool {
+	if p == nil {
+		return false
+	}
 	for _, rp := range ps {
 		if !p.PurposesConsent[rp] {
 			return false
@@ -281,15 +301,22 @@ func (p *V2ParsedConsent) EveryPurposeAllowed(ps []int) bool {
 // PurposeAllowed returns true if the passed purpose number exists in
 // the V2ParsedConsent, otherwise false.
 func (p *V2ParsedConsent) PurposeAllowed(ps int) bool {
-	if !p.PurposesConsent[ps] {
+	if p == nil || !p.PurposesConsent[ps] {
 		return false
 	}
 	return true
 }
 
 // VendorAllowed returns true if the ParsedConsent contains affirmative consent
-// for VendorID |v|.
+// for VendorID |v|. From TCF v2.3, a vendor must also have been disclosed to
+// the user, see VendorDisclosed.
 func (p *V2ParsedConsent) VendorAllowed(v int) bool {
+	if p == nil {
+		return false
+	}
+	if p.requiresDisclosure() && !p.VendorDisclosed(v) {
+		return false
+	}
 	if p.IsConsentRangeEncoding {
 		return inRangeEntries(v, p.ConsentedVendorsRange)
 	}
@@ -297,9 +324,98 @@ func (p *V2ParsedConsent) VendorAllowed(v int) bool {
 	return p.ConsentedVendors[v]
 }
 
+// VendorLegitimateInterest returns true if the ParsedConsent establishes
+// transparency for the legitimate interest of VendorID |v|, and the user has not
+// objected to it. From TCF v2.3, a 0 bit only signals an objection if the vendor
+// was disclosed to the user, so undisclosed vendors always return false.
+func (p *V2ParsedConsent) VendorLegitimateInterest(v int) bool {
+	if p == nil {
+		return false
+	}
+	if p.requiresDisclosure() && !p.VendorDisclosed(v) {
+		return false
+	}
+	if p.IsInterestsRangeEncoding {
+		return inRangeEntries(v, p.InterestsVendorsRange)
+	}
+
+	return p.InterestsVendors[v]
+}
+
+// VendorDisclosed returns true if VendorID |v| is in the DisclosedVendors segment,
+// i.e. the vendor was disclosed to the user by the CMP. It returns false if the
+// string has no DisclosedVendors segment.
+func (p *V2ParsedConsent) VendorDisclosed(v int) bool {
+	if p == nil {
+		return false
+	}
+	return p.OOBDisclosedVendors.Contains(v)
+}
+
+// VendorOOBAllowed returns true if VendorID |v| is in the AllowedVendors segment,
+// i.e. the publisher permits the vendor to use OOB legal bases. It returns false
+// if the string has no AllowedVendors segment.
+func (p *V2ParsedConsent) VendorOOBAllowed(v int) bool {
+	if p == nil {
+		return false
+	}
+	return p.OOBAllowedVendors.Contains(v)
+}
+
+// PublisherPurposeConsent returns true if the Publisher TC segment holds the user's
+// consent to purpose |purpose| for the publisher. It returns false if the string has
+// no Publisher TC segment.
+func (p *V2ParsedConsent) PublisherPurposeConsent(purpose int) bool {
+	if p == nil || p.PublisherTCEntry == nil {
+		return false
+	}
+	return p.PubPurposesConsent[purpose]
+}
+
+// PublisherPurposeLITransparency returns true if the Publisher TC segment establishes
+// transparency for the publisher's legitimate interest in purpose |purpose|. It returns
+// false if the string has no Publisher TC segment.
+func (p *V2ParsedConsent) PublisherPurposeLITransparency(purpose int) bool {
+	if p == nil || p.PublisherTCEntry == nil {
+		return false
+	}
+	return p.PubPurposesLITransparency[purpose]
+}
+
+// CustomPurposeConsent returns true if the Publisher TC segment holds the user's
+// consent to the publisher's custom purpose |purpose|. It returns false if the
+// string has no Publisher TC segment.
+func (p *V2ParsedConsent) CustomPurposeConsent(purpose int) bool {
+	if p == nil || p.PublisherTCEntry == nil {
+		return false
+	}
+	return p.CustomPurposesConsent[purpose]
+}
+
+// CustomPurposeLITransparency returns true if the Publisher TC segment establishes
+// transparency for the publisher's legitimate interest in its custom purpose |purpose|.
+// It returns false if the string has no Publisher TC segment.
+func (p *V2ParsedConsent) CustomPurposeLITransparency(purpose int) bool {
+	if p == nil || p.PublisherTCEntry == nil {
+		return false
+	}
+	return p.CustomPurposesLITransparency[purpose]
+}
+
+// requiresDisclosure returns true if the string has TCF Policy Version 5 (TCF v2.3) or
+// higher, which requires the DisclosedVendors segment, and only applies the vendor
+// consent and legitimate interest signals of disclosed vendors. Policy versions above 5
+// are not yet defined, and are assumed to keep the requirement.
+func (p *V2ParsedConsent) requiresDisclosure() bool {
+	return p != nil && p.TCFPolicyVersion >= 5
+}
+
 // PublisherRestricted returns true if any purpose in |ps| is
 // Flatly Not Allowed and |v| is covered by that restriction.
 func (p *V2ParsedConsent) PublisherRestricted(ps []int, v int) bool {
+	if p == nil {
+		return false
+	}
 	// Map-ify ps for use in checking pub restrictions.
 	var pm = make(map[int]bool)
 	for _, p := range ps {
@@ -308,7 +424,7 @@ func (p *V2ParsedConsent) PublisherRestricted(ps []int, v int) bool {
 
 	if p.NumPubRestrictions > 0 {
 		for _, re := range p.PubRestrictionEntries {
-			if pm[re.PurposeID] &&
+			if re != nil && pm[re.PurposeID] &&
 				re.RestrictionType == PurposeFlatlyNotAllowed &&
 				inRangeEntries(v, re.RestrictionsRange) {
 
@@ -322,7 +438,7 @@ func (p *V2ParsedConsent) PublisherRestricted(ps []int, v int) bool {
 // inRangeEntries returns whether |v| is found within |entries|.
 func inRangeEntries(v int, entries []*RangeEntry) bool {
 	for _, re := range entries {
-		if re.StartVendorID <= v && v <= re.EndVendorID {
+		if re != nil && re.StartVendorID <= v && v <= re.EndVendorID {
 			return true
 		}
 	}
@@ -345,6 +461,9 @@ func (p *V2ParsedConsent) SuitableToProcess(ps []int, v int) bool {
 // apply the highest supported minor version when parsing, but the user as the ability to call
 // MinorVersion() on its own to decide what to do if TCF Policy Version is higher.
 func (p *V2ParsedConsent) MinorVersion() (int, error) {
+	if p == nil {
+		return 0, nil
+	}
 	switch p.TCFPolicyVersion {
 	case 0, 1, 2:
 		return 0, nil
@@ -354,7 +473,28 @@ func (p *V2ParsedConsent) MinorVersion() (int, error) {
 	// Any TCF v2 string that has TCFPolicyVersion of 4 indicates v2.2.
 	case 4:
 		return 2, nil
+	// Any TCF v2 string that has TCFPolicyVersion of 5 indicates v2.3.
+	case 5:
+		return 3, nil
 	default:
 		return 100, errors.Errorf("Unsupported TCFPolicyVersion %d", p.TCFPolicyVersion)
 	}
 }
+
+// SectionID returns TcfEuV2SID, the GPP Section ID of EU TCF v2.
+func (p *V2ParsedConsent) SectionID() int {
+	return TcfEuV2SID
+}
+
+// SectionName returns "tcfeuv2", the GPP API prefix of EU TCF v2.
+func (p *V2ParsedConsent) SectionName() string {
+	return GppSectionName(TcfEuV2SID)
+}
+
+// SectionVersion returns the encoding version of the TC String.
+func (p *V2ParsedConsent) SectionVersion() int {
+	if p == nil {
+		return 0
+	}
+	return p.Version
+}
@@ -8,6 +8,24 @@ import (
 
 var v2TestTime = time.Unix(1583436280, 9*nsPerDs).UTC()
 
+// v2BaseConsent returns a globally-scoped TCF v2.2 consent created and last updated on 2020-01-01,
+// which consents to purpose 1 and to no vendors. Tests derive the consents they need from it.
+func v2BaseConsent() *iabconsent.V2ParsedConsent {
+	var created = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	return &iabconsent.V2ParsedConsent{
+		Version:          2,
+		Created:          created,
+		LastUpdated:      created,
+		CMPID:            123,
+		ConsentLanguage:  "EN",
+		TCFPolicyVersion: 4,
+		PurposesConsent:  map[int]bool{1: true},
+		PublisherCC:      "FR",
+		ConsentedVendors: map[int]bool{},
+		InterestsVendors: map[int]bool{},
+	}
+}
+
 // Consent fixtures have been generated using the tool at https://iabtcf.com/#/encode.
 // To inspect a consent string used in the test you can enter it into: https://iabtcf.com/#/decode.
 
@@ -458,7 +476,7 @@ var v2ConsentFixtures = map[string]*iabconsent.V2ParsedConsent{
 		},
 		PublisherTCEntry: nil,
 	},
-	// TCF > v2.2, with PurposesLit 2 and 7 True.
+	// Valid TCF v2.3, with PurposesLit 2 and 7 True.
 	"COvzTO5OvzTO5B7ABCENAPFYAIAAAEIAAIqIAAoAAoAA.QAAo.IAAo": {
 		Version:              2,
 		Created:              v2TestTime,
@@ -521,6 +539,10 @@ var v2InvalidConsentFixtures = map[string]string{
 	"COvwooAOvwooAB7ABCENAPEYAIAAAAgAAIqIAAoAAoAA.QAAo.IAAo": "TCF String Version 2.2 or higher has invalid PurposesLIT 5 not set to 0.",
 	// Policy Version 4, PurposesLit 6 True.
 	"COvwooAOvwooAB7ABCENAPEYAIAAAAQAAIqIAAoAAoAA.QAAo.IAAo": "TCF String Version 2.2 or higher has invalid PurposesLIT 6 not set to 0.",
-	// Policy Version 5, still checking for LIT 6.
-	"COvwooAOvwooAB7ABCENAPFYAIAAAAQAAIqIAAoAAoAA.QAAo.IAAo": "TCF String Version 2.2 or higher has invalid PurposesLIT 6 not set to 0.",
+	// Policy Version 6, still checking for LIT 6.
+	"COvwooAOvwooAB7ABCENAPGYAIAAAAQAAIqIAAoAAoAA.QAAo.IAAo": "TCF String Version 2.2 or higher has invalid PurposesLIT 6 not set to 0.",
+	// Policy Version 5, without a DisclosedVendors segment.
+	"COvzTO5OvzTO5B7ABCENAPFYAIAAAIAAAIqIAAoAAoAA.QAAo": "TCF Policy Version 5 string is missing the DisclosedVendors segment.",
+	// Policy Version 6, without a DisclosedVendors segment.
+	"COvzTO5OvzTO5B7ABCENAPGYAIAAAIAAAIqIAAoAAoAA.QAAo": "TCF Policy Version 6 string is missing the DisclosedVendors segment.",
 }
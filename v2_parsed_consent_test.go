@@ -219,6 +219,67 @@ func (v *V2ParsedConsentSuite) TestVendorAllowed(c *check.C) {
 	}
 }
 
+func (v *V2ParsedConsentSuite) TestVendorDisclosure(c *check.C) {
+	var disclosed = &iabconsent.OOBVendorList{
+		SegmentType:     iabconsent.DisclosedVendors,
+		MaxVendorID:     20,
+		IsRangeEncoding: true,
+		NumEntries:      1,
+		VendorEntries:   []*iabconsent.RangeEntry{{StartVendorID: 10, EndVendorID: 20}},
+	}
+	var tcs = []struct {
+		desc          string
+		policyVersion int
+		disclosed     *iabconsent.OOBVendorList
+		vendor        int
+		expDisclosed  bool
+		expAllowed    bool
+		expLI         bool
+	}{
+		{
+			desc:          "TCF v2.2 does not require disclosure.",
+			policyVersion: 4,
+			vendor:        5,
+			expAllowed:    true,
+			expLI:         true,
+		},
+		{
+			desc:          "TCF v2.3, undisclosed vendor.",
+			policyVersion: 5,
+			disclosed:     disclosed,
+			vendor:        5,
+		},
+		{
+			desc:          "TCF v2.3, disclosed vendor.",
+			policyVersion: 5,
+			disclosed:     disclosed,
+			vendor:        15,
+			expDisclosed:  true,
+			expAllowed:    true,
+			expLI:         true,
+		},
+		{
+			desc:          "TCF v2.3, disclosed vendor without consent, which objected to legitimate interest.",
+			policyVersion: 5,
+			disclosed:     disclosed,
+			vendor:        20,
+			expDisclosed:  true,
+		},
+	}
+	for _, tc := range tcs {
+		c.Log(tc.desc)
+		var pc = &iabconsent.V2ParsedConsent{
+			TCFPolicyVersion:    tc.policyVersion,
+			ConsentedVendors:    map[int]bool{5: true, 15: true},
+			InterestsVendors:    map[int]bool{5: true, 15: true},
+			OOBDisclosedVendors: tc.disclosed,
+		}
+		c.Check(pc.VendorDisclosed(tc.vendor), check.Equals, tc.expDisclosed)
+		c.Check(pc.VendorAllowed(tc.vendor), check.Equals, tc.expAllowed)
+		c.Check(pc.VendorLegitimateInterest(tc.vendor), check.Equals, tc.expLI)
+	}
+}
+
 func (v *V2ParsedConsentSuite) TestPublisherRestricted(c *check.C) {
 	var tcs = []struct {
 		purposes        []int
@@ -458,9 +519,14 @@ func (v *V2ParsedConsentSuite) TestMinorVersion(c *check.C) {
 			minorVersion:  2,
 		},
 		{
+			desc:          "TCFPolicyVersion of 5",
 			consentString: "CPuy0IAPuy0IAPoABABGCyFAAAAAAAAAAAAAAAAAAAAA.QAAA.IAAA",
+			minorVersion:  3,
+		},
+		{
+			consentString: "CPuy0IAPuy0IAPoABABGCyGAAAAAAAAAAAAAAAAAAAAA.QAAA.IAAA",
 			minorVersion:  100,
-			err:           "Unsupported TCFPolicyVersion 5",
+			err:           "Unsupported TCFPolicyVersion 6",
 		},
 	}
 
@@ -482,4 +548,61 @@ func (v *V2ParsedConsentSuite) TestMinorVersion(c *check.C) {
 	}
 }
 
+func (s *V2ParsedConsentSuite) TestOptionalSegmentAccessors(c *check.C) {
+	var p, err = iabconsent.ParseV2("COvzTO5OvzTO5BRAAAENAPCoALIAADgAAAAAAewAwABAAlAB6ABBFAAA")
+	c.Assert(err, check.IsNil)
+	c.Assert(p.PublisherTCEntry, check.IsNil)
+	c.Check(p.PublisherPurposeConsent(1), check.Equals, false)
+	c.Check(p.PublisherPurposeLITransparency(1), check.Equals, false)
+	c.Check(p.CustomPurposeConsent(1), check.Equals, false)
+	c.Check(p.CustomPurposeLITransparency(1), check.Equals, false)
+	c.Check(p.VendorOOBAllowed(2), check.Equals, false)
+
+	p.PublisherTCEntry = &iabconsent.PublisherTCEntry{
+		SegmentType:                  iabconsent.PublisherTC,
+		PubPurposesConsent:           map[int]bool{1: true},
+		PubPurposesLITransparency:    map[int]bool{2: true},
+		NumCustomPurposes:            2,
+		CustomPurposesConsent:        map[int]bool{1: true},
+		CustomPurposesLITransparency: map[int]bool{2: true},
+	}
+	p.OOBAllowedVendors = &iabconsent.OOBVendorList{
+		SegmentType:     iabconsent.AllowedVendors,
+		MaxVendorID:     10,
+		IsRangeEncoding: true,
+		NumEntries:      1,
+		VendorEntries:   []*iabconsent.RangeEntry{{StartVendorID: 2, EndVendorID: 10}},
+	}
+	c.Check(p.PublisherPurposeConsent(1), check.Equals, true)
+	c.Check(p.PublisherPurposeLITransparency(2), check.Equals, true)
+	c.Check(p.CustomPurposeConsent(1), check.Equals, true)
+	c.Check(p.CustomPurposeLITransparency(1), check.Equals, false)
+	c.Check(p.VendorOOBAllowed(2), check.Equals, true)
+	c.Check(p.VendorOOBAllowed(1), check.Equals, false)
+}
+
+func (s *V2ParsedConsentSuite) TestNilAccessors(c *check.C) {
+	var p *iabconsent.V2ParsedConsent
+	c.Check(p.EveryPurposeAllowed([]int{1}), check.Equals, false)
+	c.Check(p.PurposeAllowed(1), check.Equals, false)
+	c.Check(p.VendorAllowed(1), check.Equals, false)
+	c.Check(p.VendorLegitimateInterest(1), check.Equals, false)
+	c.Check(p.VendorDisclosed(1), check.Equals, false)
+	c.Check(p.VendorOOBAllowed(1), check.Equals, false)
+	c.Check(p.PublisherPurposeConsent(1), check.Equals, false)
+	c.Check(p.PublisherRestricted([]int{1}, 1), check.Equals, false)
+	c.Check(p.SuitableToProcess([]int{1}, 1), check.Equals, false)
+	c.Check(p.Explain([]int{1}, 1), check.HasLen, 2)
+	c.Check(p.Decide(iabconsent.ProcessingRequest{Purposes: []int{1}, VendorID: 1}), check.Equals, iabconsent.DecisionDeny)
+	c.Check(p.SectionVersion(), check.Equals, 0)
+
+	var v1 *iabconsent.ParsedConsent
+	c.Check(v1.SuitableToProcess([]int{1}, 1), check.Equals, false)
+	c.Check(v1.FrameworkVersion(), check.Equals, 0)
+
+	var m *iabconsent.MspaParsedConsent
+	c.Check(m.Decide(iabconsent.ProcessingRequest{Sale: true}), check.Equals, iabconsent.DecisionDeny)
+	c.Check(m.SectionName(), check.Equals, "")
+}
+
 var _ = check.Suite(&V2ParsedConsentSuite{})
@@ -0,0 +1,299 @@
+package iabconsent
+
+import (
+	"encoding/base64"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ConsentWriter provides Consent String-specific bit-writing functionality,
+// and is the inverse of ConsentReader. As with ConsentReader, once a write
+// fails every following write is skipped and Err holds the first error.
+type ConsentWriter struct {
+	buf []byte
+	n   uint
+	// The first error returned by a write.
+	Err error
+}
+
+// NewConsentWriter returns an empty ConsentWriter.
+func NewConsentWriter() *ConsentWriter {
+	return &ConsentWriter{}
+}
+
+// Len returns the number of bits written.
+func (w *ConsentWriter) Len() uint {
+	return w.n
+}
+
+// Bytes returns the bits written, zero-padded to a whole number of bytes.
+func (w *ConsentWriter) Bytes() []byte {
+	return w.buf
+}
+
+// String returns the bits written as a base64 Raw URL Encoded string, zero-padding the last
+// 6-bit group.
+func (w *ConsentWriter) String() string {
+	var s = base64.RawURLEncoding.EncodeToString(w.buf)
+	// Bytes are padded to 8 bits, but characters only need the bits rounded up to 6.
+	return s[:(w.n+5)/6]
+}
+
+// fail records err as the first error, and returns it.
+func (w *ConsentWriter) fail(err error) error {
+	if w.Err == nil {
+		w.Err = err
+	}
+	return w.Err
+}
+
+// WriteBits writes the n least significant bits of v, most significant first.
+func (w *ConsentWriter) WriteBits(v uint64, n uint) error {
+	if w.Err != nil {
+		return w.Err
+	}
+	if n > 64 {
+		return w.fail(errors.Errorf("write bits: length %d greater than 64", n))
+	}
+	for i := n; i > 0; i-- {
+		if w.n%8 == 0 {
+			w.buf = append(w.buf, 0)
+		}
+		if v>>(i-1)&1 == 1 {
+			w.buf[w.n/8] |= 0x80 >> (w.n % 8)
+		}
+		w.n++
+	}
+	return nil
+}
+
+// WriteInt writes v as an n bit unsigned integer.
+func (w *ConsentWriter) WriteInt(v int, n uint) error {
+	if v < 0 || (n < 64 && uint64(v) >= 1<<n) {
+		return w.fail(errors.Errorf("write int: %d does not fit in %d bits", v, n))
+	}
+	return w.WriteBits(uint64(v), n)
+}
+
+// WriteBool writes b as a single bit.
+func (w *ConsentWriter) WriteBool(b bool) error {
+	if b {
+		return w.WriteBits(1, 1)
+	}
+	return w.WriteBits(0, 1)
+}
+
+// Pad writes zero bits until Len is l. It fails if more than l bits have been written.
+func (w *ConsentWriter) Pad(l uint) error {
+	if w.n > l {
+		return w.fail(errors.Errorf("pad: %d bits written, greater than %d", w.n, l))
+	}
+	for w.n < l && w.Err == nil {
+		w.WriteBits(0, 1)
+	}
+	return w.Err
+}
+
+// WriteFibonacciInt writes v, which must be positive, using Fibonacci Encoding.
+// It is the inverse of ReadFibonacciInt.
+func (w *ConsentWriter) WriteFibonacciInt(v int) error {
+	if v < 1 {
+		return w.fail(errors.Errorf("write fibonacci int: %d is not positive", v))
+	}
+	// Fibonacci values from index 2, i.e. 1, 2, 3, 5, 8, ...
+	var fibs = []int{1, 2}
+	for fibs[len(fibs)-1] <= v {
+		fibs = append(fibs, fibs[len(fibs)-1]+fibs[len(fibs)-2])
+	}
+	// Greedily take the largest values (the Zeckendorf representation).
+	var bits = make([]bool, len(fibs)-1)
+	for i, rem := len(bits)-1, v; i >= 0 && rem > 0; i-- {
+		if fibs[i] <= rem {
+			bits[i] = true
+			rem -= fibs[i]
+		}
+	}
+	// Trim unused high values, which are always set to 0.
+	for !bits[len(bits)-1] {
+		bits = bits[:len(bits)-1]
+	}
+	for _, b := range bits {
+		w.WriteBool(b)
+	}
+	// Terminate with a second consecutive 1.
+	return w.WriteBool(true)
+}
+
+// WriteTime writes t as 36 bits of epoch deciseconds.
+func (w *ConsentWriter) WriteTime(t time.Time) error {
+	var ds = t.UnixNano() / nsPerDs
+	if t.Before(time.Unix(0, 0)) {
+		return w.fail(errors.Errorf("write time: %s is before the epoch", t))
+	}
+	return w.WriteInt(int(ds), 36)
+}
+
+// WriteString writes s, which must have length n, as 6 bits per letter.
+func (w *ConsentWriter) WriteString(s string, n uint) error {
+	if uint(len(s)) != n {
+		return w.fail(errors.Errorf("write string: %q is not %d letters", s, n))
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return w.fail(errors.Errorf("write string: invalid letter %q", s[i]))
+		}
+		w.WriteBits(uint64(s[i]-'A'), 6)
+	}
+	return w.Err
+}
+
+// WriteBitField writes n bits, where the i-th bit is set iff m[i+1].
+// It is the inverse of ReadBitField, and fails if m sets an ID greater than n.
+func (w *ConsentWriter) WriteBitField(m map[int]bool, n uint) error {
+	for id, set := range m {
+		if set && (id < 1 || id > int(n)) {
+			return w.fail(errors.Errorf("write bit field: id %d not in range 1-%d", id, n))
+		}
+	}
+	for i := 1; i <= int(n); i++ {
+		w.WriteBool(m[i])
+	}
+	return w.Err
+}
+
+// WriteNBitField writes l values of n bits, the i-th value being m[i].
+// It is the inverse of ReadNBitField.
+func (w *ConsentWriter) WriteNBitField(m map[int]int, n, l uint) error {
+	for i := range m {
+		if i < 0 || i >= int(l) {
+			return w.fail(errors.Errorf("write n-bitfield: index %d not in range 0-%d", i, int(l)-1))
+		}
+	}
+	for i := 0; i < int(l); i++ {
+		w.WriteInt(m[i], n)
+	}
+	return w.Err
+}
+
+// WriteRangeEntries writes entries in the format read by ReadRangeEntries.
+// The number of entries must be written separately.
+func (w *ConsentWriter) WriteRangeEntries(entries []*RangeEntry) error {
+	for _, e := range entries {
+		if e == nil {
+			return w.fail(errors.New("write range entries: nil entry"))
+		}
+		if e.EndVendorID < e.StartVendorID {
+			return w.fail(errors.Errorf("write range entries: end %d before start %d", e.EndVendorID, e.StartVendorID))
+		}
+		var isRange = e.StartVendorID != e.EndVendorID
+		w.WriteBool(isRange)
+		w.WriteInt(e.StartVendorID, 16)
+		if isRange {
+			w.WriteInt(e.EndVendorID, 16)
+		}
+	}
+	return w.Err
+}
+
+// WriteFibonacciRange writes ids in the format read by ReadFibonacciRange, grouping
+// consecutive IDs. ids must be positive, and are written in ascending order.
+func (w *ConsentWriter) WriteFibonacciRange(ids []int) error {
+	var sorted = append([]int(nil), ids...)
+	sort.Ints(sorted)
+
+	// Group consecutive IDs, as [start, end] pairs.
+	var groups [][2]int
+	for i, id := range sorted {
+		if i > 0 && id == sorted[i-1] {
+			return w.fail(errors.Errorf("write fibonacci range: duplicate id %d", id))
+		}
+		if len(groups) > 0 && groups[len(groups)-1][1] == id-1 {
+			groups[len(groups)-1][1] = id
+		} else {
+			groups = append(groups, [2]int{id, id})
+		}
+	}
+
+	w.WriteInt(len(groups), 12)
+	var lastSeen = 0
+	for _, g := range groups {
+		w.WriteBool(g[0] != g[1])
+		w.WriteFibonacciInt(g[0] - lastSeen)
+		if g[0] != g[1] {
+			w.WriteFibonacciInt(g[1] - g[0])
+		}
+		lastSeen = g[1]
+	}
+	return w.Err
+}
+
+// WritePubRestrictionEntries writes entries in the format read by ReadPubRestrictionEntries.
+// The number of entries must be written separately.
+func (w *ConsentWriter) WritePubRestrictionEntries(entries []*PubRestrictionEntry) error {
+	for _, e := range entries {
+		if e == nil {
+			return w.fail(errors.New("write pub restriction entries: nil entry"))
+		}
+		w.WriteInt(e.PurposeID, 6)
+		w.WriteInt(int(e.RestrictionType), 2)
+		w.WriteInt(len(e.RestrictionsRange), 12)
+		w.WriteRangeEntries(e.RestrictionsRange)
+	}
+	return w.Err
+}
+
+// WriteVendors writes the segment type and vendors of a DisclosedVendors or AllowedVendors segment.
+func (w *ConsentWriter) WriteVendors(v *OOBVendorList) error {
+	w.WriteInt(int(v.SegmentType), 3)
+	w.WriteInt(v.MaxVendorID, 16)
+	w.WriteBool(v.IsRangeEncoding)
+	if v.IsRangeEncoding {
+		w.WriteInt(len(v.VendorEntries), 12)
+		w.WriteRangeEntries(v.VendorEntries)
+	} else {
+		w.WriteBitField(v.Vendors, uint(v.MaxVendorID))
+	}
+	return w.Err
+}
+
+// WritePublisherTCEntry writes the segment type and fields of a Publisher TC segment.
+func (w *ConsentWriter) WritePublisherTCEntry(ptc *PublisherTCEntry) error {
+	w.WriteInt(int(PublisherTC), 3)
+	w.WriteBitField(ptc.PubPurposesConsent, 24)
+	w.WriteBitField(ptc.PubPurposesLITransparency, 24)
+	w.WriteInt(ptc.NumCustomPurposes, 6)
+	w.WriteBitField(ptc.CustomPurposesConsent, uint(ptc.NumCustomPurposes))
+	w.WriteBitField(ptc.CustomPurposesLITransparency, uint(ptc.NumCustomPurposes))
+	return w.Err
+}
+
+// WriteMspaField writes the value of field f of p, and is the inverse of ReadMspaField.
+// Bitfield fields write l values.
+func (w *ConsentWriter) WriteMspaField(p *MspaParsedConsent, f MspaField, l uint) error {
+	switch v := p.fieldPtr(f).(type) {
+	case *MspaNotice:
+		return w.WriteInt(int(*v), 2)
+	case *MspaOptout:
+		return w.WriteInt(int(*v), 2)
+	case *MspaConsent:
+		return w.WriteInt(int(*v), 2)
+	case *MspaNaYesNo:
+		return w.WriteInt(int(*v), 2)
+	case *map[int]MspaConsent:
+		var m = make(map[int]int, len(*v))
+		for i, c := range *v {
+			m[i] = int(c)
+		}
+		return w.WriteNBitField(m, 2, l)
+	case *map[int]MspaOptout:
+		var m = make(map[int]int, len(*v))
+		for i, o := range *v {
+			m[i] = int(o)
+		}
+		return w.WriteNBitField(m, 2, l)
+	default:
+		return w.fail(errors.Errorf("unknown mspa field %d", int(f)))
+	}
+}
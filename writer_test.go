@@ -0,0 +1,101 @@
+package iabconsent_test
+
+import (
+	"time"
+
+	"github.com/go-check/check"
+
+	"github.com/LiveRamp/iabconsent"
+)
+
+type WriterSuite struct{}
+
+var _ = check.Suite(&WriterSuite{})
+
+func (s *WriterSuite) TestWriteBits(c *check.C) {
+	var w = iabconsent.NewConsentWriter()
+	c.Check(w.WriteInt(3, 6), check.IsNil)
+	c.Check(w.WriteBool(true), check.IsNil)
+	c.Check(w.WriteInt(0x1ff, 9), check.IsNil)
+	c.Check(w.Len(), check.Equals, uint(16))
+	// 000011 1 111111111
+	c.Check(w.Bytes(), check.DeepEquals, []byte{0x0f, 0xff})
+
+	c.Check(w.WriteInt(4, 2), check.ErrorMatches, "write int: 4 does not fit in 2 bits")
+	// Writes after an error are skipped, and return the first error.
+	c.Check(w.WriteBool(true), check.ErrorMatches, "write int: 4 does not fit in 2 bits")
+	c.Check(w.Len(), check.Equals, uint(16))
+}
+
+func (s *WriterSuite) TestString(c *check.C) {
+	var w = iabconsent.NewConsentWriter()
+	// 000011 000001 000000 000001 001011
+	for _, v := range []int{3, 1, 0, 1, 11} {
+		w.WriteInt(v, 6)
+	}
+	c.Check(w.String(), check.Equals, "DBABL")
+	c.Check(w.Pad(32), check.IsNil)
+	c.Check(w.String(), check.Equals, "DBABLA")
+	c.Check(w.Pad(8), check.ErrorMatches, "pad: 32 bits written, greater than 8")
+}
+
+func (s *WriterSuite) TestWriteFibonacciInt(c *check.C) {
+	for v := 1; v < 100000; v++ {
+		var w = iabconsent.NewConsentWriter()
+		c.Assert(w.WriteFibonacciInt(v), check.IsNil)
+		var r = iabconsent.NewConsentReader(w.Bytes())
+		var got, err = r.ReadFibonacciInt()
+		c.Assert(err, check.IsNil)
+		c.Assert(got, check.Equals, v)
+	}
+	c.Check(iabconsent.NewConsentWriter().WriteFibonacciInt(0), check.ErrorMatches, "write fibonacci int: 0 is not positive")
+}
+
+func (s *WriterSuite) TestWriteFibonacciRange(c *check.C) {
+	var tcs = [][]int{
+		{2},
+		{2, 6},
+		{7, 8, 9, 10, 11, 12},
+		{7, 9, 10, 11, 22},
+		{1, 3, 5, 6, 7, 20, 21},
+	}
+	for _, ids := range tcs {
+		var w = iabconsent.NewConsentWriter()
+		c.Assert(w.WriteFibonacciRange(ids), check.IsNil)
+		var got, err = iabconsent.NewConsentReader(w.Bytes()).ReadFibonacciRange()
+		c.Check(err, check.IsNil)
+		c.Check(got, check.DeepEquals, ids)
+	}
+	c.Check(iabconsent.NewConsentWriter().WriteFibonacciRange([]int{2, 2}), check.ErrorMatches,
+		"write fibonacci range: duplicate id 2")
+}
+
+func (s *WriterSuite) TestWriteFields(c *check.C) {
+	var created = time.Date(2020, 3, 5, 19, 24, 40, 900000000, time.UTC)
+	var w = iabconsent.NewConsentWriter()
+	w.WriteTime(created)
+	w.WriteString("EN", 2)
+	w.WriteBitField(map[int]bool{1: true, 3: true, 4: false}, 5)
+	w.WriteRangeEntries([]*iabconsent.RangeEntry{{StartVendorID: 2, EndVendorID: 2}, {StartVendorID: 5, EndVendorID: 9}})
+	c.Assert(w.Err, check.IsNil)
+
+	var r = iabconsent.NewConsentReader(w.Bytes())
+	var t, _ = r.ReadTime()
+	c.Check(t, check.Equals, created)
+	var l, _ = r.ReadString(2)
+	c.Check(l, check.Equals, "EN")
+	var bf, _ = r.ReadBitField(5)
+	c.Check(bf, check.DeepEquals, map[int]bool{1: true, 3: true})
+	var re, _ = r.ReadRangeEntries(2)
+	c.Check(re, check.DeepEquals, []*iabconsent.RangeEntry{{StartVendorID: 2, EndVendorID: 2}, {StartVendorID: 5, EndVendorID: 9}})
+	c.Check(r.Err, check.IsNil)
+
+	c.Check(iabconsent.NewConsentWriter().WriteString("en", 2), check.ErrorMatches, `write string: invalid letter 'e'`)
+	c.Check(iabconsent.NewConsentWriter().WriteBitField(map[int]bool{6: true}, 5), check.ErrorMatches,
+		"write bit field: id 6 not in range 1-5")
+	c.Check(iabconsent.NewConsentWriter().WriteTime(time.Time{}), check.ErrorMatches, "write time: .* is before the epoch")
+	c.Check(iabconsent.NewConsentWriter().WriteRangeEntries([]*iabconsent.RangeEntry{nil}), check.ErrorMatches,
+		"write range entries: nil entry")
+	c.Check(iabconsent.NewConsentWriter().WritePubRestrictionEntries([]*iabconsent.PubRestrictionEntry{nil}), check.ErrorMatches,
+		"write pub restriction entries: nil entry")
+}